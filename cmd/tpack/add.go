@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/registry"
+	"github.com/tmuxpack/tpack/internal/tmux"
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add <plugin>",
+	Short: "Declare a plugin in tmux.conf and install it",
+	Long: `Add a @plugin declaration next to the ones already in your config and
+install the plugin right away. The plugin is given as it would be declared,
+e.g. "tmux-plugins/tmux-yank" or a git URL.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRegistrySpecs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		branch, _ := cmd.Flags().GetString("branch")
+		alias, _ := cmd.Flags().GetString("alias")
+
+		raw, err := addSpec(args[0], branch, alias)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack:", err)
+			return errSilent
+		}
+
+		runner := tmux.NewRealRunner()
+		cfg, err := config.Resolve(runner)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+			return errSilent
+		}
+
+		plugins, err := gatherPlugins(runner, cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+			return errSilent
+		}
+
+		p := plug.ParseSpec(raw)
+		for _, d := range plugins {
+			if d.Name == p.Name && d.Raw != raw {
+				fmt.Fprintf(os.Stderr, "tpack: a plugin named %q is already declared in %s\n", p.Name, d.Origin())
+				return errSilent
+			}
+		}
+
+		file := config.PluginsFile(plugins, cfg.TmuxConf)
+		if err := config.AppendPlugin(file, raw, cfg.EditOptions()...); err != nil {
+			fmt.Fprintln(os.Stderr, "tpack:", err)
+			return errSilent
+		}
+
+		output := newOutput(false, runner)
+		output.Ok("Added \"" + p.Name + "\" to " + file)
+
+		mgr := newManagerDeps(cfg, output)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		plugins, _ = gatherPlugins(runner, cfg)
+		mgr.InstallNamed(ctx, plugins, []string{p.Name})
+
+		if output.HasFailed() {
+			return errSilent
+		}
+		return nil
+	},
+}
+
+func init() {
+	addCmd.Flags().String("branch", "", "branch to check out instead of the default")
+	addCmd.Flags().String("alias", "", "name to install the plugin under")
+}
+
+// addSpec builds the declared spec for `tpack add` from its argument and
+// the --branch and --alias flags.
+func addSpec(spec, branch, alias string) (string, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || strings.ContainsAny(spec, " \t") {
+		return "", fmt.Errorf("invalid plugin %q", spec)
+	}
+	if strings.ContainsAny(branch+alias, " \t#") {
+		return "", fmt.Errorf("--branch and --alias must be single words")
+	}
+	if branch != "" {
+		if strings.Contains(spec, "#") {
+			return "", fmt.Errorf("%q already names a branch", spec)
+		}
+		spec += "#" + branch
+	}
+	if alias != "" {
+		spec += " alias=" + alias
+	}
+	return spec, nil
+}
+
+// completeRegistrySpecs completes `tpack add` with the registry's plugins
+// that aren't declared yet, described by their registry entry.
+func completeRegistrySpecs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	runner := tmux.NewRealRunner()
+	cfg, err := config.Resolve(runner)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	reg, err := registry.Fetch(ctx, registry.DefaultRegistryURL, cfg.StatePath, registry.DefaultCacheTTL)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	declared := make(map[string]bool)
+	plugins, _ := gatherPlugins(runner, cfg)
+	for _, p := range plugins {
+		declared[p.Name] = true
+	}
+	return registryCompletions(reg, declared), cobra.ShellCompDirectiveNoFileComp
+}
+
+// registryCompletions returns a "spec\tdescription" completion for every
+// registry plugin whose name isn't in declared.
+func registryCompletions(reg *registry.Registry, declared map[string]bool) []string {
+	var specs []string
+	for _, item := range reg.Plugins {
+		if declared[plug.PluginName(item.Repo)] {
+			continue
+		}
+		specs = append(specs, item.Spec()+"\t"+item.Description)
+	}
+	return specs
+}
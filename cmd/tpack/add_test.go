@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/registry"
+)
+
+func TestAddSpec(t *testing.T) {
+	tests := []struct {
+		spec, branch, alias string
+		want                string
+		wantErr             bool
+	}{
+		{spec: "tmux-plugins/tmux-yank", want: "tmux-plugins/tmux-yank"},
+		{spec: "catppuccin/tmux", branch: "v2", alias: "theme", want: "catppuccin/tmux#v2 alias=theme"},
+		{spec: "catppuccin/tmux@^2", alias: "theme", want: "catppuccin/tmux@^2 alias=theme"},
+		{spec: "catppuccin/tmux#main", branch: "v2", wantErr: true},
+		{spec: "catppuccin/tmux", alias: "my theme", wantErr: true},
+		{spec: "a b", wantErr: true},
+		{spec: " ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := addSpec(tt.spec, tt.branch, tt.alias)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("addSpec(%q, %q, %q) error = %v, wantErr %v", tt.spec, tt.branch, tt.alias, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("addSpec(%q, %q, %q) = %q, want %q", tt.spec, tt.branch, tt.alias, got, tt.want)
+		}
+		if p := plug.ParseSpec(got); err == nil && tt.alias != "" && p.Name != tt.alias {
+			t.Errorf("spec %q parses to name %q, want %q", got, p.Name, tt.alias)
+		}
+	}
+}
+
+func TestRegistryCompletions(t *testing.T) {
+	reg := &registry.Registry{Plugins: []registry.RegistryItem{
+		{Repo: "tmux-plugins/tmux-yank", Description: "Copy to the system clipboard"},
+		{Repo: "catppuccin/tmux", Description: "Soothing pastel theme"},
+		{Repo: "user/plugin", Host: "codeberg.org", Description: "Elsewhere"},
+	}}
+
+	got := registryCompletions(reg, map[string]bool{"tmux-yank": true})
+	want := []string{
+		"catppuccin/tmux\tSoothing pastel theme",
+		"https://codeberg.org/user/plugin\tElsewhere",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestInstalledCompletions(t *testing.T) {
+	pluginPath := t.TempDir()
+	for _, name := range []string{"tmux-sensible", "tmux-old"} {
+		if err := os.MkdirAll(filepath.Join(pluginPath, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	plugins := []plug.Plugin{{Name: "tmux-sensible"}, {Name: "tmux-yank"}}
+
+	got := installedCompletions(plugins, pluginPath, []string{"tmux-yank"})
+	want := []string{"tmux-sensible\t@tpm_plugins", "tmux-old\tnot declared"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestPluginCompletions(t *testing.T) {
+	plugins := []plug.Plugin{
+		{Name: "tmux-sensible", SourceFile: "/home/user/.tmux.conf", SourceLine: 3},
+		{Name: "tmux-yank"},
+	}
+
+	got := pluginCompletions(plugins, []string{"tmux-sensible"})
+	if want := []string{"tmux-yank\t@tpm_plugins"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -6,23 +6,29 @@ import (
 	"os"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/tmuxpack/tpack/internal/config"
-	gitcli "github.com/tmuxpack/tpack/internal/git/cli"
+	"github.com/tmuxpack/tpack/internal/git"
+	gitbackend "github.com/tmuxpack/tpack/internal/git/backend"
 	"github.com/tmuxpack/tpack/internal/parallel"
 	"github.com/tmuxpack/tpack/internal/plug"
 	"github.com/tmuxpack/tpack/internal/state"
 	"github.com/tmuxpack/tpack/internal/tmux"
+	"github.com/tmuxpack/tpack/internal/ui"
 )
 
 var checkUpdatesCmd = &cobra.Command{
 	Use:   "check-updates",
 	Short: "Check if any plugins have updates available",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		code := runCheckUpdates()
+		var code int
+		if outputFormat == outputJSON {
+			code = runCheckUpdatesJSON()
+		} else {
+			code = runCheckUpdates()
+		}
 		if code != 0 {
 			return errSilent
 		}
@@ -53,9 +59,9 @@ func runCheckUpdates() int {
 	_ = state.Save(cfg.StatePath, st)
 
 	// Gather plugins from config.
-	plugins := config.GatherPlugins(runner, config.RealFS{}, cfg.TmuxConf, cfg.Home, xdgConfigHome(cfg.Home))
+	plugins, _ := gatherPlugins(runner, cfg)
 
-	outdated := findOutdatedPlugins(plugins, cfg.PluginPath)
+	outdated := findOutdatedPlugins(plugins, cfg.PluginPath, gitbackend.Select(cfg.GitBackend))
 	if len(outdated) == 0 {
 		return 0
 	}
@@ -63,6 +69,25 @@ func runCheckUpdates() int {
 	return handleOutdated(runner, cfg, plugins, outdated)
 }
 
+// runCheckUpdatesJSON checks every plugin now, regardless of the configured
+// update mode and interval, and reports the results without acting on them.
+func runCheckUpdatesJSON() int {
+	runner := tmux.NewRealRunner()
+	cfg, err := config.Resolve(runner)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+		return 1
+	}
+
+	plugins, _ := gatherPlugins(runner, cfg)
+
+	output := ui.NewJSONOutput()
+	for _, r := range checkPlugins(plugins, cfg.PluginPath, gitbackend.Select(cfg.GitBackend)) {
+		output.Result(r)
+	}
+	return exitCode(output)
+}
+
 // updateChecksEnabled reports whether the update check feature is active.
 func updateChecksEnabled(cfg *config.Config) bool {
 	if cfg.UpdateMode == "" || cfg.UpdateMode == "off" {
@@ -73,43 +98,86 @@ func updateChecksEnabled(cfg *config.Config) bool {
 
 const maxConcurrentChecks = 5
 
-// findOutdatedPlugins checks each installed plugin for available updates in parallel.
-func findOutdatedPlugins(plugins []plug.Plugin, pluginPath string) []string {
-	validator := gitcli.NewValidator()
-	fetcher := gitcli.NewFetcher()
-
-	type target struct {
-		name string
-		dir  string
-	}
-
-	var targets []target
-	for _, p := range plugins {
-		dir := plug.PluginPath(p.Name, pluginPath)
-		if validator.IsGitRepo(dir) {
-			targets = append(targets, target{name: p.Name, dir: dir})
+// checkPlugins checks each installed plugin for available updates in
+// parallel and returns one result per declared plugin, in order. Local and
+// missing plugins, and plugins pinned to a commit or an exact tag, are
+// reported as skipped.
+func checkPlugins(plugins []plug.Plugin, pluginPath string, g git.Backend) []ui.Result {
+	results := make([]ui.Result, len(plugins))
+	var targets []int
+	for i, p := range plugins {
+		results[i] = ui.Result{Name: p.Name, Action: "check", Status: ui.StatusSkipped}
+		// Local plugins are working trees the user manages themselves.
+		if p.IsLocal() {
+			continue
+		}
+		if g.Validator.IsGitRepo(plug.PluginPath(p.Name, pluginPath)) {
+			targets = append(targets, i)
 		}
 	}
 
-	var (
-		mu       sync.Mutex
-		outdated []string
-	)
-
-	parallel.Do(targets, maxConcurrentChecks, func(t target) {
+	// Each worker writes only its own index, so no locking is needed.
+	parallel.Do(targets, maxConcurrentChecks, func(i int) {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 
-		isOutdated, err := fetcher.IsOutdated(ctx, t.dir)
-		if err != nil || !isOutdated {
+		dir := plug.PluginPath(plugins[i].Name, pluginPath)
+		if plugins[i].Version != "" {
+			status, err := checkPin(ctx, plugins[i], dir, g)
+			results[i].Status = status
+			if err != nil {
+				results[i].Error = err.Error()
+			}
 			return
 		}
 
-		mu.Lock()
-		outdated = append(outdated, t.name)
-		mu.Unlock()
+		isOutdated, err := g.Fetcher.IsOutdated(ctx, dir)
+		switch {
+		case err != nil:
+			results[i].Status, results[i].Error = ui.StatusFailed, err.Error()
+		case isOutdated:
+			results[i].Status = ui.StatusOutdated
+		default:
+			results[i].Status = ui.StatusUnchanged
+		}
 	})
 
+	return results
+}
+
+// checkPin checks a plugin pinned to a version, which sits on a detached
+// HEAD rather than tracking a branch. A range pin is outdated when the
+// newest tag satisfying it is ahead of HEAD. Commit pins and exact tags
+// never move, so they are skipped.
+func checkPin(ctx context.Context, p plug.Plugin, dir string, g git.Backend) (ui.Status, error) {
+	pin, err := git.ResolvePin(ctx, g.TagLister, p.Spec, p.Version, plug.NormalizeURL)
+	if err != nil {
+		return ui.StatusFailed, err
+	}
+	if pin.Commit != "" || pin.Tag == p.Version {
+		return ui.StatusSkipped, nil
+	}
+	head, err := g.RevParser.RevParse(ctx, dir)
+	if err != nil {
+		return ui.StatusFailed, err
+	}
+	// The tag HEAD was checked out at is in the clone, so a tag missing
+	// from it was published since.
+	commits, err := g.Logger.Log(ctx, dir, head, pin.Tag)
+	if err != nil || len(commits) > 0 {
+		return ui.StatusOutdated, nil
+	}
+	return ui.StatusUnchanged, nil
+}
+
+// findOutdatedPlugins returns the names of installed plugins with updates available.
+func findOutdatedPlugins(plugins []plug.Plugin, pluginPath string, g git.Backend) []string {
+	var outdated []string
+	for _, r := range checkPlugins(plugins, pluginPath, g) {
+		if r.Status == ui.StatusOutdated {
+			outdated = append(outdated, r.Name)
+		}
+	}
 	return outdated
 }
 
@@ -130,7 +198,7 @@ func handleOutdated(runner tmux.Runner, cfg *config.Config, plugins []plug.Plugi
 // autoUpdatePlugins performs automatic updates for the given outdated plugins.
 func autoUpdatePlugins(runner tmux.Runner, cfg *config.Config, plugins []plug.Plugin, outdated []string) int {
 	output := newOutput(false, runner)
-	mgr := newManagerDeps(cfg.PluginPath, output)
+	mgr := newManagerDeps(cfg, output)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
@@ -1,13 +1,16 @@
 package main
 
 import (
+	"errors"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/git"
 	"github.com/tmuxpack/tpack/internal/plug"
 	"github.com/tmuxpack/tpack/internal/tmux"
+	"github.com/tmuxpack/tpack/internal/ui"
 )
 
 func TestUpdateChecksEnabled(t *testing.T) {
@@ -196,3 +199,90 @@ func TestHandleOutdated_AutoMode(t *testing.T) {
 		t.Errorf("handleOutdated() = %d, want 0 or 1", result)
 	}
 }
+
+func TestCheckPlugins(t *testing.T) {
+	pluginPath := "/plugins"
+	validator := git.NewMockValidator()
+	validator.Valid["/plugins/stale"] = true
+	validator.Valid["/plugins/current"] = true
+	fetcher := git.NewMockFetcher()
+	fetcher.Outdated["/plugins/stale"] = true
+	g := git.Backend{Validator: validator, Fetcher: fetcher}
+
+	plugins := []plug.Plugin{
+		{Name: "stale"},
+		{Name: "current"},
+		{Name: "missing"},
+		{Name: "dev", LocalPath: "/src/dev"},
+	}
+
+	results := checkPlugins(plugins, pluginPath, g)
+	want := []ui.Status{ui.StatusOutdated, ui.StatusUnchanged, ui.StatusSkipped, ui.StatusSkipped}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(results))
+	}
+	for i, r := range results {
+		if r.Name != plugins[i].Name || r.Action != "check" || r.Status != want[i] {
+			t.Errorf("result %d = %+v, want %s check %s", i, r, plugins[i].Name, want[i])
+		}
+	}
+
+	if got := findOutdatedPlugins(plugins, pluginPath, g); len(got) != 1 || got[0] != "stale" {
+		t.Errorf("findOutdatedPlugins = %v, want [stale]", got)
+	}
+}
+
+func TestCheckPlugins_FetchError(t *testing.T) {
+	validator := git.NewMockValidator()
+	validator.Valid["/plugins/broken"] = true
+	fetcher := git.NewMockFetcher()
+	fetcher.Err = errors.New("network unreachable")
+	g := git.Backend{Validator: validator, Fetcher: fetcher}
+
+	results := checkPlugins([]plug.Plugin{{Name: "broken"}}, "/plugins", g)
+	if len(results) != 1 || results[0].Status != ui.StatusFailed || results[0].Error != "network unreachable" {
+		t.Errorf("expected failed result with error text, got %+v", results)
+	}
+}
+
+func TestCheckPlugins_Pinned(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		commits []git.Commit
+		logErr  error
+		want    ui.Status
+	}{
+		{name: "tag", version: "v1.2.0", want: ui.StatusSkipped},
+		{name: "commit", version: "0123456789abcdef0123456789abcdef01234567", want: ui.StatusSkipped},
+		{name: "range behind", version: "^2", commits: []git.Commit{{Hash: "bbb"}}, want: ui.StatusOutdated},
+		{name: "range tag not fetched", version: "^2", logErr: errors.New("unknown revision"), want: ui.StatusOutdated},
+		{name: "range current", version: "^2", want: ui.StatusUnchanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := git.NewMockValidator()
+			validator.Valid["/plugins/pinned"] = true
+			fetcher := git.NewMockFetcher()
+			fetcher.Err = errors.New("no upstream")
+			tags := git.NewMockTagLister()
+			tags.Tags["https://git::@github.com/user/pinned"] = []string{"v1.2.0", "v2.0.0", "v2.1.0"}
+			logger := git.NewMockLogger()
+			logger.Commits, logger.Err = tt.commits, tt.logErr
+			g := git.Backend{Validator: validator, Fetcher: fetcher, TagLister: tags, RevParser: git.NewMockRevParser(), Logger: logger}
+
+			plugins := []plug.Plugin{{Name: "pinned", Spec: "user/pinned", Version: tt.version}}
+			results := checkPlugins(plugins, "/plugins", g)
+			if len(results) != 1 || results[0].Status != tt.want {
+				t.Errorf("expected %s, got %+v", tt.want, results)
+			}
+			if len(fetcher.Calls) != 0 {
+				t.Errorf("expected no upstream comparison, got calls %v", fetcher.Calls)
+			}
+			if tt.want != ui.StatusSkipped && (len(logger.Calls) != 1 || logger.Calls[0].ToRef != "v2.1.0") {
+				t.Errorf("expected HEAD to be compared with v2.1.0, got %+v", logger.Calls)
+			}
+		})
+	}
+}
@@ -29,9 +29,13 @@ var cleanCmd = &cobra.Command{
 			_ = runner.SourceFile(cfg.TmuxConf)
 		}
 
-		mgr := newManagerDeps(cfg.PluginPath, output)
+		mgr := newManagerDeps(cfg, output)
 
-		plugins := config.GatherPlugins(runner, config.RealFS{}, cfg.TmuxConf, cfg.Home, xdgConfigHome(cfg.Home))
+		plugins, err := gatherPlugins(runner, cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+			return errSilent
+		}
 
 		mgr.Clean(context.Background(), plugins)
 
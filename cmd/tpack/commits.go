@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
-	gitcli "github.com/tmuxpack/tpack/internal/git/cli"
+	"github.com/tmuxpack/tpack/internal/config"
+	gitbackend "github.com/tmuxpack/tpack/internal/git/backend"
+	"github.com/tmuxpack/tpack/internal/tmux"
 	"github.com/tmuxpack/tpack/internal/tui"
 )
 
@@ -24,7 +26,9 @@ var commitsCmd = &cobra.Command{
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		logger := gitcli.NewLogger()
+		// Only the backend option is needed, so skip full config resolution.
+		backendName, _ := tmux.NewRealRunner().ShowOption(config.GitBackendOption)
+		logger := gitbackend.Select(backendName).Logger
 		commits, err := logger.Log(ctx, dir, from, to)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, "tpack commits: git log failed:", err)
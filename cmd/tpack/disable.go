@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/state"
+	"github.com/tmuxpack/tpack/internal/tmux"
+)
+
+var disableCmd = &cobra.Command{
+	Use:   "disable <plugin>...",
+	Short: "Stop sourcing plugins without uninstalling them",
+	Long: `Turn the named plugins off: they stay installed and declared, but are not
+sourced until turned back on with tpack enable. Takes effect the next time
+tmux loads its config.`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completePluginNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSetDisabled(args, true)
+	},
+}
+
+var enableCmd = &cobra.Command{
+	Use:               "enable <plugin>...",
+	Short:             "Source plugins turned off with tpack disable again",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completePluginNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSetDisabled(args, false)
+	},
+}
+
+func runSetDisabled(names []string, disabled bool) error {
+	runner := tmux.NewRealRunner()
+	cfg, err := config.Resolve(runner)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+		return errSilent
+	}
+	plugins, err := gatherPlugins(runner, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+		return errSilent
+	}
+	if !setDisabled(os.Stdout, os.Stderr, plugins, cfg.StatePath, names, disabled) {
+		return errSilent
+	}
+	return nil
+}
+
+// setDisabled turns the named plugins off or on in the state directory,
+// reporting each to w and problems to errw. It returns false if any plugin
+// couldn't be changed.
+func setDisabled(w, errw io.Writer, plugins []plug.Plugin, statePath string, names []string, disabled bool) bool {
+	ok := true
+	var valid []string
+	for _, name := range names {
+		decls := declaredAs(plugins, name)
+		switch {
+		case len(decls) == 0:
+			fmt.Fprintf(errw, "tpack: %q is not declared\n", name)
+			ok = false
+		case !disabled && decls[0].Disabled:
+			fmt.Fprintf(errw, "tpack: %q is disabled in its declaration (%s); turn it on there\n", name, decls[0].Origin())
+			ok = false
+		default:
+			valid = append(valid, name)
+		}
+	}
+	if len(valid) == 0 {
+		return ok
+	}
+
+	if err := state.SetDisabled(statePath, disabled, valid...); err != nil {
+		fmt.Fprintln(errw, "tpack:", err)
+		return false
+	}
+	verb := "Enabled"
+	if disabled {
+		verb = "Disabled"
+	}
+	for _, name := range valid {
+		fmt.Fprintf(w, "%s %q\n", verb, name)
+	}
+	fmt.Fprintln(w, "Reload your tmux config for the change to take effect.")
+	return ok
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/state"
+)
+
+func TestSetDisabled(t *testing.T) {
+	dir := t.TempDir()
+	plugins := []plug.Plugin{
+		plug.ParseSpec("tmux-plugins/tmux-cpu"),
+		plug.ParseSpec("tmux-plugins/tmux-yank"),
+		{Name: "theme", Raw: "catppuccin/tmux disabled alias=theme", Disabled: true, SourceFile: "/home/user/.tmux.conf", SourceLine: 3},
+	}
+
+	var out, errOut bytes.Buffer
+	if !setDisabled(&out, &errOut, plugins, dir, []string{"tmux-cpu", "tmux-yank"}, true) {
+		t.Fatalf("disable failed: %s", errOut.String())
+	}
+	if s := state.Load(dir); !s.IsDisabled("tmux-cpu") || !s.IsDisabled("tmux-yank") {
+		t.Errorf("Disabled = %v", s.Disabled)
+	}
+	if !strings.Contains(out.String(), `Disabled "tmux-cpu"`) {
+		t.Errorf("output = %q", out.String())
+	}
+
+	out.Reset()
+	if setDisabled(&out, &errOut, plugins, dir, []string{"tmux-cpu", "nope", "theme"}, false) {
+		t.Error("expected enabling undeclared and config-disabled plugins to fail")
+	}
+	if s := state.Load(dir); s.IsDisabled("tmux-cpu") || !s.IsDisabled("tmux-yank") {
+		t.Errorf("Disabled = %v, want only tmux-yank", s.Disabled)
+	}
+	for _, want := range []string{`"nope" is not declared`, `"theme" is disabled in its declaration (/home/user/.tmux.conf:3)`} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("errors = %q, want %q", errOut.String(), want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/doctor"
+	gitbackend "github.com/tmuxpack/tpack/internal/git/backend"
+	"github.com/tmuxpack/tpack/internal/tmux"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose problems with tmux, git, and installed plugins",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := tmux.NewRealRunner()
+		cfg, err := config.Resolve(runner)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+			return errSilent
+		}
+
+		xdg := xdgConfigHome(cfg.Home)
+		// A manifest error is reported by its own check.
+		plugins, _ := gatherPlugins(runner, cfg)
+		checks := doctor.Run(doctor.Env{
+			Runner:        runner,
+			Config:        cfg,
+			FS:            config.RealFS{},
+			Validator:     gitbackend.Select(cfg.GitBackend).Validator,
+			Plugins:       plugins,
+			Home:          cfg.Home,
+			XDGConfigHome: xdg,
+		})
+
+		writeDoctorReport(os.Stdout, checks)
+		if doctor.Failed(checks) {
+			return errSilent
+		}
+		return nil
+	},
+}
+
+// writeDoctorReport prints one line per check, its suggested fix beneath
+// it, and a closing tally.
+func writeDoctorReport(w io.Writer, checks []doctor.Check) {
+	counts := make(map[doctor.Status]int)
+	for _, c := range checks {
+		counts[c.Status]++
+		fmt.Fprintf(w, "[%s] %s: %s\n", c.Status, c.Name, c.Message)
+		if c.Fix != "" && c.Status != doctor.Pass {
+			fmt.Fprintf(w, "       fix: %s\n", c.Fix)
+		}
+	}
+
+	summary := []string{fmt.Sprintf("%d passed", counts[doctor.Pass])}
+	if n := counts[doctor.Warn]; n > 0 {
+		summary = append(summary, fmt.Sprintf("%d %s", n, plural(n, "warning")))
+	}
+	if n := counts[doctor.Fail]; n > 0 {
+		summary = append(summary, fmt.Sprintf("%d failed", n))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.Join(summary, ", "))
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
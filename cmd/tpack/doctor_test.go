@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/doctor"
+)
+
+func TestWriteDoctorReport(t *testing.T) {
+	checks := []doctor.Check{
+		{Name: "tmux version", Message: "tmux 3.4"},
+		{Name: "plugins", Status: doctor.Warn, Message: `"tmux-yank" is not installed`, Fix: "Run `tpack install`."},
+		{Name: "git", Status: doctor.Fail, Message: "git is not available", Fix: "Install git."},
+	}
+
+	var buf bytes.Buffer
+	writeDoctorReport(&buf, checks)
+
+	want := "[pass] tmux version: tmux 3.4\n" +
+		"[warn] plugins: \"tmux-yank\" is not installed\n" +
+		"       fix: Run `tpack install`.\n" +
+		"[fail] git: git is not available\n" +
+		"       fix: Install git.\n" +
+		"\n" +
+		"1 passed, 1 warning, 1 failed\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
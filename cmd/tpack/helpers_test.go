@@ -45,4 +45,15 @@ func TestNewOutput(t *testing.T) {
 			t.Errorf("newOutput(true, ...) returned %T, want *ui.TmuxOutput", out)
 		}
 	})
+
+	t.Run("json format overrides tmuxEcho", func(t *testing.T) {
+		outputFormat = outputJSON
+		t.Cleanup(func() { outputFormat = outputText })
+
+		out := newOutput(true, tmux.NewMockRunner())
+
+		if _, ok := out.(*ui.JSONOutput); !ok {
+			t.Errorf("newOutput with --output json returned %T, want *ui.JSONOutput", out)
+		}
+	})
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/history"
+	"github.com/tmuxpack/tpack/internal/tmux"
+)
+
+var historyCmd = &cobra.Command{
+	Use:               "history [plugin]",
+	Short:             "Show the log of plugin installs, updates, and removals",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completePluginNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Resolve(tmux.NewRealRunner())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+			return errSilent
+		}
+
+		entries, err := history.Load(cfg.StatePath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack:", err)
+			return errSilent
+		}
+		if len(args) == 1 {
+			entries = history.ForPlugin(entries, args[0])
+		}
+		if len(entries) == 0 {
+			fmt.Println("No history recorded.")
+			return nil
+		}
+
+		writeHistory(os.Stdout, entries)
+		return nil
+	},
+}
+
+// writeHistory prints entries oldest first, one per line.
+func writeHistory(w io.Writer, entries []history.Entry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			e.Time.Local().Format("2006-01-02 15:04"), e.Action, e.Plugin, e.Detail())
+	}
+	_ = tw.Flush()
+}
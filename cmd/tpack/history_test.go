@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmuxpack/tpack/internal/history"
+)
+
+func TestWriteHistory(t *testing.T) {
+	at := time.Date(2026, 3, 10, 9, 30, 0, 0, time.Local)
+	entries := []history.Entry{
+		{Time: at, Action: history.Install, Plugin: "tmux-yank", After: "abc1234"},
+		{Time: at, Action: history.Update, Plugin: "tmux-sensible", Error: "pull failed"},
+	}
+
+	var buf bytes.Buffer
+	writeHistory(&buf, entries)
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	if lines[0] != "2026-03-10 09:30  install  tmux-yank      abc1234" {
+		t.Errorf("line 0 = %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "tmux-sensible  failed: pull failed") {
+		t.Errorf("line 1 = %q", lines[1])
+	}
+}
@@ -6,11 +6,15 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/manager"
 	"github.com/tmuxpack/tpack/internal/shell"
 	"github.com/tmuxpack/tpack/internal/tmux"
 	"github.com/tmuxpack/tpack/internal/tui"
@@ -62,11 +66,24 @@ func runInitCmd() error {
 		fmt.Fprintf(os.Stderr, "tpack: warning: failed to bind keys: %v\n", err)
 	}
 
+	plugins, err := gatherPlugins(runner, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tpack: warning:", err)
+	}
+	if err := bindLazyPlugins(runner, plugins, cfg.StatePath, binary); err != nil {
+		fmt.Fprintf(os.Stderr, "tpack: warning: failed to bind lazy plugins: %v\n", err)
+	}
+
 	// Source plugins.
-	output := ui.NewShellOutput()
-	mgr := newManagerDeps(cfg.PluginPath, output)
-	plugins := config.GatherPlugins(runner, config.RealFS{}, cfg.TmuxConf, cfg.Home, xdgConfigHome(cfg.Home))
-	mgr.Source(context.Background(), plugins)
+	output := &sourceFailures{Output: ui.NewShellOutput()}
+	mgr := newManagerDeps(cfg, output, manager.WithRunner(runner))
+	// Stopping init stops the plugin scripts it is running, too.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	mgr.Source(ctx, plugins)
+	stop()
+	if msg := output.summary(cfg.TuiKey); msg != "" {
+		_ = runner.DisplayMessage(msg)
+	}
 
 	if shouldSpawnUpdateCheck(cfg) {
 		spawnUpdateCheck(binary)
@@ -79,6 +96,45 @@ func runInitCmd() error {
 	return nil
 }
 
+// sourceFailures collects the plugins that failed to source, so init can
+// tell the user about them in tmux rather than only on stderr, which
+// run-shell hides during startup.
+type sourceFailures struct {
+	ui.Output
+	mu      sync.Mutex
+	results []ui.Result
+}
+
+func (f *sourceFailures) Result(r ui.Result) {
+	if r.Status != ui.StatusFailed {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results = append(f.results, r)
+}
+
+// summary returns the message to display for the failed plugins, or "" if
+// none failed. A single failure is shown with its reason, such as a script
+// that timed out.
+func (f *sourceFailures) summary(tuiKey string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch len(f.results) {
+	case 0:
+		return ""
+	case 1:
+		r := f.results[0]
+		return fmt.Sprintf("tpack: %s failed to load (%s). Press prefix + %s and open its details to see why.", r.Name, r.Error, tuiKey)
+	}
+	names := make([]string, len(f.results))
+	for i, r := range f.results {
+		names[i] = r.Name
+	}
+	return fmt.Sprintf("tpack: %d plugins failed to load (%s). Press prefix + %s and open their details to see why.",
+		len(names), strings.Join(names, ", "), tuiKey)
+}
+
 func bindKeys(runner tmux.Runner, cfg *config.Config, binary string) error {
 	verStr, err := runner.Version()
 	if err == nil && popupSupported(verStr) {
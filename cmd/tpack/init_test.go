@@ -10,6 +10,7 @@ import (
 
 	"github.com/tmuxpack/tpack/internal/config"
 	"github.com/tmuxpack/tpack/internal/tmux"
+	"github.com/tmuxpack/tpack/internal/ui"
 )
 
 func TestFindBinary(t *testing.T) {
@@ -278,3 +279,21 @@ func TestShouldSpawnUpdateCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestSourceFailuresSummary(t *testing.T) {
+	f := &sourceFailures{Output: ui.NewMockOutput()}
+	if msg := f.summary("T"); msg != "" {
+		t.Errorf("summary() = %q, want nothing when no plugin failed", msg)
+	}
+
+	ui.Report(f, ui.Result{Name: "tmux-sensible", Action: "source", Status: ui.StatusOK})
+	ui.Report(f, ui.Result{Name: "tmux-cpu", Action: "source", Status: ui.StatusFailed, Error: "cpu.tmux: timed out after 30s"})
+	if msg := f.summary("T"); !strings.Contains(msg, "tmux-cpu failed to load (cpu.tmux: timed out after 30s)") || !strings.Contains(msg, "prefix + T") {
+		t.Errorf("summary() = %q", msg)
+	}
+
+	ui.Report(f, ui.Result{Name: "tmux-yank", Action: "source", Status: ui.StatusFailed})
+	if msg := f.summary("T"); !strings.Contains(msg, "2 plugins failed to load (tmux-cpu, tmux-yank)") {
+		t.Errorf("summary() = %q", msg)
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/tmuxpack/tpack/internal/config"
-	gitcli "github.com/tmuxpack/tpack/internal/git/cli"
+	gitbackend "github.com/tmuxpack/tpack/internal/git/backend"
 	"github.com/tmuxpack/tpack/internal/manager"
+	"github.com/tmuxpack/tpack/internal/plug"
 	"github.com/tmuxpack/tpack/internal/tmux"
 	"github.com/tmuxpack/tpack/internal/ui"
 )
@@ -20,45 +22,63 @@ var installCmd = &cobra.Command{
 	Short: "Install all plugins declared in tmux.conf",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tmuxEcho, _ := cmd.Flags().GetBool("tmux-echo")
+		frozen, _ := cmd.Flags().GetBool("frozen")
+		return runInstall(tmuxEcho, frozen)
+	},
+}
 
-		runner := tmux.NewRealRunner()
-		cfg, err := config.Resolve(runner)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "tpack: config error:", err)
-			return errSilent
-		}
+// runInstall installs all declared plugins. With frozen set, plugins are
+// synced to the lockfile instead and a stale lockfile is an error.
+func runInstall(tmuxEcho, frozen bool) error {
+	runner := tmux.NewRealRunner()
+	cfg, err := config.Resolve(runner)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+		return errSilent
+	}
 
-		output := newOutput(tmuxEcho, runner)
+	output := newOutput(tmuxEcho, runner)
 
-		if tmuxEcho {
-			_ = runner.SourceFile(cfg.TmuxConf)
-		}
+	if tmuxEcho {
+		_ = runner.SourceFile(cfg.TmuxConf)
+	}
 
-		mgr := newManagerDeps(cfg.PluginPath, output)
+	mgr := newManagerDeps(cfg, output)
 
-		plugins := config.GatherPlugins(runner, config.RealFS{}, cfg.TmuxConf, cfg.Home, xdgConfigHome(cfg.Home))
+	plugins, err := gatherPlugins(runner, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+		return errSilent
+	}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-		defer cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	if frozen {
+		mgr.Sync(ctx, plugins)
+	} else {
 		mgr.Install(ctx, plugins)
+	}
 
-		if tmuxEcho {
-			_ = runner.SourceFile(cfg.TmuxConf)
-			output.EndMessage()
-		}
+	if tmuxEcho {
+		_ = runner.SourceFile(cfg.TmuxConf)
+		output.EndMessage()
+	}
 
-		if output.HasFailed() {
-			return errSilent
-		}
-		return nil
-	},
+	if output.HasFailed() {
+		return errSilent
+	}
+	return nil
 }
 
 func init() {
 	installCmd.Flags().Bool("tmux-echo", false, "output via tmux display-message")
+	installCmd.Flags().Bool("frozen", false, "install exactly what the lockfile records; fail if it is out of date")
 }
 
 func newOutput(tmuxEcho bool, runner tmux.Runner) ui.Output {
+	if outputFormat == outputJSON {
+		return ui.NewJSONOutput()
+	}
 	if tmuxEcho {
 		return ui.NewTmuxOutput(runner)
 	}
@@ -79,16 +99,40 @@ func xdgConfigHome(home string) string {
 	return filepath.Join(home, ".config")
 }
 
-func newManagerDeps(pluginPath string, output ui.Output) *manager.Manager {
-	return manager.New(pluginPath,
-		gitcli.NewCloner(),
-		gitcli.NewPuller(),
-		gitcli.NewValidator(),
-		output,
-	)
+// gatherPlugins returns the plugins declared in tmux config files merged
+// with those in the plugin manifest. When the manifest can't be loaded, the
+// error is returned along with the plugins from config files.
+func gatherPlugins(runner tmux.Runner, cfg *config.Config) ([]plug.Plugin, error) {
+	xdg := xdgConfigHome(cfg.Home)
+	plugins := config.GatherPlugins(runner, config.RealFS{}, cfg.TmuxConf, cfg.Home, xdg)
+	host, _ := os.Hostname()
+	manifest, err := config.LoadManifest(config.RealFS{}, cfg.ManifestPath, host, cfg.Home, xdg)
+	if err != nil {
+		return plugins, err
+	}
+	return config.MergePlugins(plugins, manifest), nil
+}
+
+func newManagerDeps(cfg *config.Config, output ui.Output, opts ...manager.Option) *manager.Manager {
+	g := gitbackend.Select(cfg.GitBackend)
+	opts = append([]manager.Option{
+		manager.WithRevParser(g.RevParser),
+		manager.WithLogger(g.Logger),
+		manager.WithCheckouter(g.Checkouter),
+		manager.WithWorktreeChecker(g.WorktreeChecker),
+		manager.WithTagLister(g.TagLister),
+		manager.WithLockPath(cfg.LockPath),
+		manager.WithStatePath(cfg.StatePath),
+		manager.WithParallelSource(cfg.ParallelSource),
+		manager.WithSourceTimeout(cfg.SourceTimeout),
+		manager.WithSourceEnv(cfg.SourceEnv),
+	}, opts...)
+	return manager.New(cfg.PluginPath, g.Cloner, g.Puller, g.Validator, output, opts...)
 }
 
-// completePluginNames returns a list of plugin names for shell completion.
+// completePluginNames returns a list of plugin names for shell completion,
+// each described by where it is declared. Names already on the command
+// line are left out.
 func completePluginNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	runner := tmux.NewRealRunner()
 	cfg, err := config.Resolve(runner)
@@ -96,11 +140,44 @@ func completePluginNames(cmd *cobra.Command, args []string, toComplete string) (
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	plugins := config.GatherPlugins(runner, config.RealFS{}, cfg.TmuxConf, cfg.Home, xdgConfigHome(cfg.Home))
+	plugins, _ := gatherPlugins(runner, cfg)
+	return pluginCompletions(plugins, args), cobra.ShellCompDirectiveNoFileComp
+}
 
+// completeInstalledPluginNames is completePluginNames for commands that also
+// act on plugins installed without being declared.
+func completeInstalledPluginNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	runner := tmux.NewRealRunner()
+	cfg, err := config.Resolve(runner)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	plugins, _ := gatherPlugins(runner, cfg)
+	return installedCompletions(plugins, cfg.PluginPath, args), cobra.ShellCompDirectiveNoFileComp
+}
+
+// installedCompletions returns the completions of pluginCompletions
+// followed by a "name	not declared" completion for each orphaned plugin
+// directory not named in args.
+func installedCompletions(plugins []plug.Plugin, pluginPath string, args []string) []string {
+	names := pluginCompletions(plugins, args)
+	for _, o := range plug.FindOrphans(plugins, pluginPath) {
+		if !slices.Contains(args, o.Name) {
+			names = append(names, o.Name+"\tnot declared")
+		}
+	}
+	return names
+}
+
+// pluginCompletions returns a "name\torigin" completion for each plugin
+// not named in args.
+func pluginCompletions(plugins []plug.Plugin, args []string) []string {
 	var names []string
 	for _, p := range plugins {
-		names = append(names, p.Name)
+		if !slices.Contains(args, p.Name) {
+			names = append(names, p.Name+"\t"+p.Origin())
+		}
 	}
-	return names, cobra.ShellCompDirectiveNoFileComp
+	return names
 }
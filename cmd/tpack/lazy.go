@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/manager"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/shell"
+	"github.com/tmuxpack/tpack/internal/state"
+	"github.com/tmuxpack/tpack/internal/tmux"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+// Lazy plugins, declared with on-key= or on-hook=, are not sourced by init.
+// Instead init binds each key, and appends to each hook, a stub running
+// `tpack source --plugin <name>`, which sources the plugin the first time
+// it fires, removes the plugin's stubs and then replays what the plugin
+// bound to the key or hook.
+
+// loadedOption is the tmux option marking a lazy plugin as sourced, so its
+// stubs do nothing once it is.
+func loadedOption(name string) string {
+	return "@tpack-loaded-" + name
+}
+
+// lazyShellCmd builds the stub command loading the named plugin, for the
+// given trigger flag ("--key" or "--hook") and value.
+func lazyShellCmd(binary, name, flag, value string) string {
+	return "PATH=" + shell.Quote(os.Getenv("PATH")) + " " + shell.Quote(binary) +
+		" source --plugin " + shell.Quote(name) + " " + flag + " " + shell.Quote(value)
+}
+
+// isLazyStub reports whether the tmux command cmd is a stub loading the
+// named plugin.
+func isLazyStub(cmd, name string) bool {
+	return strings.Contains(cmd, "source --plugin "+shell.Quote(name))
+}
+
+// bindLazyPlugins binds the stubs of the lazy plugins that aren't disabled.
+// Their loaded markers are cleared, so reloading the tmux config sources
+// them again on first use; hooks that already have a plugin's stub, from an
+// earlier init, are left alone. Hooks need tmux 3.0 or newer.
+func bindLazyPlugins(runner tmux.Runner, plugins []plug.Plugin, statePath, binary string) error {
+	st := state.Load(statePath)
+	verStr, _ := runner.Version()
+	hooksSupported := tmux.IsVersionSupported(tmux.ParseVersionDigits(verStr), config.HookTmuxVersion)
+	var errs []error
+	for _, p := range plugins {
+		if !p.IsLazy() || p.Disabled || st.IsDisabled(p.Name) {
+			continue
+		}
+		errs = append(errs, runner.SetOption(loadedOption(p.Name), ""))
+		for _, key := range p.OnKey {
+			errs = append(errs, runner.BindKey(key, lazyShellCmd(binary, p.Name, "--key", key), "[tpack] Load "+p.Name))
+		}
+		if len(p.OnHook) > 0 && !hooksSupported {
+			errs = append(errs, fmt.Errorf("%s: on-hook= needs tmux 3.0 or newer", p.Name))
+			continue
+		}
+		for _, hook := range p.OnHook {
+			existing, err := runner.ShowHooks(hook)
+			if err == nil && isLazyStub(existing, p.Name) {
+				continue
+			}
+			errs = append(errs, runner.SetHook(hook, lazyShellCmd(binary, p.Name, "--hook", hook)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runLoadPlugin sources the named lazy plugin for `tpack source --plugin`,
+// unless it is already loaded, reporting failures in tmux as init does.
+func runLoadPlugin(name, key, hook string) error {
+	runner := tmux.NewRealRunner()
+	cfg, err := config.Resolve(runner)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+		return errSilent
+	}
+	plugins, err := gatherPlugins(runner, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tpack: warning:", err)
+	}
+	decls := declaredAs(plugins, name)
+	if len(decls) == 0 {
+		fmt.Fprintf(os.Stderr, "tpack: %q is not declared\n", name)
+		return errSilent
+	}
+
+	output := &sourceFailures{Output: ui.NewShellOutput()}
+	mgr := newManagerDeps(cfg, output, manager.WithRunner(runner))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := loadPlugin(ctx, runner, mgr, decls[0], key, hook); err != nil {
+		fmt.Fprintln(os.Stderr, "tpack:", err)
+	}
+	if msg := output.summary(cfg.TuiKey); msg != "" {
+		_ = runner.DisplayMessage(msg)
+	}
+	return nil
+}
+
+// loadPlugin sources p, unless it is already loaded, marks it loaded and
+// removes its stubs. Then whatever the plugin bound to key, or added to
+// hook, is run in place of the stub that triggered loading it.
+func loadPlugin(ctx context.Context, runner tmux.Runner, mgr *manager.Manager, p plug.Plugin, key, hook string) error {
+	if loaded, _ := runner.ShowOption(loadedOption(p.Name)); loaded != "" {
+		return nil
+	}
+	var before string
+	if hook != "" {
+		before, _ = runner.ShowHooks(hook)
+	}
+
+	mgr.Load(ctx, []plug.Plugin{p})
+	if err := runner.SetOption(loadedOption(p.Name), "1"); err != nil {
+		return err
+	}
+
+	var replay []string
+	switch {
+	case key != "":
+		listing, err := runner.ListKeys("prefix")
+		if err != nil {
+			return err
+		}
+		if cmd, ok := bindingCommand(listing, key); ok && !isLazyStub(cmd, p.Name) {
+			replay = append(replay, cmd)
+		}
+	case hook != "":
+		after, err := runner.ShowHooks(hook)
+		if err != nil {
+			return err
+		}
+		for _, cmd := range addedHookCommands(before, after) {
+			if !isLazyStub(cmd, p.Name) {
+				replay = append(replay, cmd)
+			}
+		}
+	}
+	if err := removeStubs(runner, p); err != nil {
+		return err
+	}
+	if len(replay) == 0 {
+		return nil
+	}
+	return runCommands(runner, replay)
+}
+
+// removeStubs unsets the hook commands, and unbinds the keys the plugin
+// didn't rebind, that still run p's stub, so they stop firing once p is
+// loaded.
+func removeStubs(runner tmux.Runner, p plug.Plugin) error {
+	var errs []error
+	if len(p.OnKey) > 0 {
+		listing, err := runner.ListKeys("prefix")
+		if err != nil {
+			return err
+		}
+		for _, key := range p.OnKey {
+			if cmd, ok := bindingCommand(listing, key); ok && isLazyStub(cmd, p.Name) {
+				errs = append(errs, runner.UnbindKey(key))
+			}
+		}
+	}
+	for _, hook := range p.OnHook {
+		hooks, err := runner.ShowHooks(hook)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for line := range strings.Lines(hooks) {
+			index, cmd := nextField(line)
+			if isLazyStub(cmd, p.Name) {
+				errs = append(errs, runner.UnsetHook(index))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// bindingCommand returns the command bound to key in listing, the output
+// of list-keys for one key table.
+func bindingCommand(listing, key string) (string, bool) {
+	for line := range strings.Lines(listing) {
+		tok, rest := nextField(line)
+		if tok != "bind-key" {
+			continue
+		}
+		for {
+			tok, rest = nextField(rest)
+			if !strings.HasPrefix(tok, "-") {
+				break
+			}
+			if tok == "-T" || tok == "-N" {
+				_, rest = nextField(rest)
+			}
+		}
+		if tok == key {
+			return strings.TrimSpace(rest), true
+		}
+	}
+	return "", false
+}
+
+// addedHookCommands returns the commands in the show-hooks output after
+// that weren't in before.
+func addedHookCommands(before, after string) []string {
+	seen := make(map[string]int)
+	for line := range strings.Lines(before) {
+		_, cmd := nextField(line)
+		seen[strings.TrimSpace(cmd)]++
+	}
+	var added []string
+	for line := range strings.Lines(after) {
+		_, cmd := nextField(line)
+		cmd = strings.TrimSpace(cmd)
+		if cmd == "" {
+			continue
+		}
+		if seen[cmd] > 0 {
+			seen[cmd]--
+			continue
+		}
+		added = append(added, cmd)
+	}
+	return added
+}
+
+// nextField splits off the first whitespace-separated field of s, leaving
+// the rest untouched.
+func nextField(s string) (field, rest string) {
+	s = strings.TrimLeft(s, " \t")
+	i := strings.IndexAny(s, " \t\n")
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], s[i:]
+}
+
+// runCommands runs tmux commands by sourcing them from a temporary file.
+func runCommands(runner tmux.Runner, cmds []string) error {
+	f, err := os.CreateTemp("", "tpack-replay-*.conf")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(strings.Join(cmds, "\n") + "\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return runner.SourceFile(f.Name())
+}
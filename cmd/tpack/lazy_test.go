@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/manager"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/state"
+	"github.com/tmuxpack/tpack/internal/tmux"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+func TestBindLazyPlugins(t *testing.T) {
+	stateDir := t.TempDir()
+	if err := state.SetDisabled(stateDir, true, "paused"); err != nil {
+		t.Fatal(err)
+	}
+	runner := tmux.NewMockRunner()
+	runner.VersionStr = "tmux 3.4"
+	runner.Hooks["client-attached"] = `client-attached[0] run-shell "PATH='/bin' '/bin/tpack' source --plugin 'resurrect' --hook 'client-attached'"`
+	plugins := []plug.Plugin{
+		{Name: "sensible"},
+		{Name: "fzf", OnKey: []string{"F"}},
+		{Name: "resurrect", OnKey: []string{"C-s"}, OnHook: []string{"session-created", "client-attached"}},
+		{Name: "paused", OnKey: []string{"P"}},
+		{Name: "off", OnKey: []string{"O"}, Disabled: true},
+	}
+
+	if err := bindLazyPlugins(runner, plugins, stateDir, "/bin/tpack"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range runner.Calls {
+		switch c.Method {
+		case "BindKey", "SetHook":
+			got = append(got, c.Method+" "+c.Args[0])
+			if !isLazyStub(c.Args[1], "fzf") && !isLazyStub(c.Args[1], "resurrect") {
+				t.Errorf("%s %s runs %q, want a stub", c.Method, c.Args[0], c.Args[1])
+			}
+		case "SetOption":
+			got = append(got, c.Method+" "+c.Args[0]+"="+c.Args[1])
+		}
+	}
+	want := "SetOption @tpack-loaded-fzf=, BindKey F, SetOption @tpack-loaded-resurrect=, BindKey C-s, SetHook session-created"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("calls = %s\nwant %s", strings.Join(got, ", "), want)
+	}
+}
+
+func TestBindLazyPlugins_OldTmux(t *testing.T) {
+	runner := tmux.NewMockRunner()
+	runner.VersionStr = "tmux 2.9"
+	plugins := []plug.Plugin{{Name: "resurrect", OnKey: []string{"C-s"}, OnHook: []string{"session-created"}}}
+
+	err := bindLazyPlugins(runner, plugins, t.TempDir(), "/bin/tpack")
+	if err == nil || !strings.Contains(err.Error(), "tmux 3.0") {
+		t.Errorf("expected an error about the tmux version, got %v", err)
+	}
+	for _, c := range runner.Calls {
+		if c.Method == "SetHook" {
+			t.Errorf("expected no hooks to be set, got %+v", c)
+		}
+	}
+	if len(runner.Calls) == 0 || runner.Calls[len(runner.Calls)-1].Method != "BindKey" {
+		t.Errorf("expected the key stub to still be bound, calls = %+v", runner.Calls)
+	}
+}
+
+func TestBindingCommand(t *testing.T) {
+	listing := `bind-key    -T prefix       C-f                  run-shell "PATH='/bin' '/bin/tpack' source --plugin 'fzf' --key 'C-f'"
+bind-key -r -T prefix       Up                   select-pane -U
+bind-key    -T prefix       F                    display-popup -E "fzf  --multi"`
+
+	tests := []struct {
+		key  string
+		want string
+		ok   bool
+	}{
+		{"F", `display-popup -E "fzf  --multi"`, true},
+		{"Up", "select-pane -U", true},
+		{"C-f", `run-shell "PATH='/bin' '/bin/tpack' source --plugin 'fzf' --key 'C-f'"`, true},
+		{"x", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := bindingCommand(listing, tt.key)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("bindingCommand(%q) = %q, %v; want %q, %v", tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAddedHookCommands(t *testing.T) {
+	before := "session-created[0] run-shell stub\nsession-created[1] display-message hi\n"
+	after := "session-created[0] run-shell stub\nsession-created[1] display-message hi\nsession-created[2] run-shell 'restore.sh'\n"
+
+	got := addedHookCommands(before, after)
+	if len(got) != 1 || got[0] != "run-shell 'restore.sh'" {
+		t.Errorf("addedHookCommands() = %q", got)
+	}
+	if got := addedHookCommands(before, before); len(got) != 0 {
+		t.Errorf("addedHookCommands() with no change = %q", got)
+	}
+}
+
+func TestLoadPlugin(t *testing.T) {
+	newManager := func() *manager.Manager {
+		return manager.New(t.TempDir(), git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), ui.NewMockOutput())
+	}
+	fzf := plug.Plugin{Name: "fzf", OnKey: []string{"F"}}
+	called := func(runner *tmux.MockRunner, method, arg string) bool {
+		for _, c := range runner.Calls {
+			if c.Method == method && c.Args[0] == arg {
+				return true
+			}
+		}
+		return false
+	}
+	sourced := func(runner *tmux.MockRunner) int {
+		n := 0
+		for _, c := range runner.Calls {
+			if c.Method == "SourceFile" {
+				n++
+			}
+		}
+		return n
+	}
+
+	t.Run("replays the plugin's binding", func(t *testing.T) {
+		runner := tmux.NewMockRunner()
+		runner.Keys["prefix"] = `bind-key -T prefix F display-popup -E fzf`
+		if err := loadPlugin(context.Background(), runner, newManager(), fzf, "F", ""); err != nil {
+			t.Fatal(err)
+		}
+		if runner.Options[loadedOption("fzf")] == "" {
+			t.Error("expected the plugin to be marked loaded")
+		}
+		if sourced(runner) != 1 {
+			t.Errorf("expected the binding to be replayed, calls = %+v", runner.Calls)
+		}
+	})
+
+	t.Run("does not replay the stub", func(t *testing.T) {
+		runner := tmux.NewMockRunner()
+		runner.Keys["prefix"] = `bind-key -T prefix F run-shell "` + lazyShellCmd("/bin/tpack", "fzf", "--key", "F") + `"`
+		if err := loadPlugin(context.Background(), runner, newManager(), fzf, "F", ""); err != nil {
+			t.Fatal(err)
+		}
+		if sourced(runner) != 0 {
+			t.Errorf("expected nothing to be replayed, calls = %+v", runner.Calls)
+		}
+		if !called(runner, "UnbindKey", "F") {
+			t.Errorf("expected the stub key to be unbound, calls = %+v", runner.Calls)
+		}
+	})
+
+	t.Run("keeps the plugin's binding", func(t *testing.T) {
+		runner := tmux.NewMockRunner()
+		runner.Keys["prefix"] = `bind-key -T prefix F display-popup -E fzf`
+		if err := loadPlugin(context.Background(), runner, newManager(), fzf, "F", ""); err != nil {
+			t.Fatal(err)
+		}
+		if called(runner, "UnbindKey", "F") {
+			t.Errorf("expected the plugin's binding to be kept, calls = %+v", runner.Calls)
+		}
+	})
+
+	t.Run("removes the hook stubs", func(t *testing.T) {
+		resurrect := plug.Plugin{Name: "resurrect", OnHook: []string{"session-created", "client-attached"}}
+		runner := tmux.NewMockRunner()
+		runner.Hooks["session-created"] = "session-created[0] display-message hi\n" +
+			`session-created[1] run-shell "` + lazyShellCmd("/bin/tpack", "resurrect", "--hook", "session-created") + `"`
+		runner.Hooks["client-attached"] = `client-attached[3] run-shell "` + lazyShellCmd("/bin/tpack", "resurrect", "--hook", "client-attached") + `"`
+		if err := loadPlugin(context.Background(), runner, newManager(), resurrect, "", "session-created"); err != nil {
+			t.Fatal(err)
+		}
+		var unset []string
+		for _, c := range runner.Calls {
+			if c.Method == "UnsetHook" {
+				unset = append(unset, c.Args[0])
+			}
+		}
+		if strings.Join(unset, " ") != "session-created[1] client-attached[3]" {
+			t.Errorf("unset hooks = %q", unset)
+		}
+	})
+
+	t.Run("does nothing once loaded", func(t *testing.T) {
+		runner := tmux.NewMockRunner()
+		runner.Options[loadedOption("fzf")] = "1"
+		runner.Keys["prefix"] = `bind-key -T prefix F display-popup -E fzf`
+		if err := loadPlugin(context.Background(), runner, newManager(), fzf, "F", ""); err != nil {
+			t.Fatal(err)
+		}
+		if len(runner.Calls) != 1 {
+			t.Errorf("expected only the loaded check, calls = %+v", runner.Calls)
+		}
+	})
+}
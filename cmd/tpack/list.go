@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/git"
+	gitbackend "github.com/tmuxpack/tpack/internal/git/backend"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/tmux"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+// Values of listEntry.Status.
+const (
+	listInstalled = "installed"
+	listMissing   = "missing"
+	listLocal     = "local"
+	listOrphan    = "orphan"
+)
+
+// Values of listEntry.Update, set only when listing with --fetch.
+const (
+	updateAvailable = "available"
+	updateNone      = "none"
+	updateUnknown   = "unknown"
+)
+
+// listEntry is one row of `tpack list`. Field names double as the
+// identifiers available to --format templates.
+type listEntry struct {
+	Name     string `json:"name"`
+	Spec     string `json:"spec,omitempty"`
+	Branch   string `json:"branch,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Revision string `json:"revision,omitempty"`
+	Status   string `json:"status"`
+	Update   string `json:"update,omitempty"`
+	Path     string `json:"path"`
+	// Source is where the plugin is declared, as "file:line" or
+	// "@tpm_plugins". It is empty for orphans.
+	Source string `json:"source,omitempty"`
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List declared plugins with their install and update status",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		fetch, _ := cmd.Flags().GetBool("fetch")
+		if format != "" && outputFormat == outputJSON {
+			return fmt.Errorf("--format cannot be used with --output %s", outputJSON)
+		}
+
+		runner := tmux.NewRealRunner()
+		cfg, err := config.Resolve(runner)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+			return errSilent
+		}
+
+		plugins, err := gatherPlugins(runner, cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: warning:", err)
+		}
+		entries := collectList(plugins, cfg.PluginPath, gitbackend.Select(cfg.GitBackend), fetch)
+
+		switch {
+		case format != "":
+			err = writeListFormat(os.Stdout, format, entries)
+		case outputFormat == outputJSON:
+			err = writeListJSON(os.Stdout, entries)
+		default:
+			writeList(os.Stdout, entries, fetch)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack:", err)
+			return errSilent
+		}
+		return nil
+	},
+}
+
+func init() {
+	listCmd.Flags().String("format", "", "print each plugin using a Go template, e.g. '{{.Name}} {{.Revision}}'")
+	listCmd.Flags().Bool("fetch", false, "fetch from remotes to report available updates")
+}
+
+// collectList describes every declared plugin followed by any orphaned
+// plugin directories. With fetch set, installed plugins are also checked
+// for updates.
+func collectList(plugins []plug.Plugin, pluginPath string, g git.Backend, fetch bool) []listEntry {
+	entries := make([]listEntry, 0, len(plugins))
+	for _, p := range plugins {
+		dir := plug.PluginPath(p.Name, pluginPath)
+		e := listEntry{
+			Name:    p.Name,
+			Spec:    p.Spec,
+			Branch:  p.Branch,
+			Version: p.Version,
+			Status:  listMissing,
+			Path:    dir,
+			Source:  p.Origin(),
+		}
+		switch {
+		case p.IsLocal():
+			e.Path = p.LocalPath
+			if plug.IsLinked(p, pluginPath) {
+				e.Status = listLocal
+			}
+		case g.Validator.IsGitRepo(dir):
+			e.Status = listInstalled
+		}
+		if e.Status != listMissing && g.RevParser != nil {
+			e.Revision = revision(g.RevParser, e.Path)
+		}
+		entries = append(entries, e)
+	}
+
+	if fetch {
+		for i, r := range checkPlugins(plugins, pluginPath, g) {
+			switch r.Status {
+			case ui.StatusOutdated:
+				entries[i].Update = updateAvailable
+			case ui.StatusUnchanged:
+				entries[i].Update = updateNone
+			case ui.StatusFailed:
+				entries[i].Update = updateUnknown
+			}
+		}
+	}
+
+	for _, o := range plug.FindOrphans(plugins, pluginPath) {
+		e := listEntry{Name: o.Name, Status: listOrphan, Path: o.Path}
+		if g.RevParser != nil {
+			e.Revision = revision(g.RevParser, o.Path)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// revision returns the commit checked out in dir, or "" if it can't be read
+// (e.g. a local plugin that isn't a git repository).
+func revision(rp git.RevParser, dir string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rev, err := rp.RevParse(ctx, dir)
+	if err != nil {
+		return ""
+	}
+	return rev
+}
+
+// writeList prints entries as an aligned table. The update column is only
+// shown when updates were fetched. Where each plugin is declared comes last,
+// as it is the longest.
+func writeList(w io.Writer, entries []listEntry, fetch bool) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := "NAME\tSPEC\tBRANCH\tREVISION\tSTATUS"
+	if fetch {
+		header += "\tUPDATE"
+	}
+	header += "\tSOURCE"
+	fmt.Fprintln(tw, header)
+	for _, e := range entries {
+		row := fmt.Sprintf("%s\t%s\t%s\t%s\t%s",
+			e.Name, orDash(e.Spec), orDash(listRef(e)), orDash(git.ShortHash(e.Revision)), e.Status)
+		if fetch {
+			row += "\t" + orDash(e.Update)
+		}
+		row += "\t" + orDash(e.Source)
+		fmt.Fprintln(tw, row)
+	}
+	_ = tw.Flush()
+}
+
+// writeListJSON prints entries as a single indented JSON array.
+func writeListJSON(w io.Writer, entries []listEntry) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// writeListFormat executes the template text once per entry, each followed
+// by a newline.
+func writeListFormat(w io.Writer, text string, entries []listEntry) error {
+	tmpl, err := template.New("format").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid --format: %w", err)
+	}
+	for _, e := range entries {
+		if err := tmpl.Execute(w, e); err != nil {
+			return fmt.Errorf("invalid --format: %w", err)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// listRef is the ref column: the declared branch, or the version pin.
+func listRef(e listEntry) string {
+	if e.Version != "" {
+		return "@" + e.Version
+	}
+	return e.Branch
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/plug"
+)
+
+func TestCollectList(t *testing.T) {
+	pluginPath := t.TempDir()
+	for _, d := range []string{"tmux-sensible", "tmux-yank", "old-plugin"} {
+		if err := os.Mkdir(filepath.Join(pluginPath, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	validator := git.NewMockValidator()
+	validator.Valid[filepath.Join(pluginPath, "tmux-sensible")] = true
+	validator.Valid[filepath.Join(pluginPath, "tmux-yank")] = true
+	fetcher := git.NewMockFetcher()
+	fetcher.Outdated[filepath.Join(pluginPath, "tmux-yank")] = true
+	rp := git.NewMockRevParser()
+	rp.Hash = "0123456789abcdef"
+	g := git.Backend{Validator: validator, Fetcher: fetcher, RevParser: rp}
+
+	plugins := []plug.Plugin{
+		{Name: "tmux-sensible", Spec: "tmux-plugins/tmux-sensible", Branch: "main", SourceFile: "/home/user/.tmux.conf", SourceLine: 4},
+		{Name: "tmux-yank", Spec: "tmux-plugins/tmux-yank"},
+		{Name: "tmux-resurrect", Spec: "tmux-plugins/tmux-resurrect"},
+	}
+
+	entries := collectList(plugins, pluginPath, g, true)
+
+	want := []struct{ name, status, update string }{
+		{"tmux-sensible", listInstalled, updateNone},
+		{"tmux-yank", listInstalled, updateAvailable},
+		{"tmux-resurrect", listMissing, ""},
+		{"old-plugin", listOrphan, ""},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), entries)
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Name != w.name || e.Status != w.status || e.Update != w.update {
+			t.Errorf("entry %d = %+v, want %s %s update=%q", i, e, w.name, w.status, w.update)
+		}
+	}
+	if entries[0].Revision != "0123456789abcdef" {
+		t.Errorf("expected revision of installed plugin, got %q", entries[0].Revision)
+	}
+	if entries[2].Revision != "" {
+		t.Errorf("expected no revision for missing plugin, got %q", entries[2].Revision)
+	}
+	if entries[0].Source != "/home/user/.tmux.conf:4" || entries[1].Source != "@tpm_plugins" || entries[3].Source != "" {
+		t.Errorf("unexpected sources %q, %q, %q", entries[0].Source, entries[1].Source, entries[3].Source)
+	}
+}
+
+func TestCollectList_NoFetch(t *testing.T) {
+	pluginPath := t.TempDir()
+	validator := git.NewMockValidator()
+	validator.Valid[filepath.Join(pluginPath, "tmux-sensible")] = true
+	fetcher := git.NewMockFetcher()
+	g := git.Backend{Validator: validator, Fetcher: fetcher, RevParser: git.NewMockRevParser()}
+
+	entries := collectList([]plug.Plugin{{Name: "tmux-sensible"}}, pluginPath, g, false)
+
+	if len(fetcher.Calls) != 0 {
+		t.Errorf("expected no fetches without --fetch, got %v", fetcher.Calls)
+	}
+	if len(entries) != 1 || entries[0].Update != "" {
+		t.Errorf("expected no update status without --fetch, got %+v", entries)
+	}
+}
+
+func TestWriteList(t *testing.T) {
+	entries := []listEntry{
+		{Name: "tmux-sensible", Spec: "tmux-plugins/tmux-sensible", Branch: "main", Revision: "0123456789abcdef", Status: listInstalled, Update: updateNone, Source: "/home/user/.tmux.conf:4"},
+		{Name: "tmux-yank", Spec: "tmux-plugins/tmux-yank@v2.3.0", Version: "v2.3.0", Status: listMissing},
+	}
+
+	var buf bytes.Buffer
+	writeList(&buf, entries, true)
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got %q", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "NAME") || !strings.HasSuffix(lines[0], "UPDATE  SOURCE") {
+		t.Errorf("header = %q", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "tmux-sensible tmux-plugins/tmux-sensible main 0123456 installed none /home/user/.tmux.conf:4" {
+		t.Errorf("row 1 = %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "tmux-yank tmux-plugins/tmux-yank@v2.3.0 @v2.3.0 - missing - -" {
+		t.Errorf("row 2 = %q", lines[2])
+	}
+}
+
+func TestWriteListJSON(t *testing.T) {
+	entries := []listEntry{{Name: "tmux-sensible", Revision: "abc", Status: listInstalled, Path: "/p/tmux-sensible"}}
+
+	var buf bytes.Buffer
+	if err := writeListJSON(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if len(got) != 1 || got[0]["name"] != "tmux-sensible" || got[0]["status"] != "installed" {
+		t.Errorf("unexpected JSON: %v", got)
+	}
+	if _, ok := got[0]["update"]; ok {
+		t.Error("expected update to be omitted when not fetched")
+	}
+}
+
+func TestWriteListFormat(t *testing.T) {
+	entries := []listEntry{
+		{Name: "tmux-sensible", Status: listInstalled},
+		{Name: "old-plugin", Status: listOrphan},
+	}
+
+	var buf bytes.Buffer
+	if err := writeListFormat(&buf, "{{.Name}}={{.Status}}", entries); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "tmux-sensible=installed\nold-plugin=orphan\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	if err := writeListFormat(&buf, "{{.Nope}}", entries); err == nil {
+		t.Error("expected error for unknown field")
+	}
+	if err := writeListFormat(&buf, "{{", entries); err == nil {
+		t.Error("expected error for malformed template")
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/tmux"
+)
+
+var removeCmd = &cobra.Command{
+	Use:   "remove <plugin>...",
+	Short: "Remove plugins from tmux.conf and delete their directories",
+	Long: `Remove the @plugin declarations of the named plugins from whichever config
+file declares them, then delete their plugin directories. Plugins that are
+installed but no longer declared just have their directories deleted.`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeInstalledPluginNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner := tmux.NewRealRunner()
+		cfg, err := config.Resolve(runner)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+			return errSilent
+		}
+
+		plugins, err := gatherPlugins(runner, cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+			return errSilent
+		}
+		gather := func() []plug.Plugin {
+			plugins, _ := gatherPlugins(runner, cfg)
+			return plugins
+		}
+		output := newOutput(false, runner)
+
+		// Edit the config first, so a plugin whose declaration can't be
+		// removed keeps its directory.
+		var removed []plug.Plugin
+		orphans := plug.FindOrphans(plugins, cfg.PluginPath)
+		for _, name := range args {
+			decls := declaredAs(plugins, name)
+			if len(decls) == 0 {
+				if slices.ContainsFunc(orphans, func(o plug.Orphan) bool { return o.Name == name }) {
+					removed = append(removed, plug.Plugin{Name: name})
+				} else {
+					output.Err("\"" + name + "\" is not declared")
+				}
+				continue
+			}
+			ok := true
+			done := make(map[[2]string]bool)
+			for _, p := range decls {
+				// One call removes every declaration of p.Raw in its file.
+				if key := [2]string{p.SourceFile, p.Raw}; !done[key] {
+					done[key] = true
+					if err := config.RemoveDeclaration(p.SourceFile, p.Raw, cfg.EditOptions()...); err != nil {
+						output.Err("Can't remove \"" + name + "\": " + err.Error())
+						ok = false
+						break
+					}
+				}
+			}
+			if ok {
+				removed = append(removed, decls[0])
+			}
+		}
+
+		mgr := newManagerDeps(cfg, output)
+		remaining := gather()
+		for _, p := range removed {
+			mgr.Remove(context.Background(), remaining, p)
+		}
+
+		if output.HasFailed() {
+			return errSilent
+		}
+		return nil
+	},
+}
+
+// declaredAs returns every declaration of the plugin named name.
+func declaredAs(plugins []plug.Plugin, name string) []plug.Plugin {
+	var decls []plug.Plugin
+	for _, p := range plugins {
+		if p.Name == name {
+			decls = append(decls, p)
+		}
+	}
+	return decls
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/tmux"
+)
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <plugin>",
+	Short: "Return a plugin to the revision it had before its last update",
+	Long: `Check out the commit a plugin was at before its most recent update.
+Running it again steps further back through the plugin's update history.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completePluginNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tmuxEcho, _ := cmd.Flags().GetBool("tmux-echo")
+
+		runner := tmux.NewRealRunner()
+		cfg, err := config.Resolve(runner)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+			return errSilent
+		}
+
+		output := newOutput(tmuxEcho, runner)
+		mgr := newManagerDeps(cfg, output)
+
+		plugins, err := gatherPlugins(runner, cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+			return errSilent
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		mgr.Rollback(ctx, plugins, args[0])
+
+		if tmuxEcho {
+			_ = runner.SourceFile(cfg.TmuxConf)
+			output.EndMessage()
+		}
+
+		if output.HasFailed() {
+			return errSilent
+		}
+		return nil
+	},
+}
+
+func init() {
+	rollbackCmd.Flags().Bool("tmux-echo", false, "output via tmux display-message")
+}
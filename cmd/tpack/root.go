@@ -12,6 +12,15 @@ import (
 // Execute() will not print it again, but will still return exit code 1.
 var errSilent = errors.New("")
 
+// Values accepted by the --output flag.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// outputFormat is the value of the global --output flag.
+var outputFormat string
+
 var rootCmd = &cobra.Command{
 	Use:   "tpack",
 	Short: "A modern tmux plugin manager",
@@ -22,6 +31,13 @@ var rootCmd = &cobra.Command{
 	// We handle error printing ourselves to preserve current output format.
 	SilenceErrors: true,
 
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if outputFormat != outputText && outputFormat != outputJSON {
+			return fmt.Errorf("invalid --output %q: must be %q or %q", outputFormat, outputText, outputJSON)
+		}
+		return nil
+	},
+
 	// Default command (no args): run init for backward compatibility.
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runInitCmd()
@@ -38,11 +54,22 @@ var versionCmd = &cobra.Command{
 
 func init() {
 	rootCmd.SetVersionTemplate("tpack {{.Version}}\n")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText,
+		"output format for install, update, clean, source, and check-updates: text or json")
 
 	rootCmd.AddCommand(
 		initCmd,
 		installCmd,
+		addCmd,
+		removeCmd,
+		disableCmd,
+		enableCmd,
+		syncCmd,
 		updateCmd,
+		rollbackCmd,
+		historyCmd,
+		listCmd,
+		doctorCmd,
 		cleanCmd,
 		sourceCmd,
 		tuiCmd,
@@ -2,13 +2,20 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/manager"
+	"github.com/tmuxpack/tpack/internal/profile"
 	"github.com/tmuxpack/tpack/internal/tmux"
-	"github.com/tmuxpack/tpack/internal/ui"
 )
 
 // Loading point for plugins
@@ -16,7 +23,24 @@ import (
 var sourceCmd = &cobra.Command{
 	Use:   "source",
 	Short: "Source all plugins without installing",
+	Long: `Source all plugins without installing.
+
+With --profile, every plugin script is timed and a table of the slowest
+scripts is printed. The run is saved to the state directory for the TUI's
+startup view.
+
+With --plugin, only the named plugin is sourced, even if it is declared to
+load on demand with on-key= or on-hook=. This is what the stubs bound by
+init run; with --key or --hook, the plugin's own binding for that key or
+hook is run afterwards.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		withProfile, _ := cmd.Flags().GetBool("profile")
+		if name, _ := cmd.Flags().GetString("plugin"); name != "" {
+			key, _ := cmd.Flags().GetString("key")
+			hook, _ := cmd.Flags().GetString("hook")
+			return runLoadPlugin(name, key, hook)
+		}
+
 		runner := tmux.NewRealRunner()
 		cfg, err := config.Resolve(runner)
 		if err != nil {
@@ -24,12 +48,80 @@ var sourceCmd = &cobra.Command{
 			return errSilent
 		}
 
-		output := ui.NewShellOutput()
-		mgr := newManagerDeps(cfg.PluginPath, output)
+		output := newOutput(false, runner)
+		opts := []manager.Option{manager.WithRunner(runner)}
+		var run *profile.Run
+		if withProfile {
+			run = &profile.Run{Time: time.Now()}
+			opts = append(opts, manager.WithProfile(run))
+		}
+		mgr := newManagerDeps(cfg, output, opts...)
 
-		plugins := config.GatherPlugins(runner, config.RealFS{}, cfg.TmuxConf, cfg.Home, xdgConfigHome(cfg.Home))
+		plugins, err := gatherPlugins(runner, cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: warning:", err)
+		}
 
 		mgr.Source(context.Background(), plugins)
-		return nil
+		if run == nil {
+			return nil
+		}
+
+		run.Total = time.Since(run.Time)
+		run.Sort()
+		if err := profile.Save(cfg.StatePath, run); err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: warning: failed to save profile:", err)
+		}
+		if outputFormat == outputJSON {
+			return json.NewEncoder(os.Stdout).Encode(struct {
+				Type string `json:"type"`
+				*profile.Run
+			}{"profile", run})
+		}
+		return writeProfile(os.Stdout, run)
 	},
 }
+
+func init() {
+	sourceCmd.Flags().Bool("profile", false, "time every plugin script and print the slowest")
+	sourceCmd.Flags().String("plugin", "", "source only the named plugin, once")
+	sourceCmd.Flags().String("key", "", "with --plugin, replay the plugin's binding for this key")
+	sourceCmd.Flags().String("hook", "", "with --plugin, replay what the plugin added to this hook")
+	sourceCmd.MarkFlagsMutuallyExclusive("key", "hook")
+	sourceCmd.MarkFlagsMutuallyExclusive("plugin", "profile")
+	_ = sourceCmd.RegisterFlagCompletionFunc("plugin", completePluginNames)
+}
+
+// writeProfile prints the scripts of r as a table, slowest first, with the
+// first line of anything they wrote to stderr.
+func writeProfile(w io.Writer, r *profile.Run) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tEXIT\tPLUGIN\tSCRIPT\tSTDERR")
+	var sum time.Duration
+	for _, s := range r.Scripts {
+		sum += s.Duration
+		exit := strconv.Itoa(s.ExitCode)
+		switch {
+		case s.TimedOut:
+			exit = "timeout"
+		case s.ExitCode < 0:
+			exit = "-"
+		}
+		stderr, _, _ := strings.Cut(strings.TrimSpace(s.Stderr), "\n")
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", formatDuration(s.Duration), exit, s.Plugin, s.File, orDash(stderr))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d scripts in %s (%s of script time)\n",
+		len(r.Scripts), formatDuration(r.Total), formatDuration(sum))
+	return err
+}
+
+// formatDuration rounds d for display, e.g. "12.3ms" or "1.25s".
+func formatDuration(d time.Duration) string {
+	if d >= time.Second {
+		return d.Round(10 * time.Millisecond).String()
+	}
+	return d.Round(100 * time.Microsecond).String()
+}
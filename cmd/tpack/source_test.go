@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmuxpack/tpack/internal/profile"
+)
+
+func TestWriteProfile(t *testing.T) {
+	run := &profile.Run{
+		Total: 1500 * time.Millisecond,
+		Scripts: []profile.Script{
+			{Plugin: "tmux-cpu", File: "cpu.tmux", Duration: 1234567 * time.Microsecond, ExitCode: 1, Stderr: "cpu: command not found\nmore\n"},
+			{Plugin: "tmux-sensible", File: "sensible.tmux", Duration: 12345 * time.Microsecond},
+			{Plugin: "broken", File: "broken.tmux", Duration: time.Millisecond, ExitCode: -1},
+			{Plugin: "hung", File: "hung.tmux", Duration: 2 * time.Second, ExitCode: -1, TimedOut: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeProfile(&buf, run); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `TIME    EXIT     PLUGIN         SCRIPT         STDERR
+1.23s   1        tmux-cpu       cpu.tmux       cpu: command not found
+12.3ms  0        tmux-sensible  sensible.tmux  -
+1ms     -        broken         broken.tmux    -
+2s      timeout  hung           hung.tmux      -
+
+4 scripts in 1.5s (3.25s of script time)
+`
+	if got := buf.String(); got != want {
+		t.Errorf("writeProfile() =\n%s\nwant:\n%s", got, want)
+	}
+	if strings.Contains(buf.String(), "more") {
+		t.Error("expected only the first stderr line")
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import "github.com/spf13/cobra"
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Make installed plugins match the lockfile exactly",
+	Long: `Clone missing plugins at their locked commits and reset installed plugins
+that drifted or were modified. Fails without changing anything if tmux.conf
+and the lockfile disagree. Equivalent to "tpack install --frozen".`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tmuxEcho, _ := cmd.Flags().GetBool("tmux-echo")
+		return runInstall(tmuxEcho, true)
+	},
+}
+
+func init() {
+	syncCmd.Flags().Bool("tmux-echo", false, "output via tmux display-message")
+}
@@ -9,7 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/tmuxpack/tpack/internal/config"
-	gitcli "github.com/tmuxpack/tpack/internal/git/cli"
+	gitbackend "github.com/tmuxpack/tpack/internal/git/backend"
 	"github.com/tmuxpack/tpack/internal/plug"
 	"github.com/tmuxpack/tpack/internal/shell"
 	"github.com/tmuxpack/tpack/internal/tmux"
@@ -50,15 +50,22 @@ var tuiCmd = &cobra.Command{
 		}
 		theme = tui.OverlayConfigColors(theme, cfg.Colors)
 
-		plugins := config.GatherPlugins(runner, config.RealFS{}, cfg.TmuxConf, cfg.Home, xdgConfigHome(cfg.Home))
+		plugins, err := gatherPlugins(runner, cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+			return errSilent
+		}
 
+		g := gitbackend.Select(cfg.GitBackend)
 		deps := tui.Deps{
-			Cloner:    gitcli.NewCloner(),
-			Puller:    gitcli.NewPuller(),
-			Validator: gitcli.NewValidator(),
-			Fetcher:   gitcli.NewFetcher(),
-			RevParser: gitcli.NewRevParser(),
-			Logger:    gitcli.NewLogger(),
+			Cloner:     g.Cloner,
+			Puller:     g.Puller,
+			Validator:  g.Validator,
+			Fetcher:    g.Fetcher,
+			RevParser:  g.RevParser,
+			Logger:     g.Logger,
+			TagLister:  g.TagLister,
+			Checkouter: g.Checkouter,
 		}
 		deps.Runner = runner
 
@@ -17,7 +17,7 @@ var updateCmd = &cobra.Command{
 	Use:               "update [plugin...]",
 	Short:             "Update specific plugin(s) or all",
 	Long:              `Update one or more plugins by name, or use "all" to update everything.`,
-	ValidArgsFunction: completePluginNames,
+	ValidArgsFunction: completeInstalledPluginNames,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tmuxEcho, _ := cmd.Flags().GetBool("tmux-echo")
 
@@ -41,9 +41,13 @@ var updateCmd = &cobra.Command{
 
 		output := newOutput(tmuxEcho, runner)
 
-		mgr := newManagerDeps(cfg.PluginPath, output)
+		mgr := newManagerDeps(cfg, output)
 
-		plugins := config.GatherPlugins(runner, config.RealFS{}, cfg.TmuxConf, cfg.Home, xdgConfigHome(cfg.Home))
+		plugins, err := gatherPlugins(runner, cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tpack: config error:", err)
+			return errSilent
+		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 		defer cancel()
@@ -87,12 +91,12 @@ func runUpdatePrompt(runner *tmux.RealRunner, cfg *config.Config) {
 
 // listInstalledPlugins displays the list of installed plugins via output.
 func listInstalledPlugins(runner *tmux.RealRunner, cfg *config.Config, output ui.Output) {
-	plugins := config.GatherPlugins(runner, config.RealFS{}, cfg.TmuxConf, cfg.Home, xdgConfigHome(cfg.Home))
+	plugins, _ := gatherPlugins(runner, cfg)
 
 	output.Ok("Installed plugins:")
 	output.Ok("")
 
-	mgr := newManagerDeps(cfg.PluginPath, output)
+	mgr := newManagerDeps(cfg, output)
 
 	for _, p := range plugins {
 		if mgr.IsPluginInstalled(p.Name) {
@@ -0,0 +1,80 @@
+// Package build runs plugin build commands, declared with a "build=" token
+// or in the manifest, after a plugin is cloned or updated. Plugins such as
+// tmux-thumbs need one to compile a binary before they work.
+package build
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/tmuxpack/tpack/internal/shell"
+	"github.com/tmuxpack/tpack/internal/state"
+)
+
+// maxOutput bounds how much of a build's output is kept. The end is kept,
+// as that is where builds say why they failed.
+const maxOutput = 64 << 10
+
+// waitDelay is how long a finished build is given to close its output, so
+// a background process it left running can't hold up the operation.
+const waitDelay = time.Second
+
+// Run runs command with sh in dir and returns what it wrote to stdout and
+// stderr. Canceling ctx kills the command and every process it started.
+func Run(ctx context.Context, dir, command string) (string, error) {
+	out := &tailBuffer{limit: maxOutput}
+	cmd := shell.Command(ctx, waitDelay, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	err := cmd.Run()
+	output := strings.TrimRight(out.String(), "\n")
+	switch {
+	case ctx.Err() != nil:
+		return output, fmt.Errorf("build %q: %w", command, ctx.Err())
+	case err != nil && !errors.Is(err, exec.ErrWaitDelay):
+		return output, fmt.Errorf("build %q: %w", command, err)
+	}
+	return output, nil
+}
+
+// Needed reports whether the named plugin, updated from commit before to
+// after, must be built: when the update moved it, or when its last build
+// failed. Updates that change nothing don't rebuild.
+func Needed(statePath, name, before, after string) bool {
+	if before == "" || after != before {
+		return true
+	}
+	return statePath != "" && state.Load(statePath).BuildFailed(name)
+}
+
+// Record notes whether the named plugin's build failed, so the next update
+// retries a failed build even if it doesn't move the plugin.
+func Record(statePath, name string, buildErr error) error {
+	if statePath == "" {
+		return nil
+	}
+	return state.SetBuildFailed(statePath, name, buildErr != nil)
+}
+
+// tailBuffer keeps the last limit bytes written to it, so a noisy build
+// can't use unbounded memory.
+type tailBuffer struct {
+	buf   []byte
+	limit int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.limit; over > 0 {
+		b.buf = b.buf[over:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string { return string(b.buf) }
@@ -0,0 +1,49 @@
+package build_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmuxpack/tpack/internal/build"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	out, err := build.Run(context.Background(), dir, "echo compiling; echo warning >&2; touch built")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if out != "compiling\nwarning" {
+		t.Errorf("output = %q", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "built")); err != nil {
+		t.Errorf("expected the build to run in the plugin directory: %v", err)
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	out, err := build.Run(context.Background(), t.TempDir(), "echo 'cargo: not found' >&2; exit 127")
+	if err == nil || !strings.Contains(err.Error(), "exit status 127") {
+		t.Errorf("err = %v, want the exit status", err)
+	}
+	if out != "cargo: not found" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := build.Run(ctx, t.TempDir(), "sleep 10 & sleep 10")
+	if err == nil {
+		t.Fatal("expected a canceled build to fail")
+	}
+	if d := time.Since(start); d > 3*time.Second {
+		t.Errorf("Run took %s after cancellation", d)
+	}
+}
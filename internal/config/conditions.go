@@ -0,0 +1,57 @@
+package config
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/tmuxpack/tpack/internal/tmux"
+)
+
+// ShellConditionTimeout bounds each if-shell condition run while gathering plugins.
+const ShellConditionTimeout = 5 * time.Second
+
+// conditions evaluates %if and if-shell conditions for GatherPlugins.
+// Formats are expanded by the tmux server. Shell conditions are only run
+// when the user opts in with @tpack-eval-if-shell; otherwise they are
+// undecided and both branches count.
+type conditions struct {
+	runner tmux.Runner
+	shell  bool
+}
+
+func newConditions(runner tmux.Runner) conditions {
+	v, _ := runner.ShowOption(EvalIfShellOption)
+	return conditions{runner: runner, shell: v == "on"}
+}
+
+// Format reports whether format expands to a true value: tmux treats
+// anything but "" and "0" as true. Without a tmux server it is undecided.
+func (c conditions) Format(format string) (bool, bool) {
+	out, err := c.runner.DisplayFormat(format)
+	if err != nil {
+		return false, false
+	}
+	return out != "" && out != "0", true
+}
+
+// Shell runs command with sh, as if-shell does, after expanding any formats
+// in it.
+func (c conditions) Shell(command string) (bool, bool) {
+	if !c.shell {
+		return false, false
+	}
+	if strings.Contains(command, "#{") {
+		expanded, err := c.runner.DisplayFormat(command)
+		if err != nil {
+			return false, false
+		}
+		command = expanded
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ShellConditionTimeout)
+	defer cancel()
+	err := exec.CommandContext(ctx, "sh", "-c", command).Run() //nolint:gosec // the user's own if-shell condition
+	return err == nil, true
+}
@@ -14,6 +14,9 @@ const (
 	LegacyPluginPathEnvVar = "TMUX_PLUGIN_MANAGER_PATH"
 	// SupportedTmuxVersion is the minimum tmux version encoded as major*100+minor.
 	SupportedTmuxVersion = 109
+	// HookTmuxVersion is the minimum tmux version for on-hook= plugins,
+	// whose stubs are appended with set-hook -a.
+	HookTmuxVersion = 300
 
 	// Current tmux option names for keybinding customization.
 	InstallKeyOption = "@tpack-install"
@@ -42,6 +45,33 @@ const (
 	// VersionOption is the tmux option for pinning the tpack version.
 	VersionOption = "@tpack-version"
 
+	// LockFileOption overrides the lockfile location (default: next to tmux.conf).
+	LockFileOption = "@tpack-lockfile"
+
+	// ManifestOption overrides the plugin manifest location (default: next to tmux.conf).
+	ManifestOption = "@tpack-manifest"
+
+	// GitBackendOption selects the git implementation ("auto", "cli", or "go").
+	GitBackendOption = "@tpack-git-backend"
+
+	// EvalIfShellOption opts in to running if-shell conditions when gathering plugins.
+	EvalIfShellOption = "@tpack-eval-if-shell"
+
+	// Current tmux option names for how tpack edits tmux.conf.
+	SortPluginsOption    = "@tpack-sort-plugins"
+	CommentRemovedOption = "@tpack-comment-removed"
+
+	// ParallelSourceOption opts in to sourcing independent plugins concurrently.
+	ParallelSourceOption = "@tpack-parallel-source"
+
+	// SourceTimeoutOption limits how long each plugin script may run.
+	SourceTimeoutOption = "@tpack-source-timeout"
+	// DefaultSourceTimeout is the script time limit unless set otherwise.
+	DefaultSourceTimeout = 30 * time.Second
+
+	// SourceEnvOption lists the environment variables plugin scripts get.
+	SourceEnvOption = "@tpack-source-env"
+
 	// AutoDownloadEnvVar is the current env var to opt out of auto-download.
 	AutoDownloadEnvVar = "TPACK_AUTO_DOWNLOAD"
 	// LegacyAutoDownloadEnvVar is the legacy env var to opt out of auto-download.
@@ -73,6 +103,35 @@ type Config struct {
 	HiddenCategories []string
 	// Directory for persistent state (e.g. last update check).
 	StatePath string
+	// Lockfile recording the exact commit of every plugin.
+	LockPath string
+	// Plugin manifest declaring plugins alongside @plugin lines.
+	ManifestPath string
+	// Git implementation: "cli", "go", or "auto"/empty to pick at runtime.
+	GitBackend string
+	// Insert new @plugin lines in alphabetical order.
+	SortPlugins bool
+	// Comment out removed @plugin lines instead of deleting them.
+	CommentRemoved bool
+	// Source plugins that don't depend on each other concurrently.
+	ParallelSource bool
+	// How long a plugin script may run before it is killed (0 = no limit).
+	SourceTimeout time.Duration
+	// Environment variables passed to plugin scripts (nil = all of them).
+	SourceEnv []string
 	// User's home directory
 	Home string
 }
+
+// EditOptions returns the options for editing config files the way the
+// user asked for.
+func (c *Config) EditOptions() []EditOption {
+	var opts []EditOption
+	if c.SortPlugins {
+		opts = append(opts, WithSortedInsert())
+	}
+	if c.CommentRemoved {
+		opts = append(opts, WithCommentOut())
+	}
+	return opts
+}
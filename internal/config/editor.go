@@ -0,0 +1,293 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tmuxpack/tpack/internal/tmuxconf"
+)
+
+// BackupSuffix is appended to a config file's name for the copy of it kept
+// from before tpack last edited it.
+const BackupSuffix = ".tpack.bak"
+
+// defaultDecl is how a declaration is written when the file has no other
+// declaration to copy the style of.
+const defaultDecl = "set -g @plugin "
+
+// EditOption configures an Editor.
+type EditOption func(*Editor)
+
+// WithSortedInsert makes Add place a plugin in alphabetical order within the
+// @plugin block, instead of after its last declaration.
+func WithSortedInsert() EditOption {
+	return func(e *Editor) { e.sorted = true }
+}
+
+// WithCommentOut makes Remove comment declarations out instead of deleting them.
+func WithCommentOut() EditOption {
+	return func(e *Editor) { e.commentOut = true }
+}
+
+// Editor edits the @plugin declarations of a config file. New declarations
+// go into the existing @plugin block, written in the style of the ones
+// around them, and the rest of the file is kept byte for byte. Nothing is
+// written until Save.
+type Editor struct {
+	path       string // symlinks resolved, so the link itself survives Save
+	mode       os.FileMode
+	orig, src  []byte
+	sorted     bool
+	commentOut bool
+}
+
+// EditFile reads the config file at path for editing.
+func EditFile(path string, opts ...EditOption) (*Editor, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	e := &Editor{path: resolved, mode: info.Mode().Perm(), orig: data, src: data}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e, nil
+}
+
+// Bytes returns the edited contents.
+func (e *Editor) Bytes() []byte { return e.src }
+
+// Declared reports whether spec has a top-level declaration.
+func (e *Editor) Declared(spec string) bool {
+	for _, d := range e.parse().Plugins() {
+		if d.Value == spec {
+			return true
+		}
+	}
+	return false
+}
+
+// Add declares spec unless it already is, and reports whether it did. The
+// declaration is inserted after the last one outside any %if, or at the end
+// of the file when there is none, but always before the command that runs
+// tpm or tpack.
+func (e *Editor) Add(spec string) bool {
+	if e.Declared(spec) {
+		return false
+	}
+	f := e.parse()
+	block := pluginBlock(f)
+	nl := tmuxconf.Newline(e.src)
+
+	at := len(e.src)
+	line := defaultDecl + quote(spec, '"')
+	if len(block) > 0 {
+		ref := block[len(block)-1]
+		at = lineEnd(e.src, ref.Command.End)
+		if e.sorted {
+			for _, d := range block {
+				if strings.ToLower(d.Value) > strings.ToLower(spec) {
+					ref = d
+					at = lineStart(e.src, d.Command.Start)
+					break
+				}
+			}
+		}
+		style := byte('"')
+		if w := specWord(ref.Command); w != nil {
+			style = e.src[w.Start]
+		}
+		line = e.styleOf(ref.Command) + quote(spec, style)
+	}
+	if run := runCommand(f); run != nil && at > run.Start {
+		at = lineStart(e.src, run.Start)
+	}
+
+	var b bytes.Buffer
+	b.Write(e.src[:at])
+	if at > 0 && e.src[at-1] != '\n' {
+		b.WriteString(nl)
+	}
+	b.WriteString(line + nl)
+	b.Write(e.src[at:])
+	e.src = b.Bytes()
+	return true
+}
+
+// Remove deletes or, with WithCommentOut, comments out every top-level
+// declaration of spec, and reports whether there was one.
+func (e *Editor) Remove(spec string) bool {
+	decls := e.parse().Plugins()
+	found := false
+	// Edit from the end so earlier commands' offsets stay valid.
+	for i := len(decls) - 1; i >= 0; i-- {
+		if decls[i].Value != spec {
+			continue
+		}
+		if e.commentOut {
+			e.src = tmuxconf.CommentCommand(e.src, decls[i].Command)
+		} else {
+			e.src = tmuxconf.RemoveCommand(e.src, decls[i].Command)
+		}
+		found = true
+	}
+	return found
+}
+
+// Save writes the edits, if any. The previous contents are kept next to
+// the file with BackupSuffix, and the file is replaced atomically so a
+// failed write never leaves it truncated.
+func (e *Editor) Save() error {
+	if bytes.Equal(e.src, e.orig) {
+		return nil
+	}
+	if err := os.WriteFile(e.path+BackupSuffix, e.orig, e.mode); err != nil {
+		return fmt.Errorf("back up %s: %w", e.path, err)
+	}
+	if err := writeAtomic(e.path, e.src, e.mode); err != nil {
+		return fmt.Errorf("write %s: %w", e.path, err)
+	}
+	e.orig = e.src
+	return nil
+}
+
+// writeAtomic replaces path with data by writing a temporary file in the
+// same directory and renaming it over path.
+func writeAtomic(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // fails harmlessly once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (e *Editor) parse() *tmuxconf.File {
+	f, _ := tmuxconf.Parse(e.path, e.src)
+	return f
+}
+
+// styleOf returns the indentation and command text up to the spec of the
+// declaration c, e.g. "  set-option -g @plugin ", for new declarations to
+// copy. Declarations split over several lines aren't copied.
+func (e *Editor) styleOf(c *tmuxconf.Command) string {
+	w := specWord(c)
+	if w == nil {
+		return defaultDecl
+	}
+	start := lineStart(e.src, c.Start)
+	style := string(e.src[start:w.Start])
+	if strings.Contains(style, "\n") || strings.TrimSpace(style[:c.Start-start]) != "" {
+		return defaultDecl
+	}
+	return style
+}
+
+// specWord returns the word of c holding its plugin spec.
+func specWord(c *tmuxconf.Command) *tmuxconf.Word {
+	spec, ok := c.PluginSpec()
+	if !ok || len(c.Args) == 0 {
+		return nil
+	}
+	w := &c.Args[len(c.Args)-1]
+	if strings.TrimSpace(w.Value) != spec {
+		return nil
+	}
+	return w
+}
+
+// pluginBlock returns the top-level declarations of f that aren't inside
+// an %if, where a new declaration would become conditional.
+func pluginBlock(f *tmuxconf.File) []tmuxconf.Decl {
+	var block []tmuxconf.Decl
+	depth := 0
+	for i := range f.Commands {
+		c := &f.Commands[i]
+		switch c.Name {
+		case "%if":
+			depth++
+		case "%endif":
+			depth = max(depth-1, 0)
+		}
+		if spec, ok := c.PluginSpec(); ok && depth == 0 {
+			block = append(block, tmuxconf.Decl{Value: spec, Command: c})
+		}
+	}
+	return block
+}
+
+// runCommand returns the top-level run-shell command that starts tpm or
+// tpack, which by convention comes after every @plugin declaration.
+func runCommand(f *tmuxconf.File) *tmuxconf.Command {
+	depth := 0
+	for i := range f.Commands {
+		c := &f.Commands[i]
+		switch c.Name {
+		case "%if":
+			depth++
+		case "%endif":
+			depth = max(depth-1, 0)
+		}
+		if depth > 0 || !c.Is("run-shell") || len(c.Args) == 0 {
+			continue
+		}
+		cmd := c.Args[len(c.Args)-1].Value
+		if strings.Contains(cmd, "tpm") || strings.Contains(cmd, "tpack") {
+			return c
+		}
+	}
+	return nil
+}
+
+// quote writes spec as a word in the given quote style: a quote character,
+// or anything else for bare. Quoting changes when the style can't hold spec.
+func quote(spec string, style byte) string {
+	switch {
+	case style == '\'' && !strings.Contains(spec, "'"):
+		return "'" + spec + "'"
+	case style != '\'' && style != '"' && !strings.ContainsAny(spec, " \t;#'\"\\$~{}"):
+		return spec
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`).Replace(spec) + `"`
+}
+
+// lineStart returns the offset of the start of the line holding off.
+func lineStart(src []byte, off int) int {
+	return bytes.LastIndexByte(src[:off], '\n') + 1
+}
+
+// lineEnd returns the offset just past the newline ending the line holding
+// off, or the end of src.
+func lineEnd(src []byte, off int) int {
+	if i := bytes.IndexByte(src[off:], '\n'); i >= 0 {
+		return off + i + 1
+	}
+	return len(src)
+}
@@ -0,0 +1,169 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/config"
+)
+
+// editFile writes src to a temporary tmux.conf and opens it for editing.
+func editFile(t *testing.T, src string, opts ...config.EditOption) (*config.Editor, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tmux.conf")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	e, err := config.EditFile(path, opts...)
+	if err != nil {
+		t.Fatalf("EditFile: %v", err)
+	}
+	return e, path
+}
+
+func TestEditorAdd(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		sorted bool
+		want   string
+	}{
+		{
+			name: "after the plugin block, before tpm",
+			src:  "set -g @plugin 'tmux-plugins/tpm'\nset -g @plugin 'b/two'\n\nset -g mouse on\nrun '~/.tmux/plugins/tpm/tpm'\n",
+			want: "set -g @plugin 'tmux-plugins/tpm'\nset -g @plugin 'b/two'\nset -g @plugin 'a/new'\n\nset -g mouse on\nrun '~/.tmux/plugins/tpm/tpm'\n",
+		},
+		{
+			name: "no block goes before tpm",
+			src:  "set -g mouse on\n\nrun -b '~/.tmux/plugins/tpm/tpm'\n",
+			want: "set -g mouse on\n\nset -g @plugin \"a/new\"\nrun -b '~/.tmux/plugins/tpm/tpm'\n",
+		},
+		{
+			name: "no block and no tpm appends",
+			src:  "set -g mouse on",
+			want: "set -g mouse on\nset -g @plugin \"a/new\"\n",
+		},
+		{
+			name:   "sorted",
+			src:    "set -g @plugin 'b/one'\nset -g @plugin 'c/two'\n",
+			sorted: true,
+			want:   "set -g @plugin 'a/new'\nset -g @plugin 'b/one'\nset -g @plugin 'c/two'\n",
+		},
+		{
+			name:   "sorted ignores case",
+			src:    "set -g @plugin 'A/mid'\nset -g @plugin 'Z/last'\n",
+			sorted: true,
+			want:   "set -g @plugin 'A/mid'\nset -g @plugin 'a/new'\nset -g @plugin 'Z/last'\n",
+		},
+		{
+			name: "copies command, indentation and bare words",
+			src:  "  set-option -g @plugin tmux-plugins/tpm # manager\n",
+			want: "  set-option -g @plugin tmux-plugins/tpm # manager\n  set-option -g @plugin a/new\n",
+		},
+		{
+			name: "declarations under %if are not the block",
+			src:  "set -g @plugin \"b/one\"\n%if #{>=:#{version},3.2}\nset -g @plugin 'c/popup'\n%endif\n",
+			want: "set -g @plugin \"b/one\"\nset -g @plugin \"a/new\"\n%if #{>=:#{version},3.2}\nset -g @plugin 'c/popup'\n%endif\n",
+		},
+		{
+			name: "crlf",
+			src:  "set -g @plugin 'b/one'\r\n",
+			want: "set -g @plugin 'b/one'\r\nset -g @plugin 'a/new'\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []config.EditOption
+			if tt.sorted {
+				opts = append(opts, config.WithSortedInsert())
+			}
+			e, _ := editFile(t, tt.src, opts...)
+			if !e.Add("a/new") {
+				t.Fatal("Add reported the plugin as already declared")
+			}
+			if got := string(e.Bytes()); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if e.Add("a/new") {
+				t.Error("second Add declared the plugin again")
+			}
+		})
+	}
+}
+
+func TestEditorAddQuotesWhenNeeded(t *testing.T) {
+	e, _ := editFile(t, "set -g @plugin tmux-plugins/tpm\n")
+	e.Add("user/repo alias=x")
+
+	want := "set -g @plugin tmux-plugins/tpm\nset -g @plugin \"user/repo alias=x\"\n"
+	if got := string(e.Bytes()); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestEditorRemoveCommentOut(t *testing.T) {
+	e, _ := editFile(t, "set -g @plugin 'a/one'\nset -g @plugin 'b/two'\n", config.WithCommentOut())
+
+	if !e.Remove("b/two") {
+		t.Fatal("expected the declaration to be found")
+	}
+	if want := "set -g @plugin 'a/one'\n# set -g @plugin 'b/two'\n"; string(e.Bytes()) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", e.Bytes(), want)
+	}
+	if e.Declared("b/two") {
+		t.Error("commented-out plugin still declared")
+	}
+}
+
+func TestEditorSave(t *testing.T) {
+	const orig = "set -g @plugin 'a/one'\n"
+	_, path := editFile(t, orig)
+	if err := os.Chmod(path, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(t.TempDir(), ".tmux.conf")
+	if err := os.Symlink(path, link); err != nil {
+		t.Fatal(err)
+	}
+	e, err := config.EditFile(link)
+	if err != nil {
+		t.Fatalf("EditFile: %v", err)
+	}
+
+	e.Add("b/two")
+	if err := e.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if fi, err := os.Lstat(link); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("symlink was replaced: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != orig+"set -g @plugin 'b/two'\n" {
+		t.Errorf("unexpected contents:\n%s", data)
+	}
+	if fi, _ := os.Stat(path); fi.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", fi.Mode().Perm())
+	}
+	backup, err := os.ReadFile(path + config.BackupSuffix)
+	if err != nil || string(backup) != orig {
+		t.Errorf("backup = %q, %v; want the original contents", backup, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 2 {
+		t.Errorf("expected only the file and its backup, got %v", entries)
+	}
+}
+
+func TestEditorSaveUnchanged(t *testing.T) {
+	e, path := editFile(t, "set -g @plugin 'a/one'\n")
+	e.Add("a/one")
+	if err := e.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := os.Stat(path + config.BackupSuffix); !os.IsNotExist(err) {
+		t.Errorf("expected no backup for an unchanged file, got %v", err)
+	}
+}
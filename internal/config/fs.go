@@ -1,11 +1,16 @@
 package config
 
-import "os"
+import (
+	"os"
+	"path/filepath"
+)
 
 // FS abstracts filesystem operations for testability.
 type FS interface {
 	ReadFile(name string) ([]byte, error)
 	FileExists(name string) bool
+	// Glob returns the files matching pattern, as filepath.Glob does.
+	Glob(pattern string) ([]string, error)
 }
 
 // RealFS implements FS using the real filesystem.
@@ -19,3 +24,7 @@ func (RealFS) FileExists(name string) bool {
 	_, err := os.Stat(name)
 	return err == nil
 }
+
+func (RealFS) Glob(pattern string) ([]string, error) {
+	return filepath.Glob(pattern)
+}
@@ -1,6 +1,10 @@
 package config
 
-import "os"
+import (
+	"os"
+	"path/filepath"
+	"sort"
+)
 
 // MockFS provides configurable filesystem responses for testing.
 type MockFS struct {
@@ -23,3 +27,18 @@ func (m *MockFS) FileExists(name string) bool {
 	_, ok := m.Files[name]
 	return ok
 }
+
+func (m *MockFS) Glob(pattern string) ([]string, error) {
+	var matches []string
+	for name := range m.Files {
+		ok, err := filepath.Match(pattern, name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matches = append(matches, name)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/tmuxpack/tpack/internal/plug"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFileName is the default plugin manifest name, read from next to
+// tmux.conf.
+const ManifestFileName = "tpack.yml"
+
+// Manifest is a plugin manifest: plugins declared in YAML instead of with
+// @plugin lines.
+type Manifest struct {
+	Plugins []ManifestEntry `yaml:"plugins"`
+}
+
+// ManifestEntry declares one plugin.
+type ManifestEntry struct {
+	// Source is the plugin spec without suffixes: "user/repo", a git URL,
+	// or "file:DIR".
+	Source string `yaml:"source"`
+	Branch string `yaml:"branch,omitempty"`
+	// Version pins a tag, a semver range, or a commit, as "@version" does.
+	Version string `yaml:"version,omitempty"`
+	// Tag is another name for Version.
+	Tag   string `yaml:"tag,omitempty"`
+	Alias string `yaml:"alias,omitempty"`
+	// Enabled defaults to true. Disabled plugins stay installed but are not
+	// sourced.
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// Hosts limits the entry to machines whose hostname matches one of
+	// these glob patterns.
+	Hosts []string `yaml:"hosts,omitempty"`
+	// After names plugins to source before this one.
+	After []string `yaml:"after,omitempty"`
+	// OnKey and OnHook load the plugin on demand instead of at startup.
+	OnKey   []string          `yaml:"on-key,omitempty"`
+	OnHook  []string          `yaml:"on-hook,omitempty"`
+	Build   string            `yaml:"build,omitempty"`
+	Options map[string]string `yaml:"options,omitempty"`
+}
+
+// IsManifest reports whether file is a plugin manifest rather than a tmux
+// config file.
+func IsManifest(file string) bool {
+	ext := filepath.Ext(file)
+	return ext == ".yml" || ext == ".yaml"
+}
+
+// LoadManifest reads the plugin manifest at path and returns the plugins
+// it declares for host. Entries limited to other hosts are left out; an
+// empty host matches every entry. A missing manifest
+// declares no plugins. Local paths are relative to the manifest.
+func LoadManifest(fs FS, path, host, home, xdgConfigHome string) ([]plug.Plugin, error) {
+	if !fs.FileExists(path) {
+		return nil, nil
+	}
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+
+	var m Manifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse manifest %s: %w", path, err)
+	}
+	// Decode again for the line each entry starts on.
+	var nodes struct {
+		Plugins []yaml.Node `yaml:"plugins"`
+	}
+	_ = yaml.Unmarshal(data, &nodes)
+
+	var plugins []plug.Plugin
+	lines := make(map[string]int)
+	for i, e := range m.Plugins {
+		line := 0
+		if i < len(nodes.Plugins) {
+			line = nodes.Plugins[i].Line
+		}
+		p, err := e.plugin()
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if !matchHost(e.Hosts, host) {
+			continue
+		}
+		if prev, ok := lines[p.Name]; ok {
+			return nil, fmt.Errorf("%s:%d: %q is already declared on line %d", path, line, p.Name, prev)
+		}
+		lines[p.Name] = line
+
+		p.SourceFile, p.SourceLine = path, line
+		if p.IsLocal() {
+			p.LocalPath = plug.ResolveLocalPath(p.LocalPath, filepath.Dir(path), home, xdgConfigHome)
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, nil
+}
+
+// plugin converts e to a Plugin by way of the equivalent @plugin spec, so
+// both kinds of declaration mean the same thing.
+func (e ManifestEntry) plugin() (plug.Plugin, error) {
+	source := strings.TrimSpace(e.Source)
+	if source == "" {
+		return plug.Plugin{}, errors.New("plugin has no source")
+	}
+	if strings.ContainsAny(source, " \t") {
+		return plug.Plugin{}, fmt.Errorf("source %q contains spaces", source)
+	}
+	version := e.Version
+	if e.Tag != "" {
+		if version != "" {
+			return plug.Plugin{}, errors.New("set either version or tag, not both")
+		}
+		version = e.Tag
+	}
+
+	raw := source
+	if version != "" {
+		raw += "@" + version
+	}
+	if e.Branch != "" {
+		raw += "#" + e.Branch
+	}
+	if e.Alias != "" {
+		raw += " alias=" + e.Alias
+	}
+	p := plug.ParseSpec(raw)
+
+	for name, value := range e.Options {
+		if !strings.HasPrefix(name, "@") {
+			return plug.Plugin{}, fmt.Errorf("option %q is not a user option (they start with @)", name)
+		}
+		p.Options = plug.AddOption(p.Options, name, value)
+	}
+	p.After = e.After
+	p.OnKey, p.OnHook = e.OnKey, e.OnHook
+	p.Build = e.Build
+	p.Disabled = e.Enabled != nil && !*e.Enabled
+	return p, nil
+}
+
+// matchHost reports whether host matches one of patterns, either as the
+// full hostname or up to its first dot, as tmux's #{host} and
+// #{host_short} differ. No patterns, or an unknown host, match.
+func matchHost(patterns []string, host string) bool {
+	if len(patterns) == 0 || host == "" {
+		return true
+	}
+	short, _, _ := strings.Cut(host, ".")
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, host); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, short); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// MergePlugins combines the plugins declared in tmux config files with
+// those from the manifest. A manifest entry replaces a declaration of the
+// same plugin in place; the rest follow the declared plugins in order.
+func MergePlugins(declared, manifest []plug.Plugin) []plug.Plugin {
+	byName := make(map[string]int, len(manifest))
+	for i, p := range manifest {
+		byName[p.Name] = i
+	}
+
+	merged := make([]plug.Plugin, 0, len(declared)+len(manifest))
+	used := make([]bool, len(manifest))
+	for _, p := range declared {
+		i, ok := byName[p.Name]
+		if !ok {
+			merged = append(merged, p)
+			continue
+		}
+		if !used[i] {
+			merged = append(merged, manifest[i])
+			used[i] = true
+		}
+	}
+	for i, p := range manifest {
+		if !used[i] {
+			merged = append(merged, p)
+		}
+	}
+	return merged
+}
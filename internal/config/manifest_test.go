@@ -0,0 +1,202 @@
+package config_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/plug"
+)
+
+const manifestPath = "/home/user/.config/tmux/tpack.yml"
+
+func loadManifest(t *testing.T, content, host string) ([]plug.Plugin, error) {
+	t.Helper()
+	fs := config.NewMockFS()
+	fs.Files[manifestPath] = content
+	return config.LoadManifest(fs, manifestPath, host, "/home/user", "/home/user/.config")
+}
+
+func TestLoadManifest(t *testing.T) {
+	plugins, err := loadManifest(t, `
+plugins:
+  - source: tmux-plugins/tmux-sensible
+  - source: catppuccin/tmux
+    tag: v2.1.0
+    alias: catppuccin-tmux
+    build: make
+    options:
+      "@catppuccin_flavor": mocha
+      "@catppuccin_window_status": icon
+  - source: tmux-plugins/tmux-yank
+    branch: dev
+    after: [catppuccin-tmux]
+    on-key: [y]
+    on-hook: [pane-focus-in]
+  - source: file:./plugins/mine
+`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plugins) != 4 {
+		t.Fatalf("expected 4 plugins, got %d", len(plugins))
+	}
+
+	sensible := plugins[0]
+	if sensible.Raw != "tmux-plugins/tmux-sensible" || sensible.Name != "tmux-sensible" {
+		t.Errorf("plugin[0] = %+v", sensible)
+	}
+	if sensible.SourceFile != manifestPath || sensible.SourceLine != 3 {
+		t.Errorf("plugin[0] origin = %s", sensible.Origin())
+	}
+
+	cat := plugins[1]
+	if cat.Raw != "catppuccin/tmux@v2.1.0 alias=catppuccin-tmux" {
+		t.Errorf("plugin[1].Raw = %q", cat.Raw)
+	}
+	if cat.Name != "catppuccin-tmux" || cat.Version != "v2.1.0" || cat.Build != "make" {
+		t.Errorf("plugin[1] = %+v", cat)
+	}
+	want := []plug.Option{
+		{Name: "@catppuccin_flavor", Value: "mocha"},
+		{Name: "@catppuccin_window_status", Value: "icon"},
+	}
+	if len(cat.Options) != len(want) || cat.Options[0] != want[0] || cat.Options[1] != want[1] {
+		t.Errorf("plugin[1].Options = %v, want %v", cat.Options, want)
+	}
+	if cat.SourceLine != 4 {
+		t.Errorf("plugin[1].SourceLine = %d, want 4", cat.SourceLine)
+	}
+
+	if plugins[2].Branch != "dev" || plugins[2].Raw != "tmux-plugins/tmux-yank#dev" ||
+		len(plugins[2].After) != 1 || plugins[2].After[0] != "catppuccin-tmux" ||
+		len(plugins[2].OnKey) != 1 || plugins[2].OnKey[0] != "y" ||
+		len(plugins[2].OnHook) != 1 || plugins[2].OnHook[0] != "pane-focus-in" {
+		t.Errorf("plugin[2] = %+v", plugins[2])
+	}
+	if plugins[3].LocalPath != "/home/user/.config/tmux/plugins/mine" {
+		t.Errorf("plugin[3].LocalPath = %q, want it relative to the manifest", plugins[3].LocalPath)
+	}
+}
+
+func TestLoadManifestMissing(t *testing.T) {
+	plugins, err := config.LoadManifest(config.NewMockFS(), manifestPath, "", "/home/user", "")
+	if err != nil || plugins != nil {
+		t.Errorf("LoadManifest() = %v, %v; want nothing", plugins, err)
+	}
+}
+
+func TestLoadManifestEmpty(t *testing.T) {
+	plugins, err := loadManifest(t, "", "")
+	if err != nil || len(plugins) != 0 {
+		t.Errorf("LoadManifest() = %v, %v; want nothing", plugins, err)
+	}
+}
+
+func TestLoadManifestSkipsEntries(t *testing.T) {
+	content := `
+plugins:
+  - source: a/off
+    enabled: false
+  - source: a/on
+    enabled: true
+  - source: a/work
+    hosts: [work-*]
+  - source: a/home
+    hosts: [home]
+`
+	tests := []struct {
+		host string
+		want []string
+	}{
+		{"work-laptop", []string{"off", "on", "work"}},
+		{"home.example.org", []string{"off", "on", "home"}},
+		{"", []string{"off", "on", "work", "home"}},
+	}
+	for _, tt := range tests {
+		plugins, err := loadManifest(t, content, tt.host)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, p := range plugins {
+			got = append(got, p.Name)
+			if p.Disabled != (p.Name == "off") {
+				t.Errorf("host %q: %s.Disabled = %v", tt.host, p.Name, p.Disabled)
+			}
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("host %q: got %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestLoadManifestErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown field", "plugins:\n  - source: a/b\n    brnach: dev\n", "brnach"},
+		{"no source", "plugins:\n  - branch: dev\n", ":2: plugin has no source"},
+		{"version and tag", "plugins:\n  - source: a/b\n    version: v1\n    tag: v2\n", "either version or tag"},
+		{"option name", "plugins:\n  - source: a/b\n    options:\n      flavor: mocha\n", `"flavor"`},
+		{"duplicate", "plugins:\n  - source: a/b\n  - source: c/b\n", `:3: "b" is already declared on line 2`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadManifest(t, tt.content, "")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadManifestDuplicateOnOtherHost(t *testing.T) {
+	plugins, err := loadManifest(t, `
+plugins:
+  - source: a/theme
+    hosts: [work]
+  - source: a/theme
+    branch: home
+    hosts: [home]
+`, "home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plugins) != 1 || plugins[0].Branch != "home" {
+		t.Errorf("got %+v, want only the home entry", plugins)
+	}
+}
+
+func TestMergePlugins(t *testing.T) {
+	declared := []plug.Plugin{
+		plug.ParseSpec("tmux-plugins/tpm"),
+		plug.ParseSpec("catppuccin/tmux"),
+		plug.ParseSpec("tmux-plugins/tmux-yank"),
+	}
+	fromManifest := []plug.Plugin{
+		{Name: "tmux", Raw: "catppuccin/tmux@v2", SourceFile: manifestPath},
+		{Name: "tmux-resurrect", Raw: "tmux-plugins/tmux-resurrect", SourceFile: manifestPath},
+	}
+
+	merged := config.MergePlugins(declared, fromManifest)
+
+	var got []string
+	for _, p := range merged {
+		got = append(got, p.Raw)
+	}
+	want := []string{"tmux-plugins/tpm", "catppuccin/tmux@v2", "tmux-plugins/tmux-yank", "tmux-plugins/tmux-resurrect"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("MergePlugins() = %v, want %v", got, want)
+	}
+}
+
+func TestRemoveDeclarationManifest(t *testing.T) {
+	err := config.RemoveDeclaration(manifestPath, "a/b")
+	if !errors.Is(err, config.ErrNotDeclared) || !strings.Contains(err.Error(), "manifest") {
+		t.Errorf("RemoveDeclaration() = %v, want a manifest ErrNotDeclared", err)
+	}
+}
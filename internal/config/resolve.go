@@ -4,9 +4,13 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/tmuxpack/tpack/internal/lock"
+	"github.com/tmuxpack/tpack/internal/plug"
 	"github.com/tmuxpack/tpack/internal/tmux"
 )
 
@@ -112,10 +116,44 @@ func Resolve(runner tmux.Runner, opts ...Option) (*Config, error) {
 	cfg.HiddenCategories = resolveHiddenCategories(runner)
 	cfg.StatePath = filepath.Join(o.xdgStateHome(), "tpack")
 	cfg.Home = o.home
+	cfg.LockPath = resolveLockPath(runner, o, cfg.TmuxConf)
+	cfg.ManifestPath = resolveManifestPath(runner, o, cfg.TmuxConf)
+	if v, err := runner.ShowOption(GitBackendOption); err == nil && v != "" {
+		cfg.GitBackend = parseGitBackend(v)
+	}
+	if v, err := runner.ShowOption(SortPluginsOption); err == nil {
+		cfg.SortPlugins = v == "on"
+	}
+	if v, err := runner.ShowOption(CommentRemovedOption); err == nil {
+		cfg.CommentRemoved = v == "on"
+	}
+	if v, err := runner.ShowOption(ParallelSourceOption); err == nil {
+		cfg.ParallelSource = v == "on"
+	}
+	cfg.SourceTimeout = resolveSourceTimeout(runner)
+	if v, err := runner.ShowOption(SourceEnvOption); err == nil && v != "" {
+		cfg.SourceEnv = strings.FieldsFunc(v, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+	}
 
 	return cfg, nil
 }
 
+// Returns the lockfile path from @tpack-lockfile, or tpack.lock next to tmux.conf.
+func resolveLockPath(runner tmux.Runner, o *resolveOpts, tmuxConf string) string {
+	if v, err := runner.ShowOption(LockFileOption); err == nil && v != "" {
+		return plug.ManualExpansion(v, o.home, o.xdgConfigHome())
+	}
+	return filepath.Join(filepath.Dir(tmuxConf), lock.FileName)
+}
+
+// Returns the manifest path from @tpack-manifest, or tpack.yml next to tmux.conf.
+func resolveManifestPath(runner tmux.Runner, o *resolveOpts, tmuxConf string) string {
+	if v, err := runner.ShowOption(ManifestOption); err == nil && v != "" {
+		return plug.ManualExpansion(v, o.home, o.xdgConfigHome())
+	}
+	return filepath.Join(filepath.Dir(tmuxConf), ManifestFileName)
+}
+
 // getUserTmuxConf returns the user's tmux.conf path (XDG first, then default).
 func getUserTmuxConf(o *resolveOpts) string {
 	xdgConf := filepath.Join(o.xdgConfigHome(), "tmux", "tmux.conf")
@@ -200,6 +238,40 @@ func parseUpdateMode(s string) string {
 	return ""
 }
 
+var validGitBackends = map[string]bool{
+	"auto": true,
+	"cli":  true,
+	"go":   true,
+}
+
+// Returns the backend name if valid, or empty string (auto) otherwise.
+func parseGitBackend(s string) string {
+	if validGitBackends[s] {
+		return s
+	}
+	return ""
+}
+
+// resolveSourceTimeout reads the plugin script time limit: a duration such
+// as "10s", a number of seconds, or "off" or "0" for no limit. Invalid
+// values keep the default.
+func resolveSourceTimeout(runner tmux.Runner) time.Duration {
+	v, err := runner.ShowOption(SourceTimeoutOption)
+	if err != nil || v == "" {
+		return DefaultSourceTimeout
+	}
+	if v == "off" {
+		return 0
+	}
+	if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+		return time.Duration(n) * time.Second
+	}
+	if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+		return d
+	}
+	return DefaultSourceTimeout
+}
+
 // Parses a duration string, returning 0 on any error.
 func parseCheckInterval(s string) time.Duration {
 	if s == "" {
@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -469,3 +470,175 @@ func TestResolveHiddenCategories(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveLockPath(t *testing.T) {
+	m := tmux.NewMockRunner()
+	fs := config.NewMockFS()
+	fs.Files["/home/user/.config/tmux/tmux.conf"] = ""
+
+	cfg, err := config.Resolve(m, testOpts(fs)...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "/home/user/.config/tmux/tpack.lock"
+	if cfg.LockPath != want {
+		t.Errorf("LockPath = %q, want %q", cfg.LockPath, want)
+	}
+}
+
+func TestResolveManifestPath(t *testing.T) {
+	m := tmux.NewMockRunner()
+	fs := config.NewMockFS()
+	fs.Files["/home/user/.config/tmux/tmux.conf"] = ""
+
+	cfg, err := config.Resolve(m, testOpts(fs)...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "/home/user/.config/tmux/tpack.yml"; cfg.ManifestPath != want {
+		t.Errorf("ManifestPath = %q, want %q", cfg.ManifestPath, want)
+	}
+
+	m.Options["@tpack-manifest"] = "~/dotfiles/plugins.yml"
+	cfg, err = config.Resolve(m, testOpts(fs)...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "/home/user/dotfiles/plugins.yml"; cfg.ManifestPath != want {
+		t.Errorf("ManifestPath = %q, want %q", cfg.ManifestPath, want)
+	}
+}
+
+func TestResolveLockPathFromOption(t *testing.T) {
+	m := tmux.NewMockRunner()
+	m.Options["@tpack-lockfile"] = "~/dotfiles/tpack.lock"
+	fs := config.NewMockFS()
+
+	cfg, err := config.Resolve(m, testOpts(fs)...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "/home/user/dotfiles/tpack.lock"
+	if cfg.LockPath != want {
+		t.Errorf("LockPath = %q, want %q", cfg.LockPath, want)
+	}
+}
+
+func TestResolveGitBackend(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"auto", "auto"},
+		{"cli", "cli"},
+		{"go", "go"},
+		{"libgit2", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			m := tmux.NewMockRunner()
+			if tt.value != "" {
+				m.Options["@tpack-git-backend"] = tt.value
+			}
+			cfg, err := config.Resolve(m, testOpts(config.NewMockFS())...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.GitBackend != tt.want {
+				t.Errorf("GitBackend = %q, want %q", cfg.GitBackend, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveEditOptions(t *testing.T) {
+	m := tmux.NewMockRunner()
+	cfg, err := config.Resolve(m, testOpts(config.NewMockFS())...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SortPlugins || cfg.CommentRemoved || len(cfg.EditOptions()) != 0 {
+		t.Errorf("expected no edit options by default, got %+v", cfg)
+	}
+
+	m.Options["@tpack-sort-plugins"] = "on"
+	m.Options["@tpack-comment-removed"] = "on"
+	cfg, err = config.Resolve(m, testOpts(config.NewMockFS())...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.SortPlugins || !cfg.CommentRemoved || len(cfg.EditOptions()) != 2 {
+		t.Errorf("expected both edit options, got %+v", cfg)
+	}
+}
+
+func TestResolveParallelSource(t *testing.T) {
+	m := tmux.NewMockRunner()
+	cfg, err := config.Resolve(m, testOpts(config.NewMockFS())...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ParallelSource {
+		t.Error("expected serial sourcing by default")
+	}
+
+	m.Options["@tpack-parallel-source"] = "on"
+	cfg, err = config.Resolve(m, testOpts(config.NewMockFS())...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.ParallelSource {
+		t.Error("expected parallel sourcing with @tpack-parallel-source on")
+	}
+}
+
+func TestResolveSourceTimeout(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", config.DefaultSourceTimeout},
+		{"10s", 10 * time.Second},
+		{"5", 5 * time.Second},
+		{"off", 0},
+		{"0", 0},
+		{"soon", config.DefaultSourceTimeout},
+	}
+	for _, tt := range tests {
+		m := tmux.NewMockRunner()
+		if tt.value != "" {
+			m.Options["@tpack-source-timeout"] = tt.value
+		}
+		cfg, err := config.Resolve(m, testOpts(config.NewMockFS())...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.SourceTimeout != tt.want {
+			t.Errorf("@tpack-source-timeout %q: SourceTimeout = %v, want %v", tt.value, cfg.SourceTimeout, tt.want)
+		}
+	}
+}
+
+func TestResolveSourceEnv(t *testing.T) {
+	m := tmux.NewMockRunner()
+	cfg, err := config.Resolve(m, testOpts(config.NewMockFS())...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SourceEnv != nil {
+		t.Errorf("expected scripts to get the whole environment by default, got %v", cfg.SourceEnv)
+	}
+
+	m.Options["@tpack-source-env"] = "LANG, LC_* SSH_AUTH_SOCK"
+	cfg, err = config.Resolve(m, testOpts(config.NewMockFS())...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(cfg.SourceEnv, " "); got != "LANG LC_* SSH_AUTH_SOCK" {
+		t.Errorf("SourceEnv = %v", cfg.SourceEnv)
+	}
+}
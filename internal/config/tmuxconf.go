@@ -1,67 +1,210 @@
 package config
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/tmuxpack/tpack/internal/plug"
 	"github.com/tmuxpack/tpack/internal/tmux"
+	"github.com/tmuxpack/tpack/internal/tmuxconf"
 )
 
 // Collects all plugin definitions from:
 // 1. Legacy @tpm_plugins tmux option
-// 2. New @plugin syntax in tmux.conf + /etc/tmux.conf + sourced files (one level deep)
+// 2. New @plugin syntax in /etc/tmux.conf + tmux.conf + every file they
+// source, recursively, skipping declarations guarded by a false %if or
+// if-shell condition
 // TODO: Move to a separate config structure down the line, mayybe something akin to LazyVim
 func GatherPlugins(runner tmux.Runner, fs FS, tmuxConf, home, xdgConfigHome string) []plug.Plugin {
-	var specs []string
+	g := newGatherer(runner, fs, home, xdgConfigHome)
 
-	if legacy, err := runner.ShowOption("@tpm_plugins"); err == nil && legacy != "" {
+	if legacy, err := runner.ShowOption(plug.LegacyOption); err == nil && legacy != "" {
 		for s := range strings.FieldsSeq(legacy) {
 			s = strings.TrimSpace(s)
 			if s != "" {
-				specs = append(specs, s)
+				g.decls = append(g.decls, declared{spec: s})
 			}
 		}
 	}
 
-	// New syntax: read config content.
-	content := configContent(fs, tmuxConf, home, xdgConfigHome)
-	specs = append(specs, plug.ExtractPluginsFromConfig(content)...)
+	g.source("/etc/tmux.conf")
+	g.source(tmuxConf)
 
-	// Parse all specs into Plugin structs.
+	// Parse all specs into Plugin structs. Local paths are relative to the
+	// file declaring them, or to tmux.conf for @tpm_plugins.
 	var plugins []plug.Plugin
-	for _, raw := range specs {
-		plugins = append(plugins, plug.ParseSpec(raw))
+	for _, d := range g.decls {
+		p := plug.ParseSpec(d.spec)
+		p.SourceFile, p.SourceLine = d.file, d.line
+		if p.IsLocal() {
+			from := d.file
+			if from == "" {
+				from = tmuxConf
+			}
+			p.LocalPath = plug.ResolveLocalPath(p.LocalPath, filepath.Dir(from), home, xdgConfigHome)
+		}
+		plugins = append(plugins, p)
 	}
 	return plugins
 }
 
-// configContent reads /etc/tmux.conf + user tmux.conf + one level of sourced files.
-func configContent(fs FS, tmuxConf, home, xdgConfigHome string) string {
-	var b strings.Builder
+// Source is a file named by a source-file command reached from tmux.conf.
+type Source struct {
+	// Path is the sourced file, or the glob pattern, after expansion.
+	Path string
+	// File and Line locate the source-file command.
+	File string
+	Line int
+	// Err is set when Path can't be read, or the pattern matches nothing.
+	Err error
+}
+
+// ErrNoMatch is the Source.Err of a glob pattern that matches no files.
+var ErrNoMatch = errors.New("matches no files")
+
+// SourcedFiles walks tmux.conf as GatherPlugins does and returns every file
+// it sources, directly or through other sourced files, in load order. Files
+// guarded by a false %if or if-shell condition are not included.
+func SourcedFiles(runner tmux.Runner, fs FS, tmuxConf, home, xdgConfigHome string) []Source {
+	g := newGatherer(runner, fs, home, xdgConfigHome)
+	g.source("/etc/tmux.conf")
+	g.source(tmuxConf)
+	return g.sources
+}
+
+// declared is a plugin spec and the config file and line that declare it
+// (empty for @tpm_plugins).
+type declared struct {
+	spec string
+	file string
+	line int
+}
+
+// gatherer walks config files the way tmux loads them, collecting @plugin
+// declarations in order and descending into source-file commands in place.
+type gatherer struct {
+	runner        tmux.Runner
+	fs            FS
+	ev            tmuxconf.Evaluator
+	home          string
+	xdgConfigHome string
+	// seen holds every file already loaded. Loading each file once breaks
+	// source-file cycles and avoids declaring a plugin twice.
+	seen    map[string]bool
+	decls   []declared
+	sources []Source
+}
 
-	// /etc/tmux.conf (system config)
-	if data, err := fs.ReadFile("/etc/tmux.conf"); err == nil {
-		b.Write(data)
+func newGatherer(runner tmux.Runner, fs FS, home, xdgConfigHome string) *gatherer {
+	return &gatherer{
+		runner:        runner,
+		fs:            fs,
+		ev:            newConditions(runner),
+		home:          home,
+		xdgConfigHome: xdgConfigHome,
+		seen:          make(map[string]bool),
 	}
+}
 
-	// User tmux.conf
-	if data, err := fs.ReadFile(tmuxConf); err == nil {
-		if b.Len() > 0 {
-			b.WriteByte('\n')
-		}
-		b.Write(data)
+// source loads a config file and everything it sources, returning the
+// error reading it. Unreadable files are skipped, as with source-file -q.
+// Like tmux, a syntax error keeps the commands before it.
+func (g *gatherer) source(name string) error {
+	name = filepath.Clean(name)
+	if g.seen[name] {
+		return nil
 	}
+	g.seen[name] = true
 
-	base := b.String()
+	data, err := g.fs.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	f, _ := tmuxconf.Parse(name, data)
 
-	// Sourced files (one level deep, not recursive).
-	for _, file := range plug.ExtractSourcedFiles(base) {
-		expanded := plug.ManualExpansion(file, home, xdgConfigHome)
-		if data, err := fs.ReadFile(expanded); err == nil {
-			b.WriteByte('\n')
-			b.Write(data)
+	active := f.Active(g.ev)
+	for i := range active.Commands {
+		c := &active.Commands[i]
+		if spec, ok := c.PluginSpec(); ok {
+			g.decls = append(g.decls, declared{spec: spec, file: name, line: c.Pos.Line})
+			continue
+		}
+		paths, format := c.SourcePaths()
+		for _, path := range paths {
+			pattern, matches := g.resolve(path, name, format)
+			if pattern != "" && len(matches) == 0 {
+				g.sources = append(g.sources, Source{Path: pattern, File: name, Line: c.Pos.Line, Err: ErrNoMatch})
+			}
+			for _, match := range matches {
+				// Record the reference before descending, so sources
+				// are listed in load order.
+				i := len(g.sources)
+				g.sources = append(g.sources, Source{Path: match, File: name, Line: c.Pos.Line})
+				g.sources[i].Err = g.source(match)
+			}
 		}
 	}
+	return nil
+}
+
+// resolve turns a source-file argument into the files it names: formats
+// (-F) or environment variables are expanded, relative paths are taken
+// from the sourcing file's directory, and glob patterns are expanded.
+// pattern is the expanded glob pattern, empty unless path is one.
+func (g *gatherer) resolve(path, from string, format bool) (pattern string, files []string) {
+	if format {
+		var ok bool
+		if path, ok = g.expandFormat(path, from); !ok {
+			return "", nil
+		}
+	} else {
+		path = g.expandEnv(plug.ManualExpansion(path, g.home, g.xdgConfigHome))
+	}
 
-	return b.String()
+	if path == "" {
+		return "", nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(from), path)
+	}
+	if !strings.ContainsAny(path, "*?[") {
+		return "", []string{path}
+	}
+	matches, err := g.fs.Glob(path)
+	if err != nil {
+		return path, nil
+	}
+	return path, matches
+}
+
+// expandFormat expands a source-file -F path. The current_file variables
+// tmux sets while sourcing are substituted here, since the server doesn't
+// know which file is being read; anything else is expanded by tmux.
+func (g *gatherer) expandFormat(path, from string) (string, bool) {
+	path = strings.NewReplacer(
+		"#{current_file}", from,
+		"#{d:current_file}", filepath.Dir(from),
+		"#{b:current_file}", filepath.Base(from),
+	).Replace(path)
+	if !strings.Contains(path, "#{") {
+		return path, true
+	}
+	expanded, err := g.runner.DisplayFormat(path)
+	return expanded, err == nil
+}
+
+// expandEnv expands $VAR and ${VAR} from the tmux global environment,
+// falling back to tpack's own environment.
+func (g *gatherer) expandEnv(path string) string {
+	if !strings.Contains(path, "$") {
+		return path
+	}
+	return os.Expand(path, func(name string) string {
+		if v, err := g.runner.ShowEnvironment(name); err == nil {
+			return v
+		}
+		return os.Getenv(name)
+	})
 }
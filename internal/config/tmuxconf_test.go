@@ -1,9 +1,12 @@
 package config_test
 
 import (
+	"errors"
+	"slices"
 	"testing"
 
 	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/plug"
 	"github.com/tmuxpack/tpack/internal/tmux"
 )
 
@@ -108,3 +111,245 @@ func TestGatherPluginsWithBranch(t *testing.T) {
 		t.Errorf("Branch = %q, want %q", plugins[0].Branch, "develop")
 	}
 }
+
+func TestGatherPluginsResolvesLocalPaths(t *testing.T) {
+	m := tmux.NewMockRunner()
+	fs := config.NewMockFS()
+	fs.Files["/home/user/.config/tmux/tmux.conf"] = `
+set -g @plugin "file:~/src/tmux-foo"
+set -g @plugin "path=plugins/tmux-bar"
+`
+
+	plugins := config.GatherPlugins(m, fs, "/home/user/.config/tmux/tmux.conf", "/home/user", "")
+	if len(plugins) != 2 {
+		t.Fatalf("expected 2 plugins, got %d", len(plugins))
+	}
+	if plugins[0].LocalPath != "/home/user/src/tmux-foo" {
+		t.Errorf("plugin[0].LocalPath = %q", plugins[0].LocalPath)
+	}
+	if plugins[1].LocalPath != "/home/user/.config/tmux/plugins/tmux-bar" {
+		t.Errorf("plugin[1].LocalPath = %q", plugins[1].LocalPath)
+	}
+}
+
+func TestGatherPluginsResolvesLocalPathsFromSourcingFile(t *testing.T) {
+	m := tmux.NewMockRunner()
+	fs := config.NewMockFS()
+	fs.Files["/home/user/.tmux.conf"] = "source ~/dotfiles/tmux/plugins.conf\n"
+	fs.Files["/home/user/dotfiles/tmux/plugins.conf"] = `
+set -g @plugin "file:local/tmux-foo"
+set -g @plugin "me/tmux-bar path=../src/tmux-bar"
+`
+
+	plugins := config.GatherPlugins(m, fs, "/home/user/.tmux.conf", "/home/user", "")
+	if len(plugins) != 2 {
+		t.Fatalf("expected 2 plugins, got %d", len(plugins))
+	}
+	if plugins[0].LocalPath != "/home/user/dotfiles/tmux/local/tmux-foo" {
+		t.Errorf("plugin[0].LocalPath = %q", plugins[0].LocalPath)
+	}
+	if plugins[1].LocalPath != "/home/user/dotfiles/src/tmux-bar" {
+		t.Errorf("plugin[1].LocalPath = %q", plugins[1].LocalPath)
+	}
+}
+
+func pluginNames(plugins []plug.Plugin) []string {
+	var names []string
+	for _, p := range plugins {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestGatherPluginsEvaluatesDirectives(t *testing.T) {
+	m := tmux.NewMockRunner()
+	m.Formats["#{==:#{host},work-laptop}"] = "1"
+	m.Formats["#{==:#{host},home}"] = "0"
+	fs := config.NewMockFS()
+	fs.Files["/home/user/.tmux.conf"] = `
+set -g @plugin "tmux-plugins/tmux-sensible"
+%if "#{==:#{host},work-laptop}"
+set -g @plugin "corp/tmux-vpn"
+%endif
+%if "#{==:#{host},home}"
+set -g @plugin "me/tmux-media"
+source-file ~/.tmux/home.conf
+%endif
+`
+	fs.Files["/home/user/.tmux/home.conf"] = `set -g @plugin "me/tmux-home"`
+
+	plugins := config.GatherPlugins(m, fs, "/home/user/.tmux.conf", "/home/user", "")
+
+	want := []string{"tmux-sensible", "tmux-vpn"}
+	if got := pluginNames(plugins); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestGatherPluginsDirectivesWithoutServer(t *testing.T) {
+	m := tmux.NewMockRunner()
+	m.Errors["DisplayFormat:#{==:#{host},work-laptop}"] = errors.New("no server running")
+	fs := config.NewMockFS()
+	fs.Files["/home/user/.tmux.conf"] = `
+%if "#{==:#{host},work-laptop}"
+set -g @plugin "corp/tmux-vpn"
+%else
+set -g @plugin "me/tmux-media"
+%endif
+`
+
+	plugins := config.GatherPlugins(m, fs, "/home/user/.tmux.conf", "/home/user", "")
+
+	// An undecidable condition keeps every branch.
+	want := []string{"tmux-vpn", "tmux-media"}
+	if got := pluginNames(plugins); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestGatherPluginsIfShell(t *testing.T) {
+	conf := `
+if-shell 'true' 'set -g @plugin "me/tmux-yes"' 'set -g @plugin "me/tmux-no"'
+if-shell 'false' {
+  set -g @plugin "me/tmux-never"
+}
+`
+	tests := []struct {
+		name   string
+		option string
+		want   []string
+	}{
+		{"opted out keeps every branch", "", []string{"tmux-yes", "tmux-no", "tmux-never"}},
+		{"opted in runs conditions", "on", []string{"tmux-yes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tmux.NewMockRunner()
+			m.Options[config.EvalIfShellOption] = tt.option
+			fs := config.NewMockFS()
+			fs.Files["/home/user/.tmux.conf"] = conf
+
+			plugins := config.GatherPlugins(m, fs, "/home/user/.tmux.conf", "/home/user", "")
+
+			if got := pluginNames(plugins); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGatherPluginsFollowsSourcesRecursively(t *testing.T) {
+	m := tmux.NewMockRunner()
+	m.Environment["DOTFILES"] = "/home/user/dotfiles"
+	fs := config.NewMockFS()
+	fs.Files["/home/user/.tmux.conf"] = `
+set -g @plugin "a/first"
+source-file ~/.config/tmux/conf.d/*.conf
+set -g @plugin "a/last"
+`
+	fs.Files["/home/user/.config/tmux/conf.d/10-theme.conf"] = `
+set -g @plugin "b/theme"
+source nested/extra.conf
+`
+	fs.Files["/home/user/.config/tmux/conf.d/nested/extra.conf"] = `
+set -g @plugin "c/nested"
+source-file -q $DOTFILES/tmux/keys.conf
+`
+	fs.Files["/home/user/dotfiles/tmux/keys.conf"] = `set -g @plugin "d/keys"`
+	fs.Files["/home/user/.config/tmux/conf.d/20-tools.conf"] = `set -g @plugin "e/tools"`
+
+	plugins := config.GatherPlugins(m, fs, "/home/user/.tmux.conf", "/home/user", "/home/user/.config")
+
+	// Sourced files are read in place, in glob order.
+	want := []string{"first", "theme", "nested", "keys", "tools", "last"}
+	if got := pluginNames(plugins); !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got := plugins[2].Origin(); got != "/home/user/.config/tmux/conf.d/nested/extra.conf:2" {
+		t.Errorf("Origin() = %q", got)
+	}
+	if got := plugins[5].Origin(); got != "/home/user/.tmux.conf:4" {
+		t.Errorf("Origin() = %q", got)
+	}
+}
+
+func TestGatherPluginsSourceCycle(t *testing.T) {
+	m := tmux.NewMockRunner()
+	fs := config.NewMockFS()
+	fs.Files["/home/user/.tmux.conf"] = "source ~/a.conf\nset -g @plugin 'x/main'"
+	fs.Files["/home/user/a.conf"] = "set -g @plugin 'x/a'\nsource ~/b.conf"
+	fs.Files["/home/user/b.conf"] = "set -g @plugin 'x/b'\nsource ~/a.conf\nsource ~/.tmux.conf"
+
+	plugins := config.GatherPlugins(m, fs, "/home/user/.tmux.conf", "/home/user", "")
+
+	want := []string{"a", "b", "main"}
+	if got := pluginNames(plugins); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestGatherPluginsFormatSourcePaths(t *testing.T) {
+	m := tmux.NewMockRunner()
+	m.Formats["/home/user/.tmux/#{host}.conf"] = "/home/user/.tmux/work.conf"
+	fs := config.NewMockFS()
+	fs.Files["/home/user/.tmux/tmux.conf"] = `
+source-file -F '#{d:current_file}/plugins.conf'
+source-file -F '#{d:current_file}/#{host}.conf'
+`
+	fs.Files["/home/user/.tmux/plugins.conf"] = `set -g @plugin 'x/shared'`
+	fs.Files["/home/user/.tmux/work.conf"] = `set -g @plugin 'x/work'`
+
+	plugins := config.GatherPlugins(m, fs, "/home/user/.tmux/tmux.conf", "/home/user", "")
+
+	want := []string{"shared", "work"}
+	if got := pluginNames(plugins); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSourcedFiles(t *testing.T) {
+	m := tmux.NewMockRunner()
+	fs := config.NewMockFS()
+	fs.Files["/home/user/.tmux.conf"] = "source ~/a.conf\nsource-file ~/conf.d/*.conf\n"
+	fs.Files["/home/user/a.conf"] = "source nested/missing.conf\nsource ~/.tmux.conf\n"
+
+	sources := config.SourcedFiles(m, fs, "/home/user/.tmux.conf", "/home/user", "")
+
+	want := []struct {
+		path, file string
+		line       int
+		ok         bool
+	}{
+		{"/home/user/a.conf", "/home/user/.tmux.conf", 1, true},
+		{"/home/user/nested/missing.conf", "/home/user/a.conf", 1, false},
+		{"/home/user/.tmux.conf", "/home/user/a.conf", 2, true},
+		{"/home/user/conf.d/*.conf", "/home/user/.tmux.conf", 2, false},
+	}
+	if len(sources) != len(want) {
+		t.Fatalf("expected %d sources, got %+v", len(want), sources)
+	}
+	for i, w := range want {
+		s := sources[i]
+		if s.Path != w.path || s.File != w.file || s.Line != w.line || (s.Err == nil) != w.ok {
+			t.Errorf("source %d = %+v, want %+v", i, s, w)
+		}
+	}
+	if !errors.Is(sources[3].Err, config.ErrNoMatch) {
+		t.Errorf("expected ErrNoMatch for an unmatched pattern, got %v", sources[3].Err)
+	}
+}
+
+func TestGatherPluginsLegacyHasNoSourceFile(t *testing.T) {
+	m := tmux.NewMockRunner()
+	m.Options["@tpm_plugins"] = "tmux-plugins/tpm"
+	fs := config.NewMockFS()
+
+	plugins := config.GatherPlugins(m, fs, "/home/user/.tmux.conf", "/home/user", "")
+
+	if len(plugins) != 1 || plugins[0].SourceFile != "" {
+		t.Fatalf("expected one legacy plugin without a source file, got %+v", plugins)
+	}
+	if got := plugins[0].Origin(); got != "@tpm_plugins" {
+		t.Errorf("Origin() = %q", got)
+	}
+}
@@ -1,65 +1,79 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-	"os"
-	"strings"
 
 	"github.com/tmuxpack/tpack/internal/plug"
 )
 
-// Adds a `set -g @plugin "repo"` line to the tmux.conf file if not already there.
-// TODO: Should find where other plugins are and inserted near them
-func AppendPlugin(confPath string, repo string) error {
-	data, err := os.ReadFile(confPath)
+// Adds a `set -g @plugin "repo"` line to the tmux.conf file if not already
+// there. It goes into the existing @plugin block, in the style of the
+// declarations around it.
+func AppendPlugin(confPath string, repo string, opts ...EditOption) error {
+	e, err := EditFile(confPath, opts...)
 	if err != nil {
-		return fmt.Errorf("read tmux.conf: %w", err)
+		return err
 	}
+	if !e.Add(repo) {
+		return nil
+	}
+	return e.Save()
+}
 
-	content := string(data)
-	for line := range strings.SplitSeq(content, "\n") {
-		if plug.MatchesPluginLine(line, repo) {
-			return nil
+// PluginsFile returns the config file new declarations go in: the one that
+// declares the last of plugins, so plugins kept in a file of their own stay
+// together, or tmuxConf if none is declared in a file. The manifest is
+// never picked.
+func PluginsFile(plugins []plug.Plugin, tmuxConf string) string {
+	for i := len(plugins) - 1; i >= 0; i-- {
+		if plugins[i].SourceFile != "" && !IsManifest(plugins[i].SourceFile) {
+			return plugins[i].SourceFile
 		}
 	}
+	return tmuxConf
+}
 
-	line := fmt.Sprintf("set -g @plugin \"%s\"\n", repo)
+// removes plugin from tmux.conf if found
+func RemovePlugin(confPath string, spec string, opts ...EditOption) error {
+	_, err := removeDecls(confPath, spec, opts)
+	return err
+}
 
-	if len(content) > 0 && !strings.HasSuffix(content, "\n") {
-		line = "\n" + line
-	}
+// ErrNotDeclared is returned by RemoveDeclaration when no top-level @plugin
+// declaration matches, e.g. because the plugin comes from @tpm_plugins or
+// the manifest, or is declared inside an if-shell command.
+var ErrNotDeclared = errors.New("no matching @plugin declaration")
 
-	f, err := os.OpenFile(confPath, os.O_APPEND|os.O_WRONLY, 0o600)
+// RemoveDeclaration removes the @plugin declarations of raw from the config
+// file that declares it, which may be a file sourced from tmux.conf. raw is
+// the declared spec as written, branch, version, and options included.
+func RemoveDeclaration(file, raw string, opts ...EditOption) error {
+	if file == "" {
+		return fmt.Errorf("%s is set by %s, edit it by hand: %w", raw, plug.LegacyOption, ErrNotDeclared)
+	}
+	if IsManifest(file) {
+		return fmt.Errorf("%s is declared in the manifest %s, edit it by hand: %w", raw, file, ErrNotDeclared)
+	}
+	found, err := removeDecls(file, raw, opts)
 	if err != nil {
-		return fmt.Errorf("open tmux.conf: %w", err)
+		return err
 	}
-	defer f.Close()
-
-	_, err = f.WriteString(line)
-	return err
+	if !found {
+		return fmt.Errorf("%s in %s: %w", raw, file, ErrNotDeclared)
+	}
+	return nil
 }
 
-// removes plugin from tmux.conf if found
-func RemovePlugin(confPath string, spec string) error {
-	data, err := os.ReadFile(confPath)
+// removeDecls removes every top-level declaration of spec from path and
+// reports whether there was one.
+func removeDecls(path, spec string, opts []EditOption) (bool, error) {
+	e, err := EditFile(path, opts...)
 	if err != nil {
-		return fmt.Errorf("read tmux.conf: %w", err)
+		return false, err
 	}
-
-	lines := strings.Split(string(data), "\n")
-	var kept []string
-	found := false
-	for _, line := range lines {
-		if plug.MatchesPluginLine(line, spec) {
-			found = true
-			continue
-		}
-		kept = append(kept, line)
+	if !e.Remove(spec) {
+		return false, nil
 	}
-
-	if !found {
-		return nil
-	}
-
-	return os.WriteFile(confPath, []byte(strings.Join(kept, "\n")), 0o600) //nolint:gosec // confPath is resolved from user config
+	return true, e.Save()
 }
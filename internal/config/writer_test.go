@@ -1,9 +1,12 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/tmuxpack/tpack/internal/plug"
 )
 
 func TestAppendPlugin(t *testing.T) {
@@ -20,7 +23,8 @@ func TestAppendPlugin(t *testing.T) {
 	data, _ := os.ReadFile(tmp)
 	content := string(data)
 
-	if !strings.Contains(content, `set -g @plugin "catppuccin/tmux"`) {
+	// The new line copies the quoting of the existing declaration.
+	if !strings.Contains(content, `set -g @plugin 'catppuccin/tmux'`) {
 		t.Errorf("expected plugin line in file, got:\n%s", content)
 	}
 
@@ -158,3 +162,101 @@ set -g mouse on
 		t.Error("non-plugin content should be preserved")
 	}
 }
+
+func TestRemovePlugin_TmuxSyntax(t *testing.T) {
+	tmp := t.TempDir() + "/tmux.conf"
+	initial := `set -ga @plugin 'catppuccin/tmux' # theme
+set -g @plugin a ; set-option -gq @plugin "catppuccin/tmux"
+set -g \
+    @plugin catppuccin/tmux
+set -g mouse on
+`
+	if err := os.WriteFile(tmp, []byte(initial), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RemovePlugin(tmp, "catppuccin/tmux"); err != nil {
+		t.Fatalf("RemovePlugin: %v", err)
+	}
+
+	data, _ := os.ReadFile(tmp)
+	if want := "set -g @plugin a\nset -g mouse on\n"; string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestAppendPlugin_DetectsTmuxSyntax(t *testing.T) {
+	tmp := t.TempDir() + "/tmux.conf"
+	initial := "set -g mouse on; set-option -gq @plugin \\\n  'catppuccin/tmux'\n"
+	if err := os.WriteFile(tmp, []byte(initial), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AppendPlugin(tmp, "catppuccin/tmux"); err != nil {
+		t.Fatalf("AppendPlugin: %v", err)
+	}
+
+	data, _ := os.ReadFile(tmp)
+	if string(data) != initial {
+		t.Errorf("expected file unchanged, got:\n%s", data)
+	}
+}
+
+func TestRemoveDeclaration(t *testing.T) {
+	dir := t.TempDir()
+	sourced := dir + "/plugins.conf"
+	initial := "set -g @plugin 'tmux-plugins/tpm'\nset -g @plugin 'catppuccin/tmux@v2.1.0 alias=theme'\n"
+	if err := os.WriteFile(sourced, []byte(initial), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RemoveDeclaration(sourced, "catppuccin/tmux@v2.1.0 alias=theme"); err != nil {
+		t.Fatalf("RemoveDeclaration: %v", err)
+	}
+
+	data, _ := os.ReadFile(sourced)
+	if want := "set -g @plugin 'tmux-plugins/tpm'\n"; string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestRemoveDeclaration_NotDeclared(t *testing.T) {
+	tmp := t.TempDir() + "/tmux.conf"
+	initial := "if-shell true 'set -g @plugin catppuccin/tmux'\n"
+	if err := os.WriteFile(tmp, []byte(initial), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RemoveDeclaration(tmp, "catppuccin/tmux"); !errors.Is(err, ErrNotDeclared) {
+		t.Errorf("expected ErrNotDeclared, got %v", err)
+	}
+	data, _ := os.ReadFile(tmp)
+	if string(data) != initial {
+		t.Errorf("file should be unchanged, got:\n%s", data)
+	}
+}
+
+func TestRemoveDeclaration_Legacy(t *testing.T) {
+	err := RemoveDeclaration("", "catppuccin/tmux")
+	if !errors.Is(err, ErrNotDeclared) || !strings.Contains(err.Error(), "@tpm_plugins") {
+		t.Errorf("expected ErrNotDeclared mentioning @tpm_plugins, got %v", err)
+	}
+}
+
+func TestPluginsFile(t *testing.T) {
+	plugins := []plug.Plugin{
+		{Name: "a", SourceFile: "/home/user/.tmux.conf"},
+		{Name: "b", SourceFile: "/home/user/.config/tmux/plugins.conf"},
+		{Name: "legacy"},
+	}
+	if got := PluginsFile(plugins, "/home/user/.tmux.conf"); got != "/home/user/.config/tmux/plugins.conf" {
+		t.Errorf("PluginsFile = %q", got)
+	}
+	if got := PluginsFile(plugins[2:], "/home/user/.tmux.conf"); got != "/home/user/.tmux.conf" {
+		t.Errorf("PluginsFile without declaring files = %q", got)
+	}
+	withManifest := append(plugins, plug.Plugin{Name: "m", SourceFile: "/home/user/.config/tmux/tpack.yml"})
+	if got := PluginsFile(withManifest, "/home/user/.tmux.conf"); got != "/home/user/.config/tmux/plugins.conf" {
+		t.Errorf("PluginsFile after a manifest plugin = %q", got)
+	}
+}
@@ -0,0 +1,403 @@
+// Package doctor diagnoses common problems with a tpack setup.
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/git"
+	gitbackend "github.com/tmuxpack/tpack/internal/git/backend"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/tmux"
+)
+
+// Status is the outcome of a single check.
+type Status int
+
+const (
+	Pass Status = iota
+	Warn
+	Fail
+)
+
+func (s Status) String() string {
+	switch s {
+	case Pass:
+		return "pass"
+	case Warn:
+		return "warn"
+	case Fail:
+		return "fail"
+	default:
+		return "unknown"
+	}
+}
+
+// Check is one line of the doctor report.
+type Check struct {
+	Name    string
+	Status  Status
+	Message string
+	// Fix suggests how to resolve a warning or failure.
+	Fix string
+}
+
+// Env holds everything the checks inspect.
+type Env struct {
+	Runner        tmux.Runner
+	Config        *config.Config
+	FS            config.FS
+	Validator     git.Validator
+	Plugins       []plug.Plugin
+	Home          string
+	XDGConfigHome string
+	// GitVersion returns the output of `git --version`. Defaults to
+	// running git from PATH.
+	GitVersion func() (string, error)
+}
+
+// Run performs every check and returns the results in report order.
+func Run(env Env) []Check {
+	if env.GitVersion == nil {
+		env.GitVersion = gitVersion
+	}
+
+	checks := []Check{tmuxVersion(env)}
+	checks = append(checks, configPaths(env)...)
+	checks = append(checks, pluginPathWritable(env))
+	checks = append(checks, gitAvailable(env))
+	checks = append(checks, keyBindings(env)...)
+	checks = append(checks, sourcedFiles(env)...)
+	checks = append(checks, manifest(env)...)
+	checks = append(checks, duplicateNames(env)...)
+	checks = append(checks, pluginDirs(env)...)
+	return checks
+}
+
+// Failed reports whether any check failed.
+func Failed(checks []Check) bool {
+	for _, c := range checks {
+		if c.Status == Fail {
+			return true
+		}
+	}
+	return false
+}
+
+func gitVersion() (string, error) {
+	out, err := exec.Command("git", "--version").Output() //nolint:noctx // local, short-lived
+	return strings.TrimSpace(string(out)), err
+}
+
+func tmuxVersion(env Env) Check {
+	c := Check{Name: "tmux version"}
+	raw, err := env.Runner.Version()
+	if err != nil {
+		c.Status = Fail
+		c.Message = "could not run tmux: " + err.Error()
+		c.Fix = "Make sure tmux is installed and on PATH."
+		return c
+	}
+	if !tmux.IsVersionSupported(tmux.ParseVersionDigits(raw), config.SupportedTmuxVersion) {
+		c.Status = Fail
+		c.Message = raw + " is not supported"
+		c.Fix = fmt.Sprintf("Install tmux %d.%d or newer.", config.SupportedTmuxVersion/100, config.SupportedTmuxVersion%100)
+		return c
+	}
+	c.Message = raw
+	return c
+}
+
+func configPaths(env Env) []Check {
+	cfg := env.Config
+	conf := Check{Name: "tmux.conf", Message: cfg.TmuxConf}
+	if !env.FS.FileExists(cfg.TmuxConf) {
+		conf.Status = Warn
+		conf.Message = cfg.TmuxConf + " does not exist"
+		conf.Fix = "Create ~/.tmux.conf or $XDG_CONFIG_HOME/tmux/tmux.conf and declare your plugins there."
+	}
+	return []Check{
+		conf,
+		{Name: "plugin path", Message: cfg.PluginPath},
+		{Name: "lockfile", Message: cfg.LockPath},
+		{Name: "state directory", Message: cfg.StatePath},
+	}
+}
+
+func pluginPathWritable(env Env) Check {
+	path := env.Config.PluginPath
+	c := Check{Name: "plugin path writable", Message: path + " is writable"}
+
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		c.Status = Warn
+		c.Message = path + " does not exist yet"
+		c.Fix = "Run `tpack install` to create it."
+		return c
+	case err != nil:
+		c.Status = Fail
+		c.Message = err.Error()
+		return c
+	case !info.IsDir():
+		c.Status = Fail
+		c.Message = path + " is not a directory"
+		c.Fix = "Move the file out of the way, or point " + config.PluginPathEnvVar + " elsewhere."
+		return c
+	}
+
+	// Probe actual write access, as the manager does before installing.
+	f, err := os.CreateTemp(path, ".tpack-probe-*")
+	if err != nil {
+		c.Status = Fail
+		c.Message = path + " is not writable"
+		c.Fix = "Fix its permissions (e.g. `chmod u+w " + path + "`), or point " + config.PluginPathEnvVar + " elsewhere."
+		return c
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name()) //nolint:gosec // path from os.CreateTemp is safe
+	return c
+}
+
+func gitAvailable(env Env) Check {
+	c := Check{Name: "git"}
+	// The go backend runs in-process, so whether git is installed does not matter.
+	if env.Config.GitBackend == gitbackend.Go {
+		c.Message = "using the built-in git implementation"
+		return c
+	}
+	version, err := env.GitVersion()
+	if err == nil {
+		c.Message = version
+		return c
+	}
+	if env.Config.GitBackend == gitbackend.CLI {
+		c.Status = Fail
+		c.Message = "git is not available, but @tpack-git-backend is 'cli'"
+		c.Fix = "Install git, or set @tpack-git-backend to 'go' or 'auto'."
+		return c
+	}
+	c.Status = Warn
+	c.Message = "git is not available; using the built-in git implementation"
+	c.Fix = "Install git if you rely on SSH config or credential helpers."
+	return c
+}
+
+// keyBindings reports tpack keys that collide with each other or with
+// another binding in the prefix table.
+func keyBindings(env Env) []Check {
+	cfg := env.Config
+	keys := []struct{ option, key string }{
+		{config.InstallKeyOption, cfg.InstallKey},
+		{config.UpdateKeyOption, cfg.UpdateKey},
+		{config.CleanKeyOption, cfg.CleanKey},
+		{config.TuiKeyOption, cfg.TuiKey},
+	}
+
+	var checks []Check
+	owner := make(map[string]string, len(keys))
+	for _, k := range keys {
+		if prev, ok := owner[k.key]; ok {
+			checks = append(checks, Check{
+				Name:    "key bindings",
+				Status:  Fail,
+				Message: fmt.Sprintf("%s and %s are both set to %q", prev, k.option, k.key),
+				Fix:     "Set " + k.option + " to a different key.",
+			})
+			continue
+		}
+		owner[k.key] = k.option
+	}
+
+	table, err := env.Runner.ListKeys("prefix")
+	if err != nil {
+		return append(checks, Check{
+			Name:    "key bindings",
+			Status:  Warn,
+			Message: "could not list key bindings: " + err.Error(),
+			Fix:     "Run `tpack doctor` from inside a tmux session.",
+		})
+	}
+	for line := range strings.SplitSeq(table, "\n") {
+		key, command, ok := parseBinding(line)
+		if !ok {
+			continue
+		}
+		option, ours := owner[key]
+		if !ours || strings.Contains(command, "tpack") || strings.Contains(command, "tpm") {
+			continue
+		}
+		checks = append(checks, Check{
+			Name:    "key bindings",
+			Status:  Warn,
+			Message: fmt.Sprintf("prefix + %s is bound to %q, not tpack", key, command),
+			Fix:     "Remove the other binding, or set " + option + " to a different key.",
+		})
+	}
+
+	if len(checks) == 0 {
+		checks = append(checks, Check{Name: "key bindings", Message: "no conflicts"})
+	}
+	return checks
+}
+
+// parseBinding splits a `tmux list-keys` line such as
+// "bind-key -r -T prefix I run-shell foo" into its key and command.
+func parseBinding(line string) (key, command string, ok bool) {
+	fields := strings.Fields(line)
+	for i, f := range fields {
+		if f == "-T" && i+2 < len(fields) {
+			return fields[i+2], strings.Join(fields[i+3:], " "), true
+		}
+	}
+	return "", "", false
+}
+
+// sourcedFiles reports source-file lines, in tmux.conf or any file it
+// sources, whose target can't be read.
+func sourcedFiles(env Env) []Check {
+	sources := config.SourcedFiles(env.Runner, env.FS, env.Config.TmuxConf, env.Home, env.XDGConfigHome)
+
+	var checks []Check
+	for _, s := range sources {
+		switch {
+		case s.Err == nil:
+			continue
+		case errors.Is(s.Err, config.ErrNoMatch):
+			checks = append(checks, Check{
+				Name:    "sourced files",
+				Status:  Warn,
+				Message: fmt.Sprintf("%s matches no files (%s:%d)", s.Path, s.File, s.Line),
+				Fix:     "Fix or remove the source-file line.",
+			})
+		default:
+			checks = append(checks, Check{
+				Name:    "sourced files",
+				Status:  Fail,
+				Message: fmt.Sprintf("cannot read %s (%s:%d): %v", s.Path, s.File, s.Line, s.Err),
+				Fix:     "Fix the path or permissions, or remove the source-file line.",
+			})
+		}
+	}
+
+	if len(checks) == 0 {
+		checks = append(checks, Check{Name: "sourced files", Message: fmt.Sprintf("%d readable", len(sources))})
+	}
+	return checks
+}
+
+// manifest reports whether the plugin manifest, if there is one, is valid.
+func manifest(env Env) []Check {
+	path := env.Config.ManifestPath
+	if path == "" || !env.FS.FileExists(path) {
+		return nil
+	}
+	c := Check{Name: "manifest"}
+	plugins, err := config.LoadManifest(env.FS, path, "", env.Home, env.XDGConfigHome)
+	if err != nil {
+		c.Status = Fail
+		c.Message = err.Error()
+		c.Fix = "Fix the manifest; until then tpack sources only @plugin declarations and refuses to change plugins."
+		return []Check{c}
+	}
+	c.Message = fmt.Sprintf("%s: %d plugins", path, len(plugins))
+	return []Check{c}
+}
+
+// duplicateNames reports plugins that resolve to the same directory.
+func duplicateNames(env Env) []Check {
+	specs := make(map[string][]string)
+	var order []string
+	for _, p := range env.Plugins {
+		if _, seen := specs[p.Name]; !seen {
+			order = append(order, p.Name)
+		}
+		specs[p.Name] = append(specs[p.Name], p.Spec)
+	}
+
+	var checks []Check
+	for _, name := range order {
+		if len(specs[name]) < 2 {
+			continue
+		}
+		checks = append(checks, Check{
+			Name:    "plugin names",
+			Status:  Fail,
+			Message: fmt.Sprintf("%q is declared more than once: %s", name, strings.Join(specs[name], ", ")),
+			Fix:     "Remove the duplicate, or give one of them a different alias=.",
+		})
+	}
+
+	if len(checks) == 0 {
+		checks = append(checks, Check{Name: "plugin names", Message: "no duplicates"})
+	}
+	return checks
+}
+
+// pluginDirs reports declared plugins whose directory is missing or is
+// not a usable checkout, plus orphaned directories.
+func pluginDirs(env Env) []Check {
+	pluginPath := env.Config.PluginPath
+	var checks []Check
+	installed := 0
+	for _, p := range env.Plugins {
+		dir := plug.PluginPath(p.Name, pluginPath)
+		report := func(status Status, msg, fix string) {
+			checks = append(checks, Check{Name: "plugins", Status: status, Message: fmt.Sprintf("%q %s", p.Name, msg), Fix: fix})
+		}
+
+		if p.IsLocal() {
+			if _, err := os.Stat(p.LocalPath); err != nil {
+				report(Fail, "points at missing local path "+p.LocalPath, "Fix the path in its @plugin line.")
+			} else {
+				installed++
+			}
+			continue
+		}
+
+		info, err := os.Lstat(dir)
+		switch {
+		case os.IsNotExist(err):
+			report(Warn, "is not installed", "Run `tpack install`.")
+		case err != nil:
+			report(Fail, err.Error(), "")
+		case info.Mode()&os.ModeSymlink != 0:
+			if _, err := os.Stat(dir); err != nil {
+				report(Fail, "is a broken symlink", "Remove "+dir+" and run `tpack install`.")
+			} else {
+				installed++
+			}
+		case !info.IsDir():
+			report(Fail, "is a file, not a directory", "Remove "+dir+" and run `tpack install`.")
+		case !env.Validator.IsGitRepo(dir):
+			report(Fail, "is not a git repository", "Remove "+dir+" and run `tpack install`.")
+		default:
+			installed++
+		}
+	}
+
+	orphans := plug.FindOrphans(env.Plugins, pluginPath)
+	if len(orphans) > 0 {
+		names := make([]string, len(orphans))
+		for i, o := range orphans {
+			names[i] = o.Name
+		}
+		sort.Strings(names)
+		checks = append(checks, Check{
+			Name:    "plugins",
+			Status:  Warn,
+			Message: "not declared but present: " + strings.Join(names, ", "),
+			Fix:     "Run `tpack clean` to remove them.",
+		})
+	}
+
+	if len(checks) == 0 {
+		checks = append(checks, Check{Name: "plugins", Message: fmt.Sprintf("%d installed", installed)})
+	}
+	return checks
+}
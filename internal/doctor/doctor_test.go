@@ -0,0 +1,280 @@
+package doctor_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/config"
+	"github.com/tmuxpack/tpack/internal/doctor"
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/tmux"
+)
+
+// newEnv returns an Env for a healthy setup: supported tmux, git present,
+// a writable plugin path, and an existing tmux.conf.
+func newEnv(t *testing.T) (doctor.Env, *tmux.MockRunner, *git.MockValidator) {
+	t.Helper()
+	dir := t.TempDir()
+	conf := filepath.Join(dir, "tmux.conf")
+	if err := os.WriteFile(conf, []byte("set -g @plugin 'tmux-plugins/tmux-sensible'\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pluginPath := filepath.Join(dir, "plugins")
+	if err := os.Mkdir(pluginPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := tmux.NewMockRunner()
+	runner.VersionStr = "tmux 3.4"
+	validator := git.NewMockValidator()
+
+	env := doctor.Env{
+		Runner: runner,
+		Config: &config.Config{
+			PluginPath: pluginPath,
+			TmuxConf:   conf,
+			InstallKey: config.DefaultInstallKey,
+			UpdateKey:  config.DefaultUpdateKey,
+			CleanKey:   config.DefaultCleanKey,
+			TuiKey:     config.DefaultTuiKey,
+		},
+		FS:         config.RealFS{},
+		Validator:  validator,
+		Home:       dir,
+		GitVersion: func() (string, error) { return "git version 2.47.0", nil },
+	}
+	return env, runner, validator
+}
+
+// find returns the checks with the given name.
+func find(checks []doctor.Check, name string) []doctor.Check {
+	var out []doctor.Check
+	for _, c := range checks {
+		if c.Name == name {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func TestRunHealthy(t *testing.T) {
+	env, _, validator := newEnv(t)
+	dir := filepath.Join(env.Config.PluginPath, "tmux-sensible")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	validator.Valid[dir] = true
+	env.Plugins = []plug.Plugin{{Name: "tmux-sensible", Spec: "tmux-plugins/tmux-sensible"}}
+
+	checks := doctor.Run(env)
+
+	for _, c := range checks {
+		if c.Status != doctor.Pass {
+			t.Errorf("expected %s to pass, got %s: %s", c.Name, c.Status, c.Message)
+		}
+	}
+	if doctor.Failed(checks) {
+		t.Error("expected Failed to be false")
+	}
+}
+
+func TestRunUnsupportedTmux(t *testing.T) {
+	env, runner, _ := newEnv(t)
+	runner.VersionStr = "tmux 1.8"
+
+	checks := doctor.Run(env)
+
+	got := find(checks, "tmux version")
+	if len(got) != 1 || got[0].Status != doctor.Fail || got[0].Fix == "" {
+		t.Errorf("expected tmux version failure with a fix, got %+v", got)
+	}
+	if !doctor.Failed(checks) {
+		t.Error("expected Failed to be true")
+	}
+}
+
+func TestRunMissingGit(t *testing.T) {
+	env, _, _ := newEnv(t)
+	env.GitVersion = func() (string, error) { return "", errors.New("not found") }
+
+	if got := find(doctor.Run(env), "git"); got[0].Status != doctor.Warn {
+		t.Errorf("expected a warning with the auto backend, got %+v", got[0])
+	}
+
+	env.Config.GitBackend = "cli"
+	if got := find(doctor.Run(env), "git"); got[0].Status != doctor.Fail {
+		t.Errorf("expected a failure with the cli backend, got %+v", got[0])
+	}
+}
+
+func TestRunGoBackendSkipsGit(t *testing.T) {
+	env, _, _ := newEnv(t)
+	env.Config.GitBackend = "go"
+	env.GitVersion = func() (string, error) {
+		t.Error("git should not be probed with the go backend")
+		return "", errors.New("not found")
+	}
+
+	if got := find(doctor.Run(env), "git"); got[0].Status != doctor.Pass {
+		t.Errorf("expected the check to pass, got %+v", got[0])
+	}
+}
+
+func TestRunUnwritablePluginPath(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	env, _, _ := newEnv(t)
+	if err := os.Chmod(env.Config.PluginPath, 0o555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(env.Config.PluginPath, 0o755) })
+
+	got := find(doctor.Run(env), "plugin path writable")
+	if got[0].Status != doctor.Fail {
+		t.Errorf("expected failure, got %+v", got[0])
+	}
+}
+
+func TestRunKeyConflicts(t *testing.T) {
+	env, runner, _ := newEnv(t)
+	env.Config.TuiKey = env.Config.UpdateKey
+	runner.Keys["prefix"] = strings.Join([]string{
+		`bind-key    -T prefix       I                    run-shell "/usr/bin/tpack tui --install"`,
+		`bind-key -r -T prefix       M-u                  resize-pane -U 5`,
+		`bind-key    -T prefix       c                    new-window`,
+	}, "\n")
+
+	got := find(doctor.Run(env), "key bindings")
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 key binding problems, got %+v", got)
+	}
+	if got[0].Status != doctor.Fail || !strings.Contains(got[0].Message, config.TuiKeyOption) {
+		t.Errorf("expected duplicate tpack keys to fail, got %+v", got[0])
+	}
+	if got[1].Status != doctor.Warn || !strings.Contains(got[1].Message, "resize-pane") {
+		t.Errorf("expected a warning for the foreign M-u binding, got %+v", got[1])
+	}
+}
+
+func TestRunUnreadableSourcedFile(t *testing.T) {
+	env, _, _ := newEnv(t)
+	good := filepath.Join(env.Home, "good.conf")
+	if err := os.WriteFile(good, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	content := "source-file " + good + "\nsource-file ~/missing.conf\n"
+	if err := os.WriteFile(env.Config.TmuxConf, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := find(doctor.Run(env), "sourced files")
+
+	if len(got) != 1 || got[0].Status != doctor.Fail {
+		t.Fatalf("expected one failure, got %+v", got)
+	}
+	if !strings.Contains(got[0].Message, filepath.Join(env.Home, "missing.conf")) {
+		t.Errorf("expected expanded path in message, got %q", got[0].Message)
+	}
+}
+
+func TestRunUnreadableNestedSourcedFile(t *testing.T) {
+	env, _, _ := newEnv(t)
+	nested := filepath.Join(env.Home, "nested.conf")
+	if err := os.WriteFile(nested, []byte("source-file ~/deep/missing.conf\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(env.Config.TmuxConf, []byte("source-file "+nested+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := find(doctor.Run(env), "sourced files")
+
+	if len(got) != 1 || got[0].Status != doctor.Fail {
+		t.Fatalf("expected one failure, got %+v", got)
+	}
+	if !strings.Contains(got[0].Message, filepath.Join(env.Home, "deep", "missing.conf")) || !strings.Contains(got[0].Message, nested+":1") {
+		t.Errorf("expected the missing file and where it is sourced, got %q", got[0].Message)
+	}
+}
+
+func TestRunDuplicateNames(t *testing.T) {
+	env, _, _ := newEnv(t)
+	env.Plugins = []plug.Plugin{
+		{Name: "theme", Spec: "alice/theme alias=theme"},
+		{Name: "theme", Spec: "bob/theme"},
+		{Name: "tmux-yank", Spec: "tmux-plugins/tmux-yank"},
+	}
+
+	got := find(doctor.Run(env), "plugin names")
+
+	if len(got) != 1 || got[0].Status != doctor.Fail {
+		t.Fatalf("expected one failure, got %+v", got)
+	}
+	if !strings.Contains(got[0].Message, "alice/theme alias=theme, bob/theme") {
+		t.Errorf("expected both specs in message, got %q", got[0].Message)
+	}
+}
+
+func TestRunPluginDirs(t *testing.T) {
+	env, _, _ := newEnv(t)
+	pluginPath := env.Config.PluginPath
+	if err := os.Mkdir(filepath.Join(pluginPath, "not-git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(env.Home, "gone"), filepath.Join(pluginPath, "dangling")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(pluginPath, "leftover"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	env.Plugins = []plug.Plugin{
+		{Name: "not-git"},
+		{Name: "dangling"},
+		{Name: "missing"},
+		{Name: "dev", LocalPath: filepath.Join(env.Home, "src", "dev")},
+	}
+
+	got := find(doctor.Run(env), "plugins")
+
+	want := []struct {
+		status doctor.Status
+		text   string
+	}{
+		{doctor.Fail, `"not-git" is not a git repository`},
+		{doctor.Fail, `"dangling" is a broken symlink`},
+		{doctor.Warn, `"missing" is not installed`},
+		{doctor.Fail, `"dev" points at missing local path`},
+		{doctor.Warn, "not declared but present: leftover"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d plugin checks, got %+v", len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Status != w.status || !strings.Contains(got[i].Message, w.text) {
+			t.Errorf("check %d = %s %q, want %s containing %q", i, got[i].Status, got[i].Message, w.status, w.text)
+		}
+	}
+}
+
+func TestRunInvalidManifest(t *testing.T) {
+	env, _, _ := newEnv(t)
+	env.Config.ManifestPath = filepath.Join(env.Home, "tpack.yml")
+	if err := os.WriteFile(env.Config.ManifestPath, []byte("plugins:\n  - branch: main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := find(doctor.Run(env), "manifest")
+
+	if len(got) != 1 || got[0].Status != doctor.Fail || got[0].Fix == "" {
+		t.Fatalf("expected a manifest failure with a fix, got %+v", got)
+	}
+	if !strings.Contains(got[0].Message, "no source") {
+		t.Errorf("expected the parse error in the message, got %q", got[0].Message)
+	}
+}
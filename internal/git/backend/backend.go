@@ -0,0 +1,37 @@
+// Package backend selects the git implementation used by tpack.
+package backend
+
+import (
+	"os/exec"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	gitcli "github.com/tmuxpack/tpack/internal/git/cli"
+	"github.com/tmuxpack/tpack/internal/git/gogit"
+)
+
+// Backend names accepted by @tpack-git-backend.
+const (
+	Auto = "auto"
+	CLI  = "cli"
+	Go   = "go"
+)
+
+// lookPath is swapped out in tests.
+var lookPath = exec.LookPath
+
+// Select returns the git implementation for name. "cli" shells out to git,
+// "go" runs in-process with go-git, and "auto" (or empty) uses the git CLI
+// when git is on PATH and go-git otherwise.
+func Select(name string) git.Backend {
+	switch name {
+	case CLI:
+		return gitcli.NewBackend()
+	case Go:
+		return gogit.NewBackend()
+	default:
+		if _, err := lookPath("git"); err != nil {
+			return gogit.NewBackend()
+		}
+		return gitcli.NewBackend()
+	}
+}
@@ -0,0 +1,44 @@
+package backend
+
+import (
+	"errors"
+	"testing"
+
+	gitcli "github.com/tmuxpack/tpack/internal/git/cli"
+	"github.com/tmuxpack/tpack/internal/git/gogit"
+)
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		name   string
+		hasGit bool
+		wantGo bool
+	}{
+		{CLI, true, false},
+		{CLI, false, false},
+		{Go, true, true},
+		{Auto, true, false},
+		{Auto, false, true},
+		{"", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := lookPath
+			t.Cleanup(func() { lookPath = orig })
+			lookPath = func(string) (string, error) {
+				if tt.hasGit {
+					return "/usr/bin/git", nil
+				}
+				return "", errors.New("not found")
+			}
+
+			b := Select(tt.name)
+			_, isGo := b.Cloner.(*gogit.Cloner)
+			_, isCLI := b.Cloner.(*gitcli.Cloner)
+			if isGo != tt.wantGo || isCLI == tt.wantGo {
+				t.Errorf("Select(%q) with git=%v picked %T", tt.name, tt.hasGit, b.Cloner)
+			}
+		})
+	}
+}
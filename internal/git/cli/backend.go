@@ -0,0 +1,18 @@
+package cli
+
+import "github.com/tmuxpack/tpack/internal/git"
+
+// NewBackend returns git CLI implementations of every git interface.
+func NewBackend() git.Backend {
+	return git.Backend{
+		Cloner:          NewCloner(),
+		Puller:          NewPuller(),
+		Validator:       NewValidator(),
+		Fetcher:         NewFetcher(),
+		RevParser:       NewRevParser(),
+		Logger:          NewLogger(),
+		Checkouter:      NewCheckouter(),
+		WorktreeChecker: NewWorktreeChecker(),
+		TagLister:       NewTagLister(),
+	}
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Checks out specific revisions using the git CLI.
+type Checkouter struct{}
+
+func NewCheckouter() *Checkouter {
+	return &Checkouter{}
+}
+
+// Checkout hard-resets the current branch (or detached HEAD) to ref, so a
+// later pull fast-forwards from there. If ref is not available locally
+// (e.g. a shallow or single-branch clone), it is fetched from origin first.
+func (c *Checkouter) Checkout(ctx context.Context, dir, ref string) error {
+	if err := runGitQuiet(ctx, dir, "reset", "-q", "--hard", ref); err != nil {
+		if err := runGitQuiet(ctx, dir, "fetch", "-q", "origin", ref); err != nil {
+			return fmt.Errorf("git fetch %s in %s: %w", ref, dir, err)
+		}
+		if err := runGitQuiet(ctx, dir, "reset", "-q", "--hard", "FETCH_HEAD"); err != nil {
+			return fmt.Errorf("git reset %s in %s: %w", ref, dir, err)
+		}
+	}
+
+	if err := runGitQuiet(ctx, dir, "submodule", "update", "--init", "--recursive"); err != nil {
+		return fmt.Errorf("git submodule update in %s: %w", dir, err)
+	}
+	return nil
+}
+
+// runGitQuiet runs a git command in dir, folding its output into the error.
+func runGitQuiet(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cli_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	gitcli "github.com/tmuxpack/tpack/internal/git/cli"
+)
+
+func TestCheckouter_CheckoutOlderCommit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git CLI test in short mode")
+	}
+
+	bare := initBareRepo(t)
+	clone := cloneLocal(t, bare)
+
+	rp := gitcli.NewRevParser()
+	first, err := rp.RevParse(context.Background(), clone)
+	if err != nil {
+		t.Fatalf("RevParse: %v", err)
+	}
+
+	addCommitToBare(t, bare, "second.txt")
+	if _, err := gitcli.NewPuller().Pull(context.Background(), git.PullOptions{Dir: clone}); err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+
+	co := gitcli.NewCheckouter()
+	if err := co.Checkout(context.Background(), clone, first); err != nil {
+		t.Fatalf("Checkout returned error: %v", err)
+	}
+
+	got, _ := rp.RevParse(context.Background(), clone)
+	if got != first {
+		t.Errorf("HEAD = %s, want %s", got, first)
+	}
+	if _, err := os.Stat(filepath.Join(clone, "second.txt")); !os.IsNotExist(err) {
+		t.Error("expected second.txt to be gone after checkout of first commit")
+	}
+
+	// A later pull should fast-forward back to the tip.
+	if _, err := gitcli.NewPuller().Pull(context.Background(), git.PullOptions{Dir: clone}); err != nil {
+		t.Fatalf("Pull after checkout: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(clone, "second.txt")); err != nil {
+		t.Errorf("expected second.txt after pull: %v", err)
+	}
+}
+
+func TestCheckouter_FetchesMissingCommit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git CLI test in short mode")
+	}
+
+	bare := initBareRepo(t)
+	clone := cloneLocal(t, bare)
+
+	// Push a commit the clone has not fetched yet.
+	addCommitToBare(t, bare, "upstream.txt")
+	other := cloneLocal(t, bare)
+	tip, err := gitcli.NewRevParser().RevParse(context.Background(), other)
+	if err != nil {
+		t.Fatalf("RevParse: %v", err)
+	}
+
+	if err := gitcli.NewCheckouter().Checkout(context.Background(), clone, tip); err != nil {
+		t.Fatalf("Checkout returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(clone, "upstream.txt")); err != nil {
+		t.Errorf("expected upstream.txt after checkout: %v", err)
+	}
+}
+
+func TestCheckouter_UnknownRef(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git CLI test in short mode")
+	}
+
+	bare := initBareRepo(t)
+	clone := cloneLocal(t, bare)
+
+	err := gitcli.NewCheckouter().Checkout(context.Background(), clone, "0000000000000000000000000000000000000000")
+	if err == nil {
+		t.Fatal("expected error for unknown ref")
+	}
+}
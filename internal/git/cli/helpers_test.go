@@ -13,12 +13,15 @@ import (
 
 // Compile-time interface compliance checks.
 var (
-	_ git.Cloner    = (*gitcli.Cloner)(nil)
-	_ git.Puller    = (*gitcli.Puller)(nil)
-	_ git.Validator = (*gitcli.Validator)(nil)
-	_ git.Fetcher   = (*gitcli.Fetcher)(nil)
-	_ git.RevParser = (*gitcli.RevParser)(nil)
-	_ git.Logger    = (*gitcli.Logger)(nil)
+	_ git.Cloner          = (*gitcli.Cloner)(nil)
+	_ git.Puller          = (*gitcli.Puller)(nil)
+	_ git.Validator       = (*gitcli.Validator)(nil)
+	_ git.Fetcher         = (*gitcli.Fetcher)(nil)
+	_ git.RevParser       = (*gitcli.RevParser)(nil)
+	_ git.Logger          = (*gitcli.Logger)(nil)
+	_ git.Checkouter      = (*gitcli.Checkouter)(nil)
+	_ git.WorktreeChecker = (*gitcli.WorktreeChecker)(nil)
+	_ git.TagLister       = (*gitcli.TagLister)(nil)
 )
 
 // initBareRepo creates a bare git repository with a single commit on the
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Lists remote tags using git ls-remote.
+type TagLister struct{}
+
+func NewTagLister() *TagLister {
+	return &TagLister{}
+}
+
+func (c *TagLister) ListTags(ctx context.Context, url string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--tags", "--refs", url)
+	cmd.Env = append(cmd.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ls-remote --tags %s: %w", url, err)
+	}
+
+	var tags []string
+	for line := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		_, ref, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if tag, ok := strings.CutPrefix(ref, "refs/tags/"); ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
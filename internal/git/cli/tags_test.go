@@ -0,0 +1,55 @@
+package cli_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	gitcli "github.com/tmuxpack/tpack/internal/git/cli"
+)
+
+func TestTagLister_ListsTags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git CLI test in short mode")
+	}
+
+	bare := initBareRepo(t)
+	clone := cloneLocal(t, bare)
+	runGit(t, clone, "tag", "v1.0.0")
+	runGit(t, clone, "tag", "-a", "v1.1.0", "-m", "annotated")
+	runGit(t, clone, "push", "origin", "--tags")
+
+	tags, err := gitcli.NewTagLister().ListTags(context.Background(), bare)
+	if err != nil {
+		t.Fatalf("ListTags returned error: %v", err)
+	}
+	slices.Sort(tags)
+	if !slices.Equal(tags, []string{"v1.0.0", "v1.1.0"}) {
+		t.Errorf("ListTags = %v, want [v1.0.0 v1.1.0]", tags)
+	}
+}
+
+func TestTagLister_NoTags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git CLI test in short mode")
+	}
+
+	tags, err := gitcli.NewTagLister().ListTags(context.Background(), initBareRepo(t))
+	if err != nil {
+		t.Fatalf("ListTags returned error: %v", err)
+	}
+	if len(tags) != 0 {
+		t.Errorf("expected no tags, got %v", tags)
+	}
+}
+
+func TestTagLister_MissingRemote(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git CLI test in short mode")
+	}
+
+	_, err := gitcli.NewTagLister().ListTags(context.Background(), t.TempDir()+"/missing.git")
+	if err == nil {
+		t.Error("expected error for missing remote")
+	}
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Inspects working tree state using the git CLI.
+type WorktreeChecker struct{}
+
+func NewWorktreeChecker() *WorktreeChecker {
+	return &WorktreeChecker{}
+}
+
+// IsDirty reports modified or staged tracked files. Untracked files are
+// ignored since plugins commonly generate them at runtime.
+func (c *WorktreeChecker) IsDirty(ctx context.Context, dir string) (bool, error) {
+	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain", "--untracked-files=no")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("git status in %s: %w", dir, err)
+	}
+	return strings.TrimSpace(string(out)) != "", nil
+}
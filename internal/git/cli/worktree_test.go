@@ -0,0 +1,59 @@
+package cli_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	gitcli "github.com/tmuxpack/tpack/internal/git/cli"
+)
+
+func TestWorktreeChecker_Clean(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git CLI test in short mode")
+	}
+
+	clone := cloneLocal(t, initBareRepo(t))
+
+	dirty, err := gitcli.NewWorktreeChecker().IsDirty(context.Background(), clone)
+	if err != nil {
+		t.Fatalf("IsDirty returned error: %v", err)
+	}
+	if dirty {
+		t.Error("expected fresh clone to be clean")
+	}
+}
+
+func TestWorktreeChecker_ModifiedAndUntracked(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git CLI test in short mode")
+	}
+
+	clone := cloneLocal(t, initBareRepo(t))
+	wc := gitcli.NewWorktreeChecker()
+
+	// Untracked files alone do not make the tree dirty.
+	writeFile(t, filepath.Join(clone, "generated.bin"), "build output")
+	if dirty, _ := wc.IsDirty(context.Background(), clone); dirty {
+		t.Error("untracked files should not count as dirty")
+	}
+
+	writeFile(t, filepath.Join(clone, "README"), "local edit")
+	dirty, err := wc.IsDirty(context.Background(), clone)
+	if err != nil {
+		t.Fatalf("IsDirty returned error: %v", err)
+	}
+	if !dirty {
+		t.Error("expected modified tracked file to be dirty")
+	}
+}
+
+func TestWorktreeChecker_NonGitDir(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git CLI test in short mode")
+	}
+
+	if _, err := gitcli.NewWorktreeChecker().IsDirty(context.Background(), t.TempDir()); err == nil {
+		t.Fatal("expected error for non-git directory")
+	}
+}
@@ -5,10 +5,19 @@ import "context"
 // Tries cloning with the given options, and on failure
 // normalizes the URL using normalize and retries.
 func CloneWithFallback(ctx context.Context, cloner Cloner, opts CloneOptions, normalize func(string) string) error {
-	err := cloner.Clone(ctx, opts)
-	if err == nil {
-		return nil
+	_, err := CloneWithFallbackURL(ctx, cloner, opts, normalize)
+	return err
+}
+
+// Behaves like CloneWithFallback but also returns the URL
+// that was used for the successful clone.
+func CloneWithFallbackURL(ctx context.Context, cloner Cloner, opts CloneOptions, normalize func(string) string) (string, error) {
+	if err := cloner.Clone(ctx, opts); err == nil {
+		return opts.URL, nil
 	}
 	opts.URL = normalize(opts.URL)
-	return cloner.Clone(ctx, opts)
+	if err := cloner.Clone(ctx, opts); err != nil {
+		return "", err
+	}
+	return opts.URL, nil
 }
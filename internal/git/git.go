@@ -43,6 +43,14 @@ type Commit struct {
 	Message string
 }
 
+// ShortHash abbreviates a commit hash to the seven characters git shows.
+func ShortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
 // RevParser resolves git refs to commit hashes.
 type RevParser interface {
 	RevParse(ctx context.Context, dir string) (string, error)
@@ -52,3 +60,32 @@ type RevParser interface {
 type Logger interface {
 	Log(ctx context.Context, dir, fromRef, toRef string) ([]Commit, error)
 }
+
+// Checkouter moves a repository's working tree to a specific revision.
+type Checkouter interface {
+	Checkout(ctx context.Context, dir, ref string) error
+}
+
+// WorktreeChecker reports whether a repository has uncommitted changes
+// to tracked files.
+type WorktreeChecker interface {
+	IsDirty(ctx context.Context, dir string) (bool, error)
+}
+
+// TagLister lists the tags published by a remote repository.
+type TagLister interface {
+	ListTags(ctx context.Context, url string) ([]string, error)
+}
+
+// Backend bundles one implementation of every git interface.
+type Backend struct {
+	Cloner          Cloner
+	Puller          Puller
+	Validator       Validator
+	Fetcher         Fetcher
+	RevParser       RevParser
+	Logger          Logger
+	Checkouter      Checkouter
+	WorktreeChecker WorktreeChecker
+	TagLister       TagLister
+}
@@ -0,0 +1,65 @@
+package gogit
+
+import (
+	"context"
+	"fmt"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// Checks out specific revisions with go-git.
+type Checkouter struct{}
+
+func NewCheckouter() *Checkouter {
+	return &Checkouter{}
+}
+
+// Checkout hard-resets the current branch (or detached HEAD) to ref. If ref
+// is not available locally, all branches and tags are fetched from origin
+// first.
+func (c *Checkouter) Checkout(ctx context.Context, dir, ref string) error {
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", dir, err)
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		err = repo.FetchContext(ctx, &gogit.FetchOptions{
+			RemoteName: gogit.DefaultRemoteName,
+			RefSpecs: []config.RefSpec{
+				"+refs/heads/*:refs/remotes/origin/*",
+				"+refs/tags/*:refs/tags/*",
+			},
+		})
+		if err := ignoreUpToDate(err); err != nil {
+			return fmt.Errorf("fetch %s in %s: %w", ref, dir, err)
+		}
+		if hash, err = repo.ResolveRevision(plumbing.Revision(ref)); err != nil {
+			return fmt.Errorf("resolve %s in %s: %w", ref, dir, err)
+		}
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if err := wt.Reset(&gogit.ResetOptions{Commit: *hash, Mode: gogit.HardReset}); err != nil {
+		return fmt.Errorf("reset %s in %s: %w", ref, dir, err)
+	}
+
+	subs, err := wt.Submodules()
+	if err != nil {
+		return fmt.Errorf("submodule update in %s: %w", dir, err)
+	}
+	err = subs.UpdateContext(ctx, &gogit.SubmoduleUpdateOptions{
+		Init:              true,
+		RecurseSubmodules: gogit.DefaultSubmoduleRecursionDepth,
+	})
+	if err != nil {
+		return fmt.Errorf("submodule update in %s: %w", dir, err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package gogit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git/gogit"
+)
+
+func TestCheckouter_LocalCommit(t *testing.T) {
+	requireFixtures(t)
+
+	bare := initBareRepo(t)
+	clone := cloneLocal(t, bare)
+	first := gitOutput(t, clone, "rev-parse", "HEAD")
+	addCommitToBare(t, bare, "later.txt")
+	runGit(t, clone, "pull", "-q")
+
+	if err := gogit.NewCheckouter().Checkout(context.Background(), clone, first); err != nil {
+		t.Fatalf("Checkout returned error: %v", err)
+	}
+	if got := gitOutput(t, clone, "rev-parse", "HEAD"); got != first {
+		t.Errorf("HEAD = %s, want %s", got, first)
+	}
+	if _, err := os.Stat(filepath.Join(clone, "later.txt")); err == nil {
+		t.Error("expected later.txt to be gone after reset")
+	}
+}
+
+func TestCheckouter_FetchesMissingRevision(t *testing.T) {
+	requireFixtures(t)
+
+	bare := initBareRepo(t)
+	clone := cloneLocal(t, bare)
+	want := addCommitToBare(t, bare, "remote-only.txt")
+
+	if err := gogit.NewCheckouter().Checkout(context.Background(), clone, want); err != nil {
+		t.Fatalf("Checkout returned error: %v", err)
+	}
+	if got := gitOutput(t, clone, "rev-parse", "HEAD"); got != want {
+		t.Errorf("HEAD = %s, want %s", got, want)
+	}
+}
+
+func TestCheckouter_UnknownRef(t *testing.T) {
+	requireFixtures(t)
+
+	clone := cloneLocal(t, initBareRepo(t))
+	if err := gogit.NewCheckouter().Checkout(context.Background(), clone, "v9.9.9"); err == nil {
+		t.Error("expected error for unknown ref")
+	}
+}
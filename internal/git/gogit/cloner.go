@@ -0,0 +1,75 @@
+package gogit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+)
+
+// Clones git repositories in-process with go-git.
+type Cloner struct{}
+
+func NewCloner() *Cloner {
+	return &Cloner{}
+}
+
+// Clone mirrors "git clone --single-branch --recursive -b <branch>": the
+// branch option may name either a branch or a tag.
+func (c *Cloner) Clone(ctx context.Context, opts git.CloneOptions) error {
+	co := &gogit.CloneOptions{
+		URL:               remoteURL(opts.URL),
+		SingleBranch:      true,
+		Depth:             opts.Depth,
+		RecurseSubmodules: gogit.DefaultSubmoduleRecursionDepth,
+	}
+	if opts.Branch != "" {
+		co.ReferenceName = plumbing.NewBranchReferenceName(opts.Branch)
+	}
+
+	_, err := gogit.PlainCloneContext(ctx, opts.Dir, false, co)
+	if err != nil && opts.Branch != "" && errors.Is(err, gogit.NoMatchingRefSpecError{}) {
+		co.ReferenceName = plumbing.NewTagReferenceName(opts.Branch)
+		_, err = gogit.PlainCloneContext(ctx, opts.Dir, false, co)
+	}
+	if err != nil {
+		return fmt.Errorf("clone %s: %w", opts.URL, err)
+	}
+	return trackCheckedOutBranch(opts.Dir)
+}
+
+// trackCheckedOutBranch makes origin fetch the checked-out branch into
+// refs/remotes/origin/<branch>, as "git clone --single-branch" does. Without
+// an explicit branch go-git tracks only origin/HEAD, which leaves the branch
+// with no upstream for fetches and update checks.
+func trackCheckedOutBranch(dir string) error {
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		return err
+	}
+	head, err := repo.Head()
+	if err != nil || !head.Name().IsBranch() {
+		return err
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	remote, ok := cfg.Remotes[gogit.DefaultRemoteName]
+	if !ok {
+		return nil
+	}
+	branch := head.Name().Short()
+	tracking := plumbing.NewRemoteReferenceName(gogit.DefaultRemoteName, branch)
+	remote.Fetch = []config.RefSpec{config.RefSpec("+" + head.Name().String() + ":" + tracking.String())}
+	if err := repo.SetConfig(cfg); err != nil {
+		return err
+	}
+	return repo.Storer.SetReference(plumbing.NewHashReference(tracking, head.Hash()))
+}
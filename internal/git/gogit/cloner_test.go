@@ -0,0 +1,66 @@
+package gogit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/git/gogit"
+)
+
+func TestCloner_CloneSuccess(t *testing.T) {
+	requireFixtures(t)
+
+	dst := cloneLocal(t, initBareRepo(t))
+
+	if _, err := os.Stat(filepath.Join(dst, "README")); err != nil {
+		t.Fatalf("expected README in cloned repo: %v", err)
+	}
+}
+
+func TestCloner_CloneBranchAndTag(t *testing.T) {
+	requireFixtures(t)
+
+	bare := initBareRepo(t)
+	work := filepath.Join(t.TempDir(), "work")
+	runGit(t, "", "clone", bare, work)
+	runGit(t, work, "config", "user.email", "test@test.com")
+	runGit(t, work, "config", "user.name", "Test")
+	runGit(t, work, "checkout", "-b", "feature")
+	writeFile(t, filepath.Join(work, "feature.txt"), "on feature branch")
+	runGit(t, work, "add", ".")
+	runGit(t, work, "commit", "-m", "feature commit")
+	runGit(t, work, "tag", "v1.0.0")
+	runGit(t, work, "push", "origin", "feature", "--tags")
+
+	for _, ref := range []string{"feature", "v1.0.0"} {
+		t.Run(ref, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "cloned")
+			err := gogit.NewCloner().Clone(context.Background(), git.CloneOptions{URL: bare, Dir: dst, Branch: ref})
+			if err != nil {
+				t.Fatalf("Clone returned error: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dst, "feature.txt")); err != nil {
+				t.Fatalf("expected feature.txt in cloned repo: %v", err)
+			}
+		})
+	}
+}
+
+func TestCloner_CloneInvalidURL(t *testing.T) {
+	requireFixtures(t)
+
+	dst := filepath.Join(t.TempDir(), "bad-clone")
+	err := gogit.NewCloner().Clone(context.Background(), git.CloneOptions{
+		URL: "/nonexistent/path/to/repo.git",
+		Dir: dst,
+	})
+	if err == nil {
+		t.Fatal("expected error when cloning invalid URL")
+	}
+	if _, statErr := os.Stat(dst); statErr == nil {
+		t.Error("failed clone should not leave a directory behind")
+	}
+}
@@ -0,0 +1,55 @@
+package gogit
+
+import (
+	"context"
+	"fmt"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// Checks outdated status by fetching and comparing refs with go-git.
+type Fetcher struct{}
+
+func NewFetcher() *Fetcher {
+	return &Fetcher{}
+}
+
+func (c *Fetcher) IsOutdated(ctx context.Context, dir string) (bool, error) {
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		return false, fmt.Errorf("open %s: %w", dir, err)
+	}
+
+	err = repo.FetchContext(ctx, &gogit.FetchOptions{RemoteName: gogit.DefaultRemoteName})
+	if err := ignoreUpToDate(err); err != nil {
+		return false, fmt.Errorf("fetch in %s: %w", dir, err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return false, fmt.Errorf("resolve HEAD in %s: %w", dir, err)
+	}
+	upstream, err := upstreamRef(repo, head.Name())
+	if err != nil {
+		return false, fmt.Errorf("resolve upstream in %s: %w", dir, err)
+	}
+	return head.Hash() != upstream.Hash(), nil
+}
+
+// upstreamRef resolves the remote-tracking ref configured for branch,
+// like "@{u}".
+func upstreamRef(repo *gogit.Repository, branch plumbing.ReferenceName) (*plumbing.Reference, error) {
+	if !branch.IsBranch() {
+		return nil, fmt.Errorf("HEAD is detached")
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return nil, err
+	}
+	b, ok := cfg.Branches[branch.Short()]
+	if !ok || b.Remote == "" || b.Merge == "" {
+		return nil, fmt.Errorf("no upstream configured for %s", branch.Short())
+	}
+	return repo.Reference(plumbing.NewRemoteReferenceName(b.Remote, b.Merge.Short()), true)
+}
@@ -0,0 +1,39 @@
+package gogit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git/gogit"
+)
+
+func TestFetcher_IsOutdated(t *testing.T) {
+	requireFixtures(t)
+
+	bare := initBareRepo(t)
+	clone := cloneLocal(t, bare)
+	f := gogit.NewFetcher()
+
+	outdated, err := f.IsOutdated(context.Background(), clone)
+	if err != nil {
+		t.Fatalf("IsOutdated returned error: %v", err)
+	}
+	if outdated {
+		t.Error("expected fresh clone to be up to date")
+	}
+
+	addCommitToBare(t, bare, "new.txt")
+	outdated, err = f.IsOutdated(context.Background(), clone)
+	if err != nil {
+		t.Fatalf("IsOutdated returned error: %v", err)
+	}
+	if !outdated {
+		t.Error("expected clone to be outdated after upstream commit")
+	}
+}
+
+func TestFetcher_NonGitDir(t *testing.T) {
+	if _, err := gogit.NewFetcher().IsOutdated(context.Background(), t.TempDir()); err == nil {
+		t.Error("expected error for non-git directory")
+	}
+}
@@ -0,0 +1,71 @@
+// Package gogit implements the git interfaces in-process with go-git, for
+// systems where the git binary is not available.
+package gogit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+
+	"github.com/go-git/go-billy/v5/osfs"
+
+	"github.com/tmuxpack/tpack/internal/git"
+)
+
+// go-git's file transport shells out to git-upload-pack; serve local
+// repositories in-process instead so local specs work without git.
+func init() {
+	client.InstallProtocol("file", server.NewServer(localLoader{}))
+}
+
+// localLoader opens bare repositories and working trees by path.
+type localLoader struct{}
+
+func (localLoader) Load(ep *transport.Endpoint) (storer.Storer, error) {
+	dir := ep.Path
+	if info, err := os.Stat(filepath.Join(dir, gogit.GitDirName)); err == nil && info.IsDir() {
+		dir = filepath.Join(dir, gogit.GitDirName)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config")); err != nil {
+		return nil, transport.ErrRepositoryNotFound
+	}
+	return filesystem.NewStorage(osfs.New(dir), cache.NewObjectLRUDefault()), nil
+}
+
+// NewBackend returns go-git implementations of every git interface.
+func NewBackend() git.Backend {
+	return git.Backend{
+		Cloner:          NewCloner(),
+		Puller:          NewPuller(),
+		Validator:       NewValidator(),
+		Fetcher:         NewFetcher(),
+		RevParser:       NewRevParser(),
+		Logger:          NewLogger(),
+		Checkouter:      NewCheckouter(),
+		WorktreeChecker: NewWorktreeChecker(),
+		TagLister:       NewTagLister(),
+	}
+}
+
+// remoteURL drops the "git::@" credential placeholder used to stop the git
+// CLI from prompting; go-git never prompts and would send it as a username.
+func remoteURL(url string) string {
+	return strings.Replace(url, "://git::@", "://", 1)
+}
+
+// ignoreUpToDate treats go-git's "already up-to-date" sentinel as success.
+func ignoreUpToDate(err error) error {
+	if errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return nil
+	}
+	return err
+}
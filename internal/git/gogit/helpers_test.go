@@ -0,0 +1,124 @@
+package gogit_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/git/gogit"
+)
+
+// Compile-time interface compliance checks.
+var (
+	_ git.Cloner          = (*gogit.Cloner)(nil)
+	_ git.Puller          = (*gogit.Puller)(nil)
+	_ git.Validator       = (*gogit.Validator)(nil)
+	_ git.Fetcher         = (*gogit.Fetcher)(nil)
+	_ git.RevParser       = (*gogit.RevParser)(nil)
+	_ git.Logger          = (*gogit.Logger)(nil)
+	_ git.Checkouter      = (*gogit.Checkouter)(nil)
+	_ git.WorktreeChecker = (*gogit.WorktreeChecker)(nil)
+	_ git.TagLister       = (*gogit.TagLister)(nil)
+)
+
+// requireFixtures skips tests that need the git CLI to build fixture
+// repositories. The backend under test never runs git itself.
+func requireFixtures(t *testing.T) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping go-git test in short mode")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git CLI not available to build fixtures")
+	}
+}
+
+// initBareRepo creates a bare git repository with a single commit on the
+// default branch. It returns the path to the bare repo directory.
+func initBareRepo(t *testing.T) string {
+	t.Helper()
+
+	bare := filepath.Join(t.TempDir(), "bare.git")
+	runGit(t, "", "init", "--bare", bare)
+
+	work := filepath.Join(t.TempDir(), "work")
+	runGit(t, "", "clone", bare, work)
+	runGit(t, work, "config", "user.email", "test@test.com")
+	runGit(t, work, "config", "user.name", "Test")
+	writeFile(t, filepath.Join(work, "README"), "init")
+	runGit(t, work, "add", ".")
+	runGit(t, work, "commit", "-m", "initial commit")
+	runGit(t, work, "push", "origin", "HEAD")
+
+	return bare
+}
+
+// cloneLocal clones the bare repo with the go-git Cloner and returns its path.
+func cloneLocal(t *testing.T, bareDir string) string {
+	t.Helper()
+
+	dst := filepath.Join(t.TempDir(), "clone")
+	if err := gogit.NewCloner().Clone(context.Background(), git.CloneOptions{URL: bareDir, Dir: dst}); err != nil {
+		t.Fatalf("clone fixture: %v", err)
+	}
+	return dst
+}
+
+// addCommitToBare pushes a new commit adding filename to the bare repo and
+// returns the commit's hash.
+func addCommitToBare(t *testing.T, bareDir, filename string) string {
+	t.Helper()
+
+	work := filepath.Join(t.TempDir(), "pusher")
+	runGit(t, "", "clone", bareDir, work)
+	runGit(t, work, "config", "user.email", "test@test.com")
+	runGit(t, work, "config", "user.name", "Test")
+	writeFile(t, filepath.Join(work, filename), "content of "+filename)
+	runGit(t, work, "add", ".")
+	runGit(t, work, "commit", "-m", "add "+filename)
+	runGit(t, work, "push", "origin", "HEAD")
+	return gitOutput(t, work, "rev-parse", "HEAD")
+}
+
+// runGit executes a git command and fails the test on error.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	gitOutput(t, dir, args...)
+}
+
+// gitOutput executes a git command and returns its trimmed output.
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.CommandContext(context.Background(), "git", args...)
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("command git %v failed: %v\n%s", args, err, out)
+	}
+	return string(trimNewline(out))
+}
+
+func trimNewline(b []byte) []byte {
+	for len(b) > 0 && (b[len(b)-1] == '\n' || b[len(b)-1] == '\r') {
+		b = b[:len(b)-1]
+	}
+	return b
+}
+
+// writeFile writes content to a file, creating parent directories as needed.
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,73 @@
+package gogit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/tmuxpack/tpack/internal/git"
+)
+
+// Retrieves commit logs with go-git.
+type Logger struct{}
+
+func NewLogger() *Logger {
+	return &Logger{}
+}
+
+// Log lists the commits in fromRef..toRef, newest first, like
+// "git log --oneline".
+func (c *Logger) Log(ctx context.Context, dir, fromRef, toRef string) ([]git.Commit, error) {
+	fail := func(err error) ([]git.Commit, error) {
+		return nil, fmt.Errorf("git log %s..%s in %s: %w", fromRef, toRef, dir, err)
+	}
+
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		return fail(err)
+	}
+	from, err := repo.ResolveRevision(plumbing.Revision(fromRef))
+	if err != nil {
+		return fail(err)
+	}
+	to, err := repo.ResolveRevision(plumbing.Revision(toRef))
+	if err != nil {
+		return fail(err)
+	}
+
+	// Everything reachable from fromRef is excluded from the walk.
+	exclude := make(map[plumbing.Hash]bool)
+	fromCommit, err := repo.CommitObject(*from)
+	if err != nil {
+		return fail(err)
+	}
+	err = object.NewCommitPreorderIter(fromCommit, nil, nil).ForEach(func(c *object.Commit) error {
+		exclude[c.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	toCommit, err := repo.CommitObject(*to)
+	if err != nil {
+		return fail(err)
+	}
+	var commits []git.Commit
+	err = object.NewCommitPreorderIter(toCommit, exclude, nil).ForEach(func(c *object.Commit) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		message, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+		commits = append(commits, git.Commit{Hash: git.ShortHash(c.Hash.String()), Message: message})
+		return nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+	return commits, nil
+}
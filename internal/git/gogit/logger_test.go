@@ -0,0 +1,45 @@
+package gogit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/git/gogit"
+)
+
+func TestLogger_LogRange(t *testing.T) {
+	requireFixtures(t)
+
+	bare := initBareRepo(t)
+	clone := cloneLocal(t, bare)
+	before := gitOutput(t, clone, "rev-parse", "HEAD")
+	addCommitToBare(t, bare, "a.txt")
+	addCommitToBare(t, bare, "b.txt")
+	if _, err := gogit.NewPuller().Pull(context.Background(), git.PullOptions{Dir: clone}); err != nil {
+		t.Fatal(err)
+	}
+
+	commits, err := gogit.NewLogger().Log(context.Background(), clone, before, "HEAD")
+	if err != nil {
+		t.Fatalf("Log returned error: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits, got %+v", commits)
+	}
+	if commits[0].Message != "add b.txt" || commits[1].Message != "add a.txt" {
+		t.Errorf("expected newest first, got %+v", commits)
+	}
+	if len(commits[0].Hash) != 7 {
+		t.Errorf("expected abbreviated hash, got %q", commits[0].Hash)
+	}
+}
+
+func TestLogger_InvalidRef(t *testing.T) {
+	requireFixtures(t)
+
+	clone := cloneLocal(t, initBareRepo(t))
+	if _, err := gogit.NewLogger().Log(context.Background(), clone, "nope", "HEAD"); err == nil {
+		t.Error("expected error for unknown ref")
+	}
+}
@@ -0,0 +1,65 @@
+package gogit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+)
+
+// Pulls updates for an existing repository in-process with go-git.
+// Only fast-forward updates are supported.
+type Puller struct{}
+
+func NewPuller() *Puller {
+	return &Puller{}
+}
+
+func (c *Puller) Pull(ctx context.Context, opts git.PullOptions) (string, error) {
+	repo, err := gogit.PlainOpen(opts.Dir)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", opts.Dir, err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	if opts.Branch != "" {
+		err := wt.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(opts.Branch)})
+		if err != nil {
+			return "", fmt.Errorf("checkout %s: %w", opts.Branch, err)
+		}
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	if !head.Name().IsBranch() {
+		return "", errors.New("HEAD is detached; not on a branch")
+	}
+
+	err = wt.PullContext(ctx, &gogit.PullOptions{
+		RemoteName:        gogit.DefaultRemoteName,
+		ReferenceName:     head.Name(),
+		SingleBranch:      true,
+		RecurseSubmodules: gogit.DefaultSubmoduleRecursionDepth,
+	})
+	if errors.Is(err, gogit.NoErrAlreadyUpToDate) {
+		return "Already up to date.", nil
+	}
+	if err != nil {
+		return err.Error(), err
+	}
+
+	after, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	return "Updating " + git.ShortHash(head.Hash().String()) + ".." + git.ShortHash(after.Hash().String()), nil
+}
@@ -0,0 +1,46 @@
+package gogit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/git/gogit"
+)
+
+func TestPuller_PullUpToDate(t *testing.T) {
+	requireFixtures(t)
+
+	clone := cloneLocal(t, initBareRepo(t))
+
+	out, err := gogit.NewPuller().Pull(context.Background(), git.PullOptions{Dir: clone})
+	if err != nil {
+		t.Fatalf("Pull returned error: %v", err)
+	}
+	if out == "" {
+		t.Fatal("expected non-empty output from pull")
+	}
+}
+
+func TestPuller_PullWithUpstreamChanges(t *testing.T) {
+	requireFixtures(t)
+
+	bare := initBareRepo(t)
+	clone := cloneLocal(t, bare)
+	addCommitToBare(t, bare, "new-file.txt")
+
+	if _, err := gogit.NewPuller().Pull(context.Background(), git.PullOptions{Dir: clone}); err != nil {
+		t.Fatalf("Pull returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(clone, "new-file.txt")); err != nil {
+		t.Fatalf("expected new-file.txt after pull: %v", err)
+	}
+}
+
+func TestPuller_PullNonGitDir(t *testing.T) {
+	if _, err := gogit.NewPuller().Pull(context.Background(), git.PullOptions{Dir: t.TempDir()}); err == nil {
+		t.Fatal("expected error when pulling in non-git directory")
+	}
+}
@@ -0,0 +1,27 @@
+package gogit
+
+import (
+	"context"
+	"fmt"
+
+	gogit "github.com/go-git/go-git/v5"
+)
+
+// Resolves HEAD with go-git.
+type RevParser struct{}
+
+func NewRevParser() *RevParser {
+	return &RevParser{}
+}
+
+func (c *RevParser) RevParse(_ context.Context, dir string) (string, error) {
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		return "", fmt.Errorf("rev-parse HEAD in %s: %w", dir, err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("rev-parse HEAD in %s: %w", dir, err)
+	}
+	return head.Hash().String(), nil
+}
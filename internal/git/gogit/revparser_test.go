@@ -0,0 +1,28 @@
+package gogit_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git/gogit"
+)
+
+func TestRevParser_MatchesGit(t *testing.T) {
+	requireFixtures(t)
+
+	clone := cloneLocal(t, initBareRepo(t))
+
+	got, err := gogit.NewRevParser().RevParse(context.Background(), clone)
+	if err != nil {
+		t.Fatalf("RevParse returned error: %v", err)
+	}
+	if want := gitOutput(t, clone, "rev-parse", "HEAD"); got != want {
+		t.Errorf("RevParse = %q, want %q", got, want)
+	}
+}
+
+func TestRevParser_NonGitDir(t *testing.T) {
+	if _, err := gogit.NewRevParser().RevParse(context.Background(), t.TempDir()); err == nil {
+		t.Error("expected error for non-git directory")
+	}
+}
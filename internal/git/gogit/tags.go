@@ -0,0 +1,36 @@
+package gogit
+
+import (
+	"context"
+	"fmt"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// Lists remote tags with go-git, like "git ls-remote --tags --refs".
+type TagLister struct{}
+
+func NewTagLister() *TagLister {
+	return &TagLister{}
+}
+
+func (c *TagLister) ListTags(ctx context.Context, url string) ([]string, error) {
+	remote := gogit.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: gogit.DefaultRemoteName,
+		URLs: []string{remoteURL(url)},
+	})
+	refs, err := remote.ListContext(ctx, &gogit.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("ls-remote --tags %s: %w", url, err)
+	}
+
+	var tags []string
+	for _, ref := range refs {
+		if ref.Name().IsTag() {
+			tags = append(tags, ref.Name().Short())
+		}
+	}
+	return tags, nil
+}
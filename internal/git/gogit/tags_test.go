@@ -0,0 +1,38 @@
+package gogit_test
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git/gogit"
+)
+
+func TestTagLister_ListsTags(t *testing.T) {
+	requireFixtures(t)
+
+	bare := initBareRepo(t)
+	work := filepath.Join(t.TempDir(), "work")
+	runGit(t, "", "clone", bare, work)
+	runGit(t, work, "tag", "v1.0.0")
+	runGit(t, work, "-c", "user.email=t@t", "-c", "user.name=T", "tag", "-a", "v1.1.0", "-m", "annotated")
+	runGit(t, work, "push", "origin", "--tags")
+
+	tags, err := gogit.NewTagLister().ListTags(context.Background(), bare)
+	if err != nil {
+		t.Fatalf("ListTags returned error: %v", err)
+	}
+	slices.Sort(tags)
+	if !slices.Equal(tags, []string{"v1.0.0", "v1.1.0"}) {
+		t.Errorf("ListTags = %v, want [v1.0.0 v1.1.0]", tags)
+	}
+}
+
+func TestTagLister_MissingRemote(t *testing.T) {
+	requireFixtures(t)
+
+	if _, err := gogit.NewTagLister().ListTags(context.Background(), filepath.Join(t.TempDir(), "missing.git")); err == nil {
+		t.Error("expected error for missing remote")
+	}
+}
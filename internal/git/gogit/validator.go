@@ -0,0 +1,17 @@
+package gogit
+
+import (
+	gogit "github.com/go-git/go-git/v5"
+)
+
+// Checks if a directory is a git repo by opening it with go-git.
+type Validator struct{}
+
+func NewValidator() *Validator {
+	return &Validator{}
+}
+
+func (c *Validator) IsGitRepo(dir string) bool {
+	_, err := gogit.PlainOpen(dir)
+	return err == nil
+}
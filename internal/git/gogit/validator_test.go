@@ -0,0 +1,19 @@
+package gogit_test
+
+import (
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git/gogit"
+)
+
+func TestValidator_IsGitRepo(t *testing.T) {
+	requireFixtures(t)
+
+	v := gogit.NewValidator()
+	if !v.IsGitRepo(cloneLocal(t, initBareRepo(t))) {
+		t.Error("expected clone to be a git repo")
+	}
+	if v.IsGitRepo(t.TempDir()) {
+		t.Error("expected empty directory not to be a git repo")
+	}
+}
@@ -0,0 +1,41 @@
+package gogit
+
+import (
+	"context"
+	"fmt"
+
+	gogit "github.com/go-git/go-git/v5"
+)
+
+// Inspects working tree state with go-git.
+type WorktreeChecker struct{}
+
+func NewWorktreeChecker() *WorktreeChecker {
+	return &WorktreeChecker{}
+}
+
+// IsDirty reports modified or staged tracked files. Untracked files are
+// ignored since plugins commonly generate them at runtime.
+func (c *WorktreeChecker) IsDirty(_ context.Context, dir string) (bool, error) {
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		return false, fmt.Errorf("status in %s: %w", dir, err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return false, fmt.Errorf("status in %s: %w", dir, err)
+	}
+	status, err := wt.StatusWithOptions(gogit.StatusOptions{Strategy: gogit.Preload})
+	if err != nil {
+		return false, fmt.Errorf("status in %s: %w", dir, err)
+	}
+	for _, s := range status {
+		if s.Staging == gogit.Untracked && s.Worktree == gogit.Untracked {
+			continue
+		}
+		if s.Staging != gogit.Unmodified || s.Worktree != gogit.Unmodified {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,30 @@
+package gogit_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git/gogit"
+)
+
+func TestWorktreeChecker_IsDirty(t *testing.T) {
+	requireFixtures(t)
+
+	clone := cloneLocal(t, initBareRepo(t))
+	wc := gogit.NewWorktreeChecker()
+
+	if dirty, err := wc.IsDirty(context.Background(), clone); err != nil || dirty {
+		t.Fatalf("expected fresh clone to be clean, got dirty=%v err=%v", dirty, err)
+	}
+
+	writeFile(t, filepath.Join(clone, "generated.bin"), "build output")
+	if dirty, _ := wc.IsDirty(context.Background(), clone); dirty {
+		t.Error("untracked files should not count as dirty")
+	}
+
+	writeFile(t, filepath.Join(clone, "README"), "local edit")
+	if dirty, err := wc.IsDirty(context.Background(), clone); err != nil || !dirty {
+		t.Errorf("expected modified tracked file to be dirty, got dirty=%v err=%v", dirty, err)
+	}
+}
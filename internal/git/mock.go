@@ -2,6 +2,7 @@ package git
 
 import (
 	"context"
+	"errors"
 	"sync"
 )
 
@@ -120,3 +121,71 @@ func (m *MockLogger) Log(_ context.Context, dir, fromRef, toRef string) ([]Commi
 	m.Calls = append(m.Calls, mockLogCall{Dir: dir, FromRef: fromRef, ToRef: toRef})
 	return m.Commits, m.Err
 }
+
+// Records checkout calls for testing.
+type MockCheckouter struct {
+	mu    sync.Mutex
+	Calls []MockCheckoutCall
+	Err   error
+}
+
+type MockCheckoutCall struct {
+	Dir string
+	Ref string
+}
+
+func NewMockCheckouter() *MockCheckouter {
+	return &MockCheckouter{}
+}
+
+func (m *MockCheckouter) Checkout(_ context.Context, dir, ref string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, MockCheckoutCall{Dir: dir, Ref: ref})
+	return m.Err
+}
+
+// Returns configurable results for testing.
+type MockWorktreeChecker struct {
+	mu    sync.Mutex
+	Calls []string
+	Dirty map[string]bool
+	Err   error
+}
+
+func NewMockWorktreeChecker() *MockWorktreeChecker {
+	return &MockWorktreeChecker{Dirty: make(map[string]bool)}
+}
+
+func (m *MockWorktreeChecker) IsDirty(_ context.Context, dir string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, dir)
+	return m.Dirty[dir], m.Err
+}
+
+// Returns canned tags per URL for testing.
+type MockTagLister struct {
+	mu    sync.Mutex
+	Calls []string
+	Tags  map[string][]string
+	Err   error
+}
+
+func NewMockTagLister() *MockTagLister {
+	return &MockTagLister{Tags: make(map[string][]string)}
+}
+
+func (m *MockTagLister) ListTags(_ context.Context, url string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, url)
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	tags, ok := m.Tags[url]
+	if !ok {
+		return nil, errors.New("repository not found: " + url)
+	}
+	return tags, nil
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/tmuxpack/tpack/internal/semver"
+)
+
+// Pin is a plugin version pin resolved against its remote.
+type Pin struct {
+	// Tag is the tag to clone or check out; empty for commit pins.
+	Tag string
+	// Commit is the commit to check out; empty for tag pins.
+	Commit string
+}
+
+// Ref returns the revision the pin points at.
+func (p Pin) Ref() string {
+	if p.Tag != "" {
+		return p.Tag
+	}
+	return p.Commit
+}
+
+// IsCommitHash reports whether s looks like an abbreviated or full commit hash.
+func IsCommitHash(s string) bool {
+	if len(s) < 7 || len(s) > 40 {
+		return false
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolvePin resolves a version pin to a tag or commit. The remote's tags
+// are listed (retrying with the normalized URL on failure) and a tag named
+// exactly like version wins, since tags such as "deadbeef" look like commit
+// hashes. Otherwise a commit hash is returned as-is, even when the tags
+// could not be listed, and any other version resolves to the highest tag
+// that satisfies it as a semver constraint. A nil lister resolves commit
+// hashes only.
+func ResolvePin(ctx context.Context, lister TagLister, url, version string, normalize func(string) string) (Pin, error) {
+	var tags []string
+	var err error
+	if lister != nil {
+		tags, err = lister.ListTags(ctx, url)
+		if err != nil {
+			var retryErr error
+			if tags, retryErr = lister.ListTags(ctx, normalize(url)); retryErr == nil {
+				err = nil
+			}
+		}
+	}
+
+	switch {
+	case slices.Contains(tags, version):
+		return Pin{Tag: version}, nil
+	case IsCommitHash(version):
+		return Pin{Commit: version}, nil
+	case err != nil:
+		return Pin{}, err
+	}
+	c, err := semver.ParseConstraint(version)
+	if err != nil {
+		return Pin{}, err
+	}
+	tag, ok := c.Latest(tags)
+	if !ok {
+		return Pin{}, fmt.Errorf("no tag matches %q", version)
+	}
+	return Pin{Tag: tag}, nil
+}
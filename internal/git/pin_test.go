@@ -0,0 +1,93 @@
+package git_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+)
+
+func TestIsCommitHash(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"3f2a9c1", true},
+		{"3f2a9c1d8e7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f", true},
+		{"3f2a9c", false},
+		{"3F2A9C1", false},
+		{"v2.1.0", false},
+		{"deadbeefcafe", true},
+		{"3f2a9c1d8e7b6a5f4e3d2c1b0a9f8e7d6c5b4a3f0", false},
+	}
+	for _, tt := range tests {
+		if got := git.IsCommitHash(tt.in); got != tt.want {
+			t.Errorf("IsCommitHash(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestResolvePin(t *testing.T) {
+	const url = "user/repo"
+	normalize := func(s string) string { return "https://example.com/" + s }
+
+	lister := git.NewMockTagLister()
+	lister.Tags["https://example.com/user/repo"] = []string{"v1.0.0", "v2.0.0", "v2.1.0", "v3.0.0-rc.1", "stable", "deadbeef"}
+
+	tests := []struct {
+		version string
+		want    git.Pin
+		wantErr bool
+	}{
+		{"3f2a9c1", git.Pin{Commit: "3f2a9c1"}, false},
+		{"deadbeef", git.Pin{Tag: "deadbeef"}, false},
+		{"v2.0.0", git.Pin{Tag: "v2.0.0"}, false},
+		{"2.0.0", git.Pin{Tag: "v2.0.0"}, false},
+		{"^2", git.Pin{Tag: "v2.1.0"}, false},
+		{"~1", git.Pin{Tag: "v1.0.0"}, false},
+		{"stable", git.Pin{Tag: "stable"}, false},
+		{"^4", git.Pin{}, true},
+		{"main", git.Pin{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := git.ResolvePin(context.Background(), lister, url, tt.version, normalize)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolvePin error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolvePin = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePinCommitRemoteUnreachable(t *testing.T) {
+	lister := git.NewMockTagLister()
+
+	pin, err := git.ResolvePin(context.Background(), lister, "user/repo", "abcdef1", func(s string) string { return s })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pin != (git.Pin{Commit: "abcdef1"}) {
+		t.Errorf("ResolvePin = %+v, want commit abcdef1", pin)
+	}
+
+	pin, err = git.ResolvePin(context.Background(), nil, "user/repo", "abcdef1", func(s string) string { return s })
+	if err != nil || pin.Commit != "abcdef1" {
+		t.Errorf("ResolvePin without a lister = %+v, %v, want commit abcdef1", pin, err)
+	}
+}
+
+func TestResolvePinRemoteUnreachable(t *testing.T) {
+	lister := git.NewMockTagLister()
+
+	_, err := git.ResolvePin(context.Background(), lister, "user/repo", "^1", func(s string) string { return "x/" + s })
+	if err == nil {
+		t.Fatal("expected error when remote is unreachable")
+	}
+	if len(lister.Calls) != 2 {
+		t.Errorf("expected raw and normalized attempts, got %v", lister.Calls)
+	}
+}
@@ -0,0 +1,39 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RollbackTimeout bounds restoring a repository after a failed update. The
+// update's own context may already be expired, so rollback gets a fresh one.
+const RollbackTimeout = 30 * time.Second
+
+// ErrRollbackFailed is joined into the error returned by WithRollback when a
+// failed update could not be undone.
+var ErrRollbackFailed = errors.New("rollback failed")
+
+// WithRollback records the HEAD of dir, runs update, and if update fails
+// hard-resets dir (submodules included) back to the recorded HEAD. It returns
+// the pre-update HEAD ("" when it could not be read) and whether the
+// repository was restored. Without a RevParser and Checkouter, update runs
+// unguarded.
+func WithRollback(ctx context.Context, rp RevParser, co Checkouter, dir string, update func() error) (before string, rolledBack bool, err error) {
+	if rp != nil {
+		before, _ = rp.RevParse(ctx, dir)
+	}
+
+	err = update()
+	if err == nil || before == "" || co == nil {
+		return before, false, err
+	}
+
+	rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), RollbackTimeout)
+	defer cancel()
+	if rbErr := co.Checkout(rctx, dir, before); rbErr != nil {
+		return before, false, errors.Join(err, fmt.Errorf("%w: %w", ErrRollbackFailed, rbErr))
+	}
+	return before, true, err
+}
@@ -0,0 +1,76 @@
+package git_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+)
+
+func TestWithRollbackSuccess(t *testing.T) {
+	rp := git.NewMockRevParser()
+	co := git.NewMockCheckouter()
+
+	before, rolledBack, err := git.WithRollback(context.Background(), rp, co, "/p", func() error { return nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if before != "abc123" || rolledBack {
+		t.Errorf("got before=%q rolledBack=%v, want abc123 false", before, rolledBack)
+	}
+	if len(co.Calls) != 0 {
+		t.Errorf("expected no checkout on success, got %v", co.Calls)
+	}
+}
+
+func TestWithRollbackRestoresOnFailure(t *testing.T) {
+	rp := git.NewMockRevParser()
+	co := git.NewMockCheckouter()
+	updateErr := errors.New("pull failed")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	before, rolledBack, err := git.WithRollback(ctx, rp, co, "/p", func() error {
+		cancel() // rollback must not depend on the update's context
+		return updateErr
+	})
+	if !errors.Is(err, updateErr) {
+		t.Fatalf("err = %v, want %v", err, updateErr)
+	}
+	if !rolledBack || before != "abc123" {
+		t.Errorf("got before=%q rolledBack=%v, want abc123 true", before, rolledBack)
+	}
+	if len(co.Calls) != 1 || co.Calls[0] != (git.MockCheckoutCall{Dir: "/p", Ref: "abc123"}) {
+		t.Errorf("checkout calls = %v", co.Calls)
+	}
+}
+
+func TestWithRollbackReportsFailedRestore(t *testing.T) {
+	rp := git.NewMockRevParser()
+	co := git.NewMockCheckouter()
+	co.Err = errors.New("reset failed")
+	updateErr := errors.New("pull failed")
+
+	_, rolledBack, err := git.WithRollback(context.Background(), rp, co, "/p", func() error { return updateErr })
+	if rolledBack {
+		t.Error("expected rolledBack=false when checkout fails")
+	}
+	if !errors.Is(err, updateErr) || !errors.Is(err, git.ErrRollbackFailed) {
+		t.Errorf("err = %v, want both update and rollback errors", err)
+	}
+}
+
+func TestWithRollbackUnknownHead(t *testing.T) {
+	rp := git.NewMockRevParser()
+	rp.Err = errors.New("not a repo")
+	rp.Hash = ""
+	co := git.NewMockCheckouter()
+
+	_, rolledBack, err := git.WithRollback(context.Background(), rp, co, "/p", func() error { return errors.New("boom") })
+	if err == nil || rolledBack {
+		t.Errorf("got rolledBack=%v err=%v, want false and an error", rolledBack, err)
+	}
+	if len(co.Calls) != 0 {
+		t.Errorf("expected no checkout without a recorded HEAD, got %v", co.Calls)
+	}
+}
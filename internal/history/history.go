@@ -0,0 +1,151 @@
+// Package history keeps an append-only log of plugin operations.
+//
+// Entries are stored one JSON object per line in history.jsonl inside the
+// tpack state directory, so the file can be appended to concurrently and
+// inspected with standard tools.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tmuxpack/tpack/internal/git"
+)
+
+const historyFile = "history.jsonl"
+
+// Action identifies the kind of operation an Entry records.
+type Action string
+
+const (
+	Install   Action = "install"
+	Update    Action = "update"
+	Clean     Action = "clean"
+	Uninstall Action = "uninstall"
+	Rollback  Action = "rollback"
+)
+
+// Entry is a single recorded operation on a plugin.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Action  Action    `json:"action"`
+	Plugin  string    `json:"plugin"`
+	Before  string    `json:"before,omitempty"`
+	After   string    `json:"after,omitempty"`
+	Commits int       `json:"commits,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Success reports whether the operation completed without error.
+func (e Entry) Success() bool {
+	return e.Error == ""
+}
+
+// Detail summarizes the outcome of e: the revisions it moved between and
+// how many commits that spanned, or the error for failed operations.
+func (e Entry) Detail() string {
+	if !e.Success() {
+		return "failed: " + e.Error
+	}
+	var s string
+	switch {
+	case e.Before != "" && e.After != "":
+		s = git.ShortHash(e.Before) + ".." + git.ShortHash(e.After)
+	case e.After != "":
+		s = git.ShortHash(e.After)
+	}
+	if e.Commits > 0 {
+		s += " (" + strconv.Itoa(e.Commits) + " commit"
+		if e.Commits != 1 {
+			s += "s"
+		}
+		s += ")"
+	}
+	return strings.TrimSpace(s)
+}
+
+// Path returns the history file location inside statePath.
+func Path(statePath string) string {
+	return filepath.Join(statePath, historyFile)
+}
+
+// Append adds entries to the history log, creating it if needed. Entries
+// with a zero Time are stamped with the current time.
+func Append(statePath string, entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(statePath, 0o755); err != nil {
+		return err
+	}
+
+	var buf []byte
+	for _, e := range entries {
+		if e.Time.IsZero() {
+			e.Time = time.Now()
+		}
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+
+	// A single O_APPEND write keeps concurrent writers from interleaving lines.
+	f, err := os.OpenFile(Path(statePath), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load reads the history log in the order entries were recorded. A missing
+// log yields no entries; malformed lines are skipped.
+func Load(statePath string) ([]Entry, error) {
+	f, err := os.Open(Path(statePath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return entries, fmt.Errorf("read %s: %w", Path(statePath), err)
+	}
+	return entries, nil
+}
+
+// ForPlugin returns the entries recorded for the named plugin.
+func ForPlugin(entries []Entry, name string) []Entry {
+	var out []Entry
+	for _, e := range entries {
+		if e.Plugin == name {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,105 @@
+package history_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tmuxpack/tpack/internal/history"
+)
+
+func TestLoadMissing(t *testing.T) {
+	entries, err := history.Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries, got %v", entries)
+	}
+}
+
+func TestAppendAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+
+	err := history.Append(dir,
+		history.Entry{Time: at, Action: history.Install, Plugin: "tmux-yank", After: "aaa111"},
+		history.Entry{Time: at, Action: history.Update, Plugin: "tmux-sensible", Before: "bbb222", After: "ccc333", Commits: 4},
+	)
+	if err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if err := history.Append(dir, history.Entry{Action: history.Update, Plugin: "tmux-yank", Error: "pull failed"}); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	entries, err := history.Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[1].Commits != 4 || entries[1].Before != "bbb222" || !entries[1].Time.Equal(at) {
+		t.Errorf("unexpected second entry: %+v", entries[1])
+	}
+	if entries[2].Time.IsZero() {
+		t.Error("expected zero Time to be stamped on append")
+	}
+	if entries[2].Success() {
+		t.Error("expected entry with error to report failure")
+	}
+}
+
+func TestLoadSkipsMalformedLines(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"action":"install","plugin":"a"}
+not json
+{"action":"clean","plugin":"b"}
+`
+	if err := os.WriteFile(history.Path(dir), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := history.Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 2 || entries[1].Plugin != "b" {
+		t.Errorf("expected two valid entries, got %+v", entries)
+	}
+}
+
+func TestForPlugin(t *testing.T) {
+	entries := []history.Entry{
+		{Plugin: "a", Action: history.Install},
+		{Plugin: "b", Action: history.Install},
+		{Plugin: "a", Action: history.Update},
+	}
+
+	got := history.ForPlugin(entries, "a")
+	if len(got) != 2 || got[1].Action != history.Update {
+		t.Errorf("ForPlugin(a) = %+v", got)
+	}
+}
+
+func TestDetail(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry history.Entry
+		want  string
+	}{
+		{"install", history.Entry{After: "aaaaaaa111"}, "aaaaaaa"},
+		{"update", history.Entry{Before: "aaaaaaa111", After: "bbbbbbb222", Commits: 3}, "aaaaaaa..bbbbbbb (3 commits)"},
+		{"single commit", history.Entry{Before: "a", After: "b", Commits: 1}, "a..b (1 commit)"},
+		{"clean", history.Entry{}, ""},
+		{"failure", history.Entry{Before: "a", Error: "download fail"}, "failed: download fail"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.Detail(); got != tt.want {
+				t.Errorf("Detail() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Package lock reads and writes the tpack lockfile, which pins every
+// plugin to the exact commit it was installed or updated to.
+package lock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the default lockfile name, written next to tmux.conf.
+const FileName = "tpack.lock"
+
+// Entry records the resolved revision of a single plugin.
+type Entry struct {
+	Name    string `yaml:"name"`
+	Spec    string `yaml:"spec"`
+	URL     string `yaml:"url"`
+	Branch  string `yaml:"branch,omitempty"`
+	Version string `yaml:"version,omitempty"`
+	Commit  string `yaml:"commit"`
+}
+
+// File is the parsed contents of a lockfile.
+type File struct {
+	Plugins []Entry `yaml:"plugins"`
+
+	// set holds the names of the entries Set since the file was loaded.
+	set map[string]bool
+}
+
+// Load reads the lockfile at path. A missing file yields an empty File.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is resolved from user config
+	if os.IsNotExist(err) {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read lockfile: %w", err)
+	}
+
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse lockfile %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Save writes f to path with entries sorted by name. The file is written
+// to a temporary sibling and renamed so readers never see partial content.
+func Save(path string, f *File) error {
+	sorted := slices.Clone(f.Plugins)
+	slices.SortFunc(sorted, func(a, b Entry) int { return strings.Compare(a.Name, b.Name) })
+
+	data, err := yaml.Marshal(File{Plugins: sorted})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tpack-lock-*")
+	if err != nil {
+		return fmt.Errorf("write lockfile: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // best-effort cleanup after rename
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write lockfile: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write lockfile: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil { //nolint:gosec // lockfile is meant to be committed and shared
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get returns the entry for the named plugin.
+func (f *File) Get(name string) (Entry, bool) {
+	for _, e := range f.Plugins {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Set adds or replaces the entry with the same name.
+func (f *File) Set(e Entry) {
+	if f.set == nil {
+		f.set = make(map[string]bool)
+	}
+	f.set[e.Name] = true
+	for i := range f.Plugins {
+		if f.Plugins[i].Name == e.Name {
+			f.Plugins[i] = e
+			return
+		}
+	}
+	f.Plugins = append(f.Plugins, e)
+}
+
+// Merge copies the entries Set in from into f, leaving f's other entries
+// alone, so the changes of one operation can be applied to a file another
+// operation has saved since.
+func (f *File) Merge(from *File) {
+	for _, e := range from.Plugins {
+		if from.set[e.Name] {
+			f.Set(e)
+		}
+	}
+}
+
+// Prune drops entries whose names are not in keep.
+func (f *File) Prune(keep map[string]bool) {
+	f.Plugins = slices.DeleteFunc(f.Plugins, func(e Entry) bool { return !keep[e.Name] })
+}
+
+// Matches reports whether e was recorded for the given spec, branch and
+// version pin, i.e. whether the plugin declaration has not changed since locking.
+func (e Entry) Matches(spec, branch, version string) bool {
+	return e.Spec == spec && e.Branch == branch && e.Version == version
+}
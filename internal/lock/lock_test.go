@@ -0,0 +1,118 @@
+package lock_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/lock"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	lf, err := lock.Load(filepath.Join(t.TempDir(), "tpack.lock"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(lf.Plugins) != 0 {
+		t.Errorf("expected empty lockfile, got %d entries", len(lf.Plugins))
+	}
+}
+
+func TestLoadCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tpack.lock")
+	os.WriteFile(path, []byte("{{bad yaml!"), 0o644)
+
+	if _, err := lock.Load(path); err == nil {
+		t.Fatal("expected error for corrupt lockfile")
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "tpack.lock")
+
+	lf := &lock.File{}
+	lf.Set(lock.Entry{Name: "tmux-yank", Spec: "tmux-plugins/tmux-yank", URL: "https://github.com/tmux-plugins/tmux-yank", Commit: "bbb"})
+	lf.Set(lock.Entry{Name: "tmux-sensible", Spec: "tmux-plugins/tmux-sensible", URL: "https://github.com/tmux-plugins/tmux-sensible", Branch: "main", Commit: "aaa"})
+
+	if err := lock.Save(path, lf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := lock.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded.Plugins) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(loaded.Plugins))
+	}
+	// Entries are written sorted by name.
+	if loaded.Plugins[0].Name != "tmux-sensible" {
+		t.Errorf("first entry = %q, want tmux-sensible", loaded.Plugins[0].Name)
+	}
+	e, ok := loaded.Get("tmux-sensible")
+	if !ok || e.Branch != "main" || e.Commit != "aaa" {
+		t.Errorf("Get(tmux-sensible) = %+v, %v", e, ok)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "branch: \"\"") {
+		t.Error("empty branch should be omitted")
+	}
+}
+
+func TestSetReplacesExisting(t *testing.T) {
+	lf := &lock.File{}
+	lf.Set(lock.Entry{Name: "a", Commit: "1"})
+	lf.Set(lock.Entry{Name: "a", Commit: "2"})
+
+	if len(lf.Plugins) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(lf.Plugins))
+	}
+	if lf.Plugins[0].Commit != "2" {
+		t.Errorf("Commit = %q, want %q", lf.Plugins[0].Commit, "2")
+	}
+}
+
+func TestMergeCopiesOnlySetEntries(t *testing.T) {
+	from := &lock.File{Plugins: []lock.Entry{{Name: "a", Commit: "stale"}}}
+	from.Set(lock.Entry{Name: "b", Commit: "new"})
+
+	into := &lock.File{Plugins: []lock.Entry{{Name: "a", Commit: "saved"}, {Name: "b", Commit: "old"}}}
+	into.Merge(from)
+
+	if e, _ := into.Get("a"); e.Commit != "saved" {
+		t.Errorf("a = %q, want the entry saved since from was loaded", e.Commit)
+	}
+	if e, _ := into.Get("b"); e.Commit != "new" {
+		t.Errorf("b = %q, want new", e.Commit)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	lf := &lock.File{Plugins: []lock.Entry{{Name: "a"}, {Name: "b"}, {Name: "c"}}}
+	lf.Prune(map[string]bool{"a": true, "c": true})
+
+	if len(lf.Plugins) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(lf.Plugins))
+	}
+	if _, ok := lf.Get("b"); ok {
+		t.Error("b should have been pruned")
+	}
+}
+
+func TestEntryMatches(t *testing.T) {
+	e := lock.Entry{Spec: "user/repo", Branch: "dev", Version: "^2"}
+	if !e.Matches("user/repo", "dev", "^2") {
+		t.Error("expected match for same spec, branch and version")
+	}
+	if e.Matches("user/repo", "", "^2") {
+		t.Error("expected mismatch for different branch")
+	}
+	if e.Matches("other/repo", "dev", "^2") {
+		t.Error("expected mismatch for different spec")
+	}
+	if e.Matches("user/repo", "dev", "^3") {
+		t.Error("expected mismatch for different version")
+	}
+}
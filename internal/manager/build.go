@@ -0,0 +1,49 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/tmuxpack/tpack/internal/build"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+// BuildAction is the action of the results reporting build commands.
+const BuildAction = "build"
+
+// runBuild runs p's build command in dir, if it has one, and reports its
+// output as a build result.
+func (m *Manager) runBuild(ctx context.Context, p plug.Plugin, dir string) error {
+	if p.Build == "" {
+		return nil
+	}
+	m.output.Ok("  \"" + p.Name + "\" building: " + p.Build)
+	out, err := build.Run(ctx, dir, p.Build)
+	res := ui.Result{Name: p.Name, Action: BuildAction, Status: ui.StatusOK, Output: out}
+	if err != nil {
+		m.output.Err("  \"" + p.Name + "\" build fail: " + err.Error())
+		if out != "" {
+			m.output.Err(indentOutput(out))
+		}
+		res.Status, res.Error = ui.StatusFailed, err.Error()
+	} else {
+		m.output.Ok("  \"" + p.Name + "\" build success")
+		if out != "" {
+			m.output.Ok(indentOutput(out))
+		}
+	}
+	m.report(res)
+	if rerr := build.Record(m.statePath, p.Name, err); rerr != nil {
+		m.output.Err("  \"" + p.Name + "\" failed to record build state: " + rerr.Error())
+	}
+	return err
+}
+
+// buildUpdated runs p's build command after an update from before to after,
+// unless the update didn't move it and its last build succeeded.
+func (m *Manager) buildUpdated(ctx context.Context, p plug.Plugin, dir, before, after string) error {
+	if p.Build == "" || !build.Needed(m.statePath, p.Name, before, after) {
+		return nil
+	}
+	return m.runBuild(ctx, p, dir)
+}
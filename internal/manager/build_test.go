@@ -0,0 +1,146 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/manager"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/state"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+// dirCloner "clones" by creating the plugin directory, so build commands
+// have somewhere to run.
+type dirCloner struct{}
+
+func (dirCloner) Clone(_ context.Context, opts git.CloneOptions) error {
+	return os.MkdirAll(opts.Dir, 0o755)
+}
+
+// result returns the result reported for the named plugin and action.
+func result(t *testing.T, output *ui.MockOutput, name, action string) ui.Result {
+	t.Helper()
+	for _, r := range output.Results {
+		if r.Name == name && r.Action == action {
+			return r
+		}
+	}
+	t.Fatalf("no %s result for %s in %+v", action, name, output.Results)
+	return ui.Result{}
+}
+
+func TestInstallRunsBuild(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, dirCloner{}, git.NewMockPuller(), git.NewMockValidator(), output)
+
+	mgr.Install(context.Background(), []plug.Plugin{
+		{Name: "tmux-thumbs", Spec: "fcsonline/tmux-thumbs", Build: "echo compiled; touch thumbs"},
+	})
+
+	if _, err := os.Stat(filepath.Join(pluginDir, "tmux-thumbs", "thumbs")); err != nil {
+		t.Errorf("expected the build to run in the plugin directory: %v", err)
+	}
+	if r := result(t, output, "tmux-thumbs", "build"); r.Status != ui.StatusOK || r.Output != "compiled" {
+		t.Errorf("build result = %+v", r)
+	}
+	if r := result(t, output, "tmux-thumbs", "install"); r.Status != ui.StatusOK {
+		t.Errorf("install result = %+v", r)
+	}
+}
+
+func TestInstallBuildFails(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, dirCloner{}, git.NewMockPuller(), git.NewMockValidator(), output)
+
+	mgr.Install(context.Background(), []plug.Plugin{
+		{Name: "tmux-thumbs", Spec: "fcsonline/tmux-thumbs", Build: "echo 'cargo: not found' >&2; exit 127"},
+	})
+
+	if r := result(t, output, "tmux-thumbs", "build"); r.Status != ui.StatusFailed || r.Output != "cargo: not found" {
+		t.Errorf("build result = %+v", r)
+	}
+	if r := result(t, output, "tmux-thumbs", "install"); r.Status != ui.StatusFailed || !strings.Contains(r.Error, "exit status 127") {
+		t.Errorf("install result = %+v", r)
+	}
+	if _, err := os.Stat(filepath.Join(pluginDir, "tmux-thumbs")); !os.IsNotExist(err) {
+		t.Errorf("expected the unbuilt plugin to be removed, stat error = %v", err)
+	}
+	fails := 0
+	for _, msg := range output.ErrMsgs {
+		if strings.Contains(msg, "exit status 127") {
+			fails++
+		}
+	}
+	if fails != 1 {
+		t.Errorf("expected the build failure to be reported once, got %q", output.ErrMsgs)
+	}
+}
+
+func TestUpdateBuildFails(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	setupInstalledPlugin(t, pluginDir, "tmux-thumbs")
+	validator := git.NewMockValidator()
+	validator.Valid[filepath.Join(pluginDir, "tmux-thumbs")] = true
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), validator, output)
+
+	mgr.Update(context.Background(), []plug.Plugin{
+		{Name: "tmux-thumbs", Build: "exit 3"},
+	}, []string{"tmux-thumbs"})
+
+	if r := result(t, output, "tmux-thumbs", "update"); r.Status != ui.StatusFailed || !strings.Contains(r.Error, "exit status 3") {
+		t.Errorf("update result = %+v", r)
+	}
+	if !output.HasFailed() {
+		t.Error("expected the failed build to fail the update")
+	}
+}
+
+func TestUpdateBuildsOnlyWhenMovedOrFailed(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	setupInstalledPlugin(t, pluginDir, "tmux-thumbs")
+	validator := git.NewMockValidator()
+	validator.Valid[filepath.Join(pluginDir, "tmux-thumbs")] = true
+	// The mock rev parser always returns the same commit, so updates
+	// never move the plugin.
+	update := func(build string) *ui.MockOutput {
+		output := ui.NewMockOutput()
+		mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), validator, output,
+			manager.WithRevParser(git.NewMockRevParser()), manager.WithStatePath(filepath.Join(pluginDir, "state")))
+		mgr.Update(context.Background(), []plug.Plugin{{Name: "tmux-thumbs", Build: build}}, []string{"tmux-thumbs"})
+		return output
+	}
+	built := func(output *ui.MockOutput) bool {
+		for _, r := range output.Results {
+			if r.Action == "build" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if built(update("exit 0")) {
+		t.Error("expected an update that didn't move the plugin not to build")
+	}
+
+	// A build that failed is retried by the next update, moved or not.
+	if err := state.SetBuildFailed(filepath.Join(pluginDir, "state"), "tmux-thumbs", true); err != nil {
+		t.Fatal(err)
+	}
+	if !built(update("exit 0")) {
+		t.Error("expected the failed build to be retried")
+	}
+	if state.Load(filepath.Join(pluginDir, "state")).BuildFailed("tmux-thumbs") {
+		t.Error("expected the successful build to clear the failure")
+	}
+	if built(update("exit 0")) {
+		t.Error("expected no build once the last one succeeded")
+	}
+}
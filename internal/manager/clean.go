@@ -3,6 +3,7 @@ package manager
 import (
 	"os"
 
+	"github.com/tmuxpack/tpack/internal/history"
 	"github.com/tmuxpack/tpack/internal/plug"
 )
 
@@ -10,10 +11,13 @@ func (m *Manager) cleanPlugins(plugins []plug.Plugin) {
 	orphans := plug.FindOrphans(plugins, m.pluginPath)
 	for _, o := range orphans {
 		m.output.Ok("Removing \"" + o.Name + "\"")
+		ev := history.Entry{Action: history.Clean, Plugin: o.Name}
 		if err := os.RemoveAll(o.Path); err != nil {
 			m.output.Err("  \"" + o.Name + "\" clean fail")
+			ev.Error = err.Error()
 		} else {
 			m.output.Ok("  \"" + o.Name + "\" clean success")
 		}
+		m.record(ev)
 	}
 }
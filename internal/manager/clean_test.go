@@ -59,6 +59,18 @@ func TestCleanRemovesUnlisted(t *testing.T) {
 	}
 }
 
+func TestCleanKeepsDisabledPlugins(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	setupInstalledPlugin(t, pluginDir, "tmux-cpu")
+
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), ui.NewMockOutput())
+	mgr.Clean(context.Background(), []plug.Plugin{{Name: "tmux-cpu", Disabled: true}})
+
+	if _, err := os.Stat(filepath.Join(pluginDir, "tmux-cpu")); err != nil {
+		t.Error("a disabled plugin's directory should be kept")
+	}
+}
+
 func TestCleanNeverRemovesTpm(t *testing.T) {
 	pluginDir := setupTestDir(t)
 	setupInstalledPlugin(t, pluginDir, "tpm")
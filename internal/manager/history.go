@@ -0,0 +1,76 @@
+package manager
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmuxpack/tpack/internal/history"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+// record appends e to the operation history, when a state path is
+// configured, and reports it as a structured result.
+func (m *Manager) record(e history.Entry) {
+	status := ui.StatusOK
+	if !e.Success() {
+		status = ui.StatusFailed
+	}
+	m.report(ui.Result{
+		Name:   e.Plugin,
+		Action: string(e.Action),
+		Status: status,
+		Before: e.Before,
+		After:  e.After,
+		Error:  e.Error,
+	})
+
+	if m.statePath == "" {
+		return
+	}
+	if err := history.Append(m.statePath, e); err != nil {
+		m.output.Err("Failed to write history: " + err.Error())
+	}
+}
+
+// report passes r to the output if it accepts structured results.
+func (m *Manager) report(r ui.Result) {
+	ui.Report(m.output, r)
+}
+
+// head returns the current commit of the named plugin, or "" if unknown.
+func (m *Manager) head(ctx context.Context, name string) string {
+	if m.revParser == nil {
+		return ""
+	}
+	hash, err := m.revParser.RevParse(ctx, plug.PluginPath(name, m.pluginPath))
+	if err != nil {
+		return ""
+	}
+	return hash
+}
+
+// commitCount returns how many commits lie between before and after.
+func (m *Manager) commitCount(ctx context.Context, name, before, after string) int {
+	if m.logger == nil || before == "" || after == "" || before == after {
+		return 0
+	}
+	commits, err := m.logger.Log(ctx, plug.PluginPath(name, m.pluginPath), before, after)
+	if err != nil {
+		return 0
+	}
+	return len(commits)
+}
+
+// failureText summarizes a failed git operation for the history log,
+// preferring git's own last line of output over a bare exit status.
+func failureText(output string, err error) string {
+	output = strings.TrimSpace(output)
+	if i := strings.LastIndexByte(output, '\n'); i >= 0 {
+		output = output[i+1:]
+	}
+	if output != "" {
+		return output
+	}
+	return err.Error()
+}
@@ -0,0 +1,124 @@
+package manager_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/history"
+	"github.com/tmuxpack/tpack/internal/manager"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+func loadHistory(t *testing.T, statePath string) []history.Entry {
+	t.Helper()
+	entries, err := history.Load(statePath)
+	if err != nil {
+		t.Fatalf("history.Load: %v", err)
+	}
+	return entries
+}
+
+func TestInstallRecordsHistory(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	statePath := t.TempDir()
+	cloner := git.NewMockCloner()
+	mgr := manager.New(pluginDir, cloner, git.NewMockPuller(), git.NewMockValidator(), ui.NewMockOutput(),
+		manager.WithRevParser(git.NewMockRevParser()),
+		manager.WithStatePath(statePath),
+	)
+
+	mgr.Install(context.Background(), []plug.Plugin{{Name: "tmux-yank", Spec: "tmux-plugins/tmux-yank"}})
+
+	entries := loadHistory(t, statePath)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %+v", entries)
+	}
+	e := entries[0]
+	if e.Action != history.Install || e.Plugin != "tmux-yank" || e.After != "abc123" || !e.Success() {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+}
+
+func TestInstallFailureRecordsHistory(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	statePath := t.TempDir()
+	cloner := git.NewMockCloner()
+	cloner.Err = errors.New("clone failed")
+	mgr := manager.New(pluginDir, cloner, git.NewMockPuller(), git.NewMockValidator(), ui.NewMockOutput(),
+		manager.WithStatePath(statePath),
+	)
+
+	mgr.Install(context.Background(), []plug.Plugin{{Name: "tmux-yank", Spec: "tmux-plugins/tmux-yank"}})
+
+	entries := loadHistory(t, statePath)
+	if len(entries) != 1 || entries[0].Success() || entries[0].Error != "download fail" {
+		t.Errorf("expected one failed install entry, got %+v", entries)
+	}
+}
+
+func TestUpdateRecordsHistory(t *testing.T) {
+	rp := &headSequence{hashes: []string{"aaa111", "bbb222"}}
+	logger := git.NewMockLogger()
+	logger.Commits = []git.Commit{{Hash: "bbb222"}, {Hash: "ccc333"}}
+	mgr, _, statePath, _, _ := newRollbackManager(t, git.NewMockPuller(), rp, manager.WithLogger(logger))
+
+	mgr.Update(context.Background(), []plug.Plugin{{Name: "tmux-yank"}}, []string{"tmux-yank"})
+
+	entries := loadHistory(t, statePath)
+	want := history.Entry{Action: history.Update, Plugin: "tmux-yank", Before: "aaa111", After: "bbb222", Commits: 2}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %+v", entries)
+	}
+	got := entries[0]
+	got.Time = want.Time
+	if got != want {
+		t.Errorf("entry = %+v, want %+v", got, want)
+	}
+}
+
+func TestUpdateWithoutChangesSkipsHistory(t *testing.T) {
+	mgr, _, statePath, _, _ := newRollbackManager(t, git.NewMockPuller(), git.NewMockRevParser())
+
+	mgr.Update(context.Background(), []plug.Plugin{{Name: "tmux-yank"}}, []string{"tmux-yank"})
+
+	if entries := loadHistory(t, statePath); len(entries) != 0 {
+		t.Errorf("expected no entries for a no-op update, got %+v", entries)
+	}
+}
+
+func TestUpdateFailureRecordsHistory(t *testing.T) {
+	puller := git.NewMockPuller()
+	puller.Output = "Updating a..b\nfatal: could not read from remote"
+	puller.Err = errors.New("exit status 128")
+	mgr, _, statePath, _, _ := newRollbackManager(t, puller, git.NewMockRevParser())
+
+	mgr.Update(context.Background(), []plug.Plugin{{Name: "tmux-yank"}}, []string{"tmux-yank"})
+
+	entries := loadHistory(t, statePath)
+	if len(entries) != 1 || entries[0].Error != "fatal: could not read from remote" {
+		t.Errorf("expected failed update entry with git's message, got %+v", entries)
+	}
+}
+
+func TestCleanRecordsHistory(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	statePath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(pluginDir, "stale"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), ui.NewMockOutput(),
+		manager.WithStatePath(statePath),
+	)
+
+	mgr.Clean(context.Background(), nil)
+
+	entries := loadHistory(t, statePath)
+	if len(entries) != 1 || entries[0].Action != history.Clean || entries[0].Plugin != "stale" {
+		t.Errorf("expected clean entry for stale, got %+v", entries)
+	}
+}
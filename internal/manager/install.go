@@ -5,7 +5,10 @@ import (
 	"os"
 
 	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/history"
+	"github.com/tmuxpack/tpack/internal/lock"
 	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/ui"
 )
 
 func (m *Manager) verifyPathPermissions() {
@@ -19,27 +22,130 @@ func (m *Manager) verifyPathPermissions() {
 	_ = os.Remove(f.Name()) //nolint:gosec // path from os.CreateTemp is safe
 }
 
-func (m *Manager) installPlugin(ctx context.Context, p plug.Plugin) {
+func (m *Manager) installPlugin(ctx context.Context, p plug.Plugin, lf *lock.File) {
 	name := p.Name
 
+	if p.IsLocal() {
+		m.installLocal(p)
+		return
+	}
+
 	if m.IsPluginInstalled(name) {
-		m.output.Ok("Already installed \"" + name + "\"")
+		m.installedPlugin(ctx, p, lf)
 		return
 	}
 
 	m.output.Ok("Installing \"" + name + "\"")
 
+	ev := history.Entry{Action: history.Install, Plugin: name}
+	defer func() { m.record(ev) }()
+	fail := func(msg string) {
+		m.output.Err("  \"" + name + "\" " + msg)
+		ev.Error = msg
+	}
+
 	dir := plug.PluginPath(name, m.pluginPath)
+	entry, locked := m.lockedEntry(lf, p)
+
+	// A locked commit wins over re-resolving the version pin.
+	opts := git.CloneOptions{URL: p.Spec, Dir: dir, Branch: p.Branch}
+	var pin git.Pin
+	if p.Version != "" && !locked {
+		var ok bool
+		if pin, ok = m.resolvePin(ctx, lf, p); !ok {
+			ev.Error = "version " + p.Version + " not resolved"
+			return
+		}
+		if pin.Tag != "" {
+			opts.Branch = pin.Tag
+		}
+	}
 
-	err := git.CloneWithFallback(ctx, m.cloner, git.CloneOptions{
-		URL:    p.Spec,
-		Dir:    dir,
-		Branch: p.Branch,
-	}, plug.NormalizeURL)
+	url, err := git.CloneWithFallbackURL(ctx, m.cloner, opts, plug.NormalizeURL)
 
 	if err != nil {
-		m.output.Err("  \"" + name + "\" download fail")
+		fail("download fail")
+		return
+	}
+	m.output.Ok("  \"" + name + "\" download success")
+
+	switch {
+	case locked && m.checkouter != nil:
+		if err := m.checkouter.Checkout(ctx, dir, entry.Commit); err != nil {
+			fail("checkout of locked commit " + git.ShortHash(entry.Commit) + " failed")
+			return
+		}
+		m.output.Ok("  \"" + name + "\" locked at " + git.ShortHash(entry.Commit))
+	case pin.Commit != "":
+		if m.checkouter == nil {
+			fail("commit pins are not supported")
+			return
+		}
+		if err := m.checkouter.Checkout(ctx, dir, pin.Commit); err != nil {
+			fail("checkout of commit " + git.ShortHash(pin.Commit) + " failed")
+			return
+		}
+		m.output.Ok("  \"" + name + "\" pinned at " + git.ShortHash(pin.Commit))
+	case pin.Tag != "":
+		m.output.Ok("  \"" + name + "\" pinned at " + pin.Tag)
+	}
+
+	// A plugin that failed to build is removed, so installing retries it.
+	// runBuild has already reported the failure.
+	if err := m.runBuild(ctx, p, dir); err != nil {
+		ev.Error = err.Error()
+		if err := os.RemoveAll(dir); err != nil {
+			m.output.Err("  \"" + name + "\" failed to remove: " + err.Error())
+		}
+		return
+	}
+	ev.After = m.head(ctx, name)
+	m.recordLock(ctx, lf, p, url)
+}
+
+// installedPlugin handles the install of a plugin that is already there.
+// Its checkout is adopted into a fresh or partial lockfile, unless its
+// branch or version pin changed since it was locked: HEAD is then still at
+// the old declaration, so a new version pin is checked out as an update
+// would, and a new branch is left to "tpack update".
+func (m *Manager) installedPlugin(ctx context.Context, p plug.Plugin, lf *lock.File) {
+	if lf != nil {
+		m.lockMu.Lock()
+		prev, ok := lf.Get(p.Name)
+		m.lockMu.Unlock()
+		if ok && prev.Spec == p.Spec && !prev.Matches(p.Spec, p.Branch, p.Version) {
+			if p.Version != "" && prev.Branch == p.Branch {
+				m.output.Ok("Moving \"" + p.Name + "\" to version " + p.Version)
+				m.updatePinned(ctx, p, lf)
+				return
+			}
+			m.output.Ok("\"" + p.Name + "\" changed since it was locked, run \"tpack update " + p.Name + "\" to apply")
+			m.report(ui.Result{Name: p.Name, Action: string(history.Install), Status: ui.StatusSkipped, Error: "changed since locked"})
+			return
+		}
+	}
+
+	m.output.Ok("Already installed \"" + p.Name + "\"")
+	m.report(ui.Result{Name: p.Name, Action: string(history.Install), Status: ui.StatusUnchanged})
+	if _, ok := m.lockedEntry(lf, p); !ok {
+		m.recordLock(ctx, lf, p, "")
+	}
+}
+
+// installLocal links a local plugin's working tree into the plugin directory.
+func (m *Manager) installLocal(p plug.Plugin) {
+	if plug.IsLinked(p, m.pluginPath) {
+		m.output.Ok("Already installed \"" + p.Name + "\"")
+		m.report(ui.Result{Name: p.Name, Action: string(history.Install), Status: ui.StatusUnchanged})
+		return
+	}
+	m.output.Ok("Linking \"" + p.Name + "\" to " + p.LocalPath)
+	ev := history.Entry{Action: history.Install, Plugin: p.Name}
+	if err := plug.LinkLocal(p, m.pluginPath); err != nil {
+		m.output.Err("  \"" + p.Name + "\" link fail: " + err.Error())
+		ev.Error = err.Error()
 	} else {
-		m.output.Ok("  \"" + name + "\" download success")
+		m.output.Ok("  \"" + p.Name + "\" link success")
 	}
+	m.record(ev)
 }
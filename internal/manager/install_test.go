@@ -56,6 +56,25 @@ func TestInstallNewPlugin(t *testing.T) {
 	}
 }
 
+func TestInstallNamed(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	cloner := git.NewMockCloner()
+	output := ui.NewMockOutput()
+
+	mgr := manager.New(pluginDir, cloner, git.NewMockPuller(), git.NewMockValidator(), output)
+
+	plugins := []plug.Plugin{
+		{Raw: "tmux-plugins/tmux-sensible", Name: "tmux-sensible", Spec: "tmux-plugins/tmux-sensible"},
+		{Raw: "tmux-plugins/tmux-yank", Name: "tmux-yank", Spec: "tmux-plugins/tmux-yank"},
+	}
+
+	mgr.InstallNamed(context.Background(), plugins, []string{"tmux-yank"})
+
+	if len(cloner.Calls) != 1 || cloner.Calls[0].URL != "tmux-plugins/tmux-yank" {
+		t.Errorf("expected only tmux-yank to be cloned, got %+v", cloner.Calls)
+	}
+}
+
 func TestInstallAlreadyInstalled(t *testing.T) {
 	pluginDir := setupTestDir(t)
 	// Create plugin directory to simulate already installed.
@@ -0,0 +1,136 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/lock"
+	"github.com/tmuxpack/tpack/internal/manager"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+func localPlugin(t *testing.T) plug.Plugin {
+	t.Helper()
+	src := filepath.Join(t.TempDir(), "tmux-foo")
+	if err := os.MkdirAll(src, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return plug.Plugin{Raw: "file:" + src, Name: "tmux-foo", Spec: "file:" + src, LocalPath: src}
+}
+
+func TestInstallLinksLocalPlugin(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	cloner := git.NewMockCloner()
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, cloner, git.NewMockPuller(), git.NewMockValidator(), output)
+	p := localPlugin(t)
+
+	mgr.Install(context.Background(), []plug.Plugin{p})
+
+	if output.HasFailed() {
+		t.Fatalf("unexpected errors: %v", output.ErrMsgs)
+	}
+	if len(cloner.Calls) != 0 {
+		t.Errorf("local plugins should not be cloned, got %+v", cloner.Calls)
+	}
+	if !plug.IsLinked(p, pluginDir) {
+		t.Error("expected plugin directory to link to the local path")
+	}
+}
+
+func TestInstallLocalPluginKeepsClone(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	os.MkdirAll(filepath.Join(pluginDir, "tmux-foo"), 0o755)
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output)
+
+	mgr.Install(context.Background(), []plug.Plugin{localPlugin(t)})
+
+	if !output.HasFailed() {
+		t.Error("expected failure when a cloned directory is in the way")
+	}
+}
+
+func TestUpdateSkipsLocalPlugin(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	puller := git.NewMockPuller()
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, git.NewMockCloner(), puller, git.NewMockValidator(), output)
+	p := localPlugin(t)
+
+	mgr.Update(context.Background(), []plug.Plugin{p}, []string{"all"})
+	mgr.Update(context.Background(), []plug.Plugin{p}, []string{"tmux-foo"})
+
+	if len(puller.Calls) != 0 {
+		t.Errorf("local plugins should never be pulled, got %+v", puller.Calls)
+	}
+	if output.HasFailed() {
+		t.Errorf("unexpected errors: %v", output.ErrMsgs)
+	}
+}
+
+func TestCleanKeepsLocalPluginLink(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), ui.NewMockOutput())
+	p := localPlugin(t)
+	mgr.Install(context.Background(), []plug.Plugin{p})
+
+	// Even once undeclared, the link is not removed and the source is untouched.
+	mgr.Clean(context.Background(), nil)
+
+	if _, err := os.Lstat(filepath.Join(pluginDir, "tmux-foo")); err != nil {
+		t.Errorf("expected link to survive clean: %v", err)
+	}
+	if _, err := os.Stat(p.LocalPath); err != nil {
+		t.Errorf("local source must never be removed: %v", err)
+	}
+}
+
+func TestLocalPluginNotLocked(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	lockPath := filepath.Join(t.TempDir(), "tpack.lock")
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output,
+		manager.WithRevParser(git.NewMockRevParser()),
+		manager.WithCheckouter(git.NewMockCheckouter()),
+		manager.WithLockPath(lockPath),
+	)
+	p := localPlugin(t)
+
+	mgr.Install(context.Background(), []plug.Plugin{p})
+	lf, err := lock.Load(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := lf.Get("tmux-foo"); ok {
+		t.Error("local plugins should not be recorded in the lockfile")
+	}
+
+	// A lockfile without the local plugin is not stale.
+	os.Remove(filepath.Join(pluginDir, "tmux-foo"))
+	mgr.Sync(context.Background(), []plug.Plugin{p})
+	if output.HasFailed() {
+		t.Errorf("unexpected sync errors: %v", output.ErrMsgs)
+	}
+	if !plug.IsLinked(p, pluginDir) {
+		t.Error("expected sync to link the local plugin")
+	}
+}
+
+func TestSourceLocalPluginInPlace(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	p := localPlugin(t)
+	marker := filepath.Join(t.TempDir(), "sourced")
+	os.WriteFile(filepath.Join(p.LocalPath, "foo.tmux"), []byte("#!/bin/sh\ntouch "+marker+"\n"), 0o755)
+
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), ui.NewMockOutput())
+	mgr.Source(context.Background(), []plug.Plugin{p})
+
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("expected local plugin to be sourced from its working tree")
+	}
+}
@@ -0,0 +1,123 @@
+package manager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/tmuxpack/tpack/internal/lock"
+	"github.com/tmuxpack/tpack/internal/plug"
+)
+
+// lockFileMu serializes rewrites of the lockfile by the managers of this
+// process, such as the TUI's concurrent installs and updates.
+var lockFileMu sync.Mutex
+
+// lockEnabled reports whether the lockfile should be read and written.
+func (m *Manager) lockEnabled() bool {
+	return m.lockPath != "" && m.revParser != nil
+}
+
+// loadLock reads the lockfile, returning nil when locking is disabled or
+// the file cannot be parsed.
+func (m *Manager) loadLock() *lock.File {
+	if !m.lockEnabled() {
+		return nil
+	}
+	lf, err := lock.Load(m.lockPath)
+	if err != nil {
+		m.output.Err("Failed to read lockfile: " + err.Error())
+		return nil
+	}
+	return lf
+}
+
+// saveLock writes the entries recorded in lf over the lockfile, so entries
+// other operations saved since lf was loaded are kept, and drops entries
+// for plugins no longer declared.
+func (m *Manager) saveLock(lf *lock.File, plugins []plug.Plugin) {
+	if lf == nil {
+		return
+	}
+	keep := make(map[string]bool, len(plugins))
+	for _, p := range plugins {
+		if !p.IsLocal() {
+			keep[p.Name] = true
+		}
+	}
+
+	m.lockMu.Lock()
+	defer m.lockMu.Unlock()
+	lockFileMu.Lock()
+	defer lockFileMu.Unlock()
+	cur, err := lock.Load(m.lockPath)
+	if err != nil {
+		m.output.Err("Failed to read lockfile: " + err.Error())
+		return
+	}
+	cur.Merge(lf)
+	cur.Prune(keep)
+	if err := lock.Save(m.lockPath, cur); err != nil {
+		m.output.Err("Failed to write lockfile: " + err.Error())
+	}
+}
+
+// lockedEntry returns the lock entry for p if it still matches p's declaration.
+// Local plugins are never locked.
+func (m *Manager) lockedEntry(lf *lock.File, p plug.Plugin) (lock.Entry, bool) {
+	if lf == nil || p.IsLocal() {
+		return lock.Entry{}, false
+	}
+	m.lockMu.Lock()
+	defer m.lockMu.Unlock()
+	e, ok := lf.Get(p.Name)
+	if !ok || !e.Matches(p.Spec, p.Branch, p.Version) || e.Commit == "" {
+		return lock.Entry{}, false
+	}
+	return e, true
+}
+
+// recordLock stores the current HEAD of p in lf. An empty url keeps the
+// previously locked URL, or derives one from the spec.
+func (m *Manager) recordLock(ctx context.Context, lf *lock.File, p plug.Plugin, url string) {
+	if lf == nil || p.IsLocal() {
+		return
+	}
+	dir := plug.PluginPath(p.Name, m.pluginPath)
+	commit, err := m.revParser.RevParse(ctx, dir)
+	if err != nil {
+		return
+	}
+
+	m.lockMu.Lock()
+	defer m.lockMu.Unlock()
+	if url == "" {
+		if prev, ok := lf.Get(p.Name); ok && prev.Spec == p.Spec {
+			url = prev.URL
+		} else {
+			url = cloneURL(p.Spec)
+		}
+	}
+	lf.Set(lock.Entry{
+		Name:    p.Name,
+		Spec:    p.Spec,
+		URL:     url,
+		Branch:  p.Branch,
+		Version: p.Version,
+		Commit:  commit,
+	})
+}
+
+// cloneURL guesses the URL a spec resolves to: full URLs and local
+// repositories are used as-is, shorthands are expanded to GitHub.
+func cloneURL(spec string) string {
+	if strings.Contains(spec, "://") || strings.Contains(spec, "git@") || filepath.IsAbs(spec) {
+		return spec
+	}
+	if _, err := os.Stat(spec); err == nil {
+		return spec
+	}
+	return plug.NormalizeURL(spec)
+}
@@ -0,0 +1,232 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/lock"
+	"github.com/tmuxpack/tpack/internal/manager"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+func TestInstallWritesLockfile(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	lockPath := filepath.Join(t.TempDir(), "tpack.lock")
+
+	cloner := git.NewMockCloner()
+	revParser := git.NewMockRevParser()
+	revParser.Hash = "1111111111111111111111111111111111111111"
+	output := ui.NewMockOutput()
+
+	mgr := manager.New(pluginDir, cloner, git.NewMockPuller(), git.NewMockValidator(), output,
+		manager.WithRevParser(revParser),
+		manager.WithCheckouter(git.NewMockCheckouter()),
+		manager.WithLockPath(lockPath),
+	)
+
+	plugins := []plug.Plugin{
+		{Raw: "/srv/repos/tmux-sensible", Name: "tmux-sensible", Spec: "/srv/repos/tmux-sensible", Branch: "main"},
+	}
+	mgr.Install(context.Background(), plugins)
+
+	lf, err := lock.Load(lockPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	e, ok := lf.Get("tmux-sensible")
+	if !ok {
+		t.Fatalf("expected lock entry, got %+v", lf.Plugins)
+	}
+	if e.Commit != revParser.Hash {
+		t.Errorf("Commit = %q, want %q", e.Commit, revParser.Hash)
+	}
+	if e.URL != "/srv/repos/tmux-sensible" {
+		t.Errorf("URL = %q, want the URL used for cloning", e.URL)
+	}
+	if e.Branch != "main" {
+		t.Errorf("Branch = %q, want main", e.Branch)
+	}
+}
+
+func TestInstallChecksOutLockedCommit(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	lockPath := filepath.Join(t.TempDir(), "tpack.lock")
+	locked := "2222222222222222222222222222222222222222"
+	lock.Save(lockPath, &lock.File{Plugins: []lock.Entry{
+		{Name: "repo", Spec: "user/repo", URL: "https://github.com/user/repo", Commit: locked},
+	}})
+
+	checkouter := git.NewMockCheckouter()
+	revParser := git.NewMockRevParser()
+	revParser.Hash = locked
+	output := ui.NewMockOutput()
+
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output,
+		manager.WithRevParser(revParser),
+		manager.WithCheckouter(checkouter),
+		manager.WithLockPath(lockPath),
+	)
+
+	mgr.Install(context.Background(), []plug.Plugin{{Name: "repo", Spec: "user/repo"}})
+
+	if len(checkouter.Calls) != 1 {
+		t.Fatalf("expected 1 checkout call, got %d", len(checkouter.Calls))
+	}
+	if checkouter.Calls[0].Ref != locked {
+		t.Errorf("checkout ref = %q, want %q", checkouter.Calls[0].Ref, locked)
+	}
+	if checkouter.Calls[0].Dir != filepath.Join(pluginDir, "repo") {
+		t.Errorf("checkout dir = %q", checkouter.Calls[0].Dir)
+	}
+	if output.HasFailed() {
+		t.Errorf("unexpected errors: %v", output.ErrMsgs)
+	}
+}
+
+func TestInstallIgnoresStaleLockEntry(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	lockPath := filepath.Join(t.TempDir(), "tpack.lock")
+	lock.Save(lockPath, &lock.File{Plugins: []lock.Entry{
+		{Name: "repo", Spec: "user/repo", Branch: "old", Commit: "3333333"},
+	}})
+
+	checkouter := git.NewMockCheckouter()
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), ui.NewMockOutput(),
+		manager.WithRevParser(git.NewMockRevParser()),
+		manager.WithCheckouter(checkouter),
+		manager.WithLockPath(lockPath),
+	)
+
+	// The branch changed since locking, so the locked commit must not be used.
+	mgr.Install(context.Background(), []plug.Plugin{{Name: "repo", Spec: "user/repo", Branch: "new"}})
+
+	if len(checkouter.Calls) != 0 {
+		t.Errorf("expected no checkout for stale entry, got %v", checkouter.Calls)
+	}
+	lf, _ := lock.Load(lockPath)
+	if e, _ := lf.Get("repo"); e.Branch != "new" {
+		t.Errorf("lock entry branch = %q, want it rewritten to %q", e.Branch, "new")
+	}
+}
+
+func TestInstallMovesInstalledPluginToNewPin(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "tpack.lock")
+	lock.Save(lockPath, &lock.File{Plugins: []lock.Entry{
+		{Name: "repo", Spec: "user/repo", Version: "^1.0", Commit: "1111111"},
+	}})
+	mgr, pluginDir, cloner, _, checkouter, validator, output := newPinManager(t, manager.WithLockPath(lockPath))
+	dir := filepath.Join(pluginDir, "repo")
+	os.MkdirAll(dir, 0o755)
+	validator.Valid[dir] = true
+
+	// The pin went from ^1.0 to ^2.0 since locking, so HEAD is still at 1.x.
+	mgr.Install(context.Background(), []plug.Plugin{plug.ParseSpec("user/repo@^2.0")})
+
+	if output.HasFailed() {
+		t.Fatalf("unexpected errors: %v", output.ErrMsgs)
+	}
+	if len(cloner.Calls) != 0 {
+		t.Errorf("installed plugins should not be cloned, got %+v", cloner.Calls)
+	}
+	want := git.MockCheckoutCall{Dir: dir, Ref: "v2.1.0"}
+	if len(checkouter.Calls) != 1 || checkouter.Calls[0] != want {
+		t.Errorf("expected checkout %+v, got %+v", want, checkouter.Calls)
+	}
+	lf, _ := lock.Load(lockPath)
+	if e, _ := lf.Get("repo"); e.Version != "^2.0" || e.Commit != "abc123" {
+		t.Errorf("expected the new pin to be locked at the checked out commit, got %+v", e)
+	}
+}
+
+func TestInstallKeepsInstalledPluginOnNewBranch(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	setupInstalledPlugin(t, pluginDir, "repo")
+	lockPath := filepath.Join(t.TempDir(), "tpack.lock")
+	lock.Save(lockPath, &lock.File{Plugins: []lock.Entry{
+		{Name: "repo", Spec: "user/repo", Branch: "old", Commit: "3333333"},
+	}})
+
+	validator := git.NewMockValidator()
+	validator.Valid[filepath.Join(pluginDir, "repo")] = true
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), validator, output,
+		manager.WithRevParser(git.NewMockRevParser()),
+		manager.WithLockPath(lockPath),
+	)
+
+	mgr.Install(context.Background(), []plug.Plugin{{Name: "repo", Spec: "user/repo", Branch: "new"}})
+
+	lf, _ := lock.Load(lockPath)
+	if e, _ := lf.Get("repo"); e.Branch != "old" || e.Commit != "3333333" {
+		t.Errorf("expected the old branch's HEAD not to be locked for the new one, got %+v", e)
+	}
+	if !strings.Contains(strings.Join(output.OkMsgs, "\n"), "tpack update repo") {
+		t.Errorf("expected a hint to run update, got %v", output.OkMsgs)
+	}
+}
+
+func TestUpdateRewritesLockfile(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	setupInstalledPlugin(t, pluginDir, "tmux-yank")
+	setupInstalledPlugin(t, pluginDir, "tmux-sensible")
+	lockPath := filepath.Join(t.TempDir(), "tpack.lock")
+	lock.Save(lockPath, &lock.File{Plugins: []lock.Entry{
+		{Name: "tmux-yank", Spec: "tmux-plugins/tmux-yank", URL: "https://example.com/yank", Commit: "old"},
+		{Name: "removed", Spec: "user/removed", Commit: "gone"},
+	}})
+
+	validator := git.NewMockValidator()
+	validator.Valid[filepath.Join(pluginDir, "tmux-yank")] = true
+	validator.Valid[filepath.Join(pluginDir, "tmux-sensible")] = true
+	revParser := git.NewMockRevParser()
+	revParser.Hash = "new"
+
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), validator, ui.NewMockOutput(),
+		manager.WithRevParser(revParser),
+		manager.WithLockPath(lockPath),
+	)
+
+	plugins := []plug.Plugin{
+		{Name: "tmux-yank", Spec: "tmux-plugins/tmux-yank"},
+		{Name: "tmux-sensible", Spec: "tmux-plugins/tmux-sensible"},
+	}
+	mgr.Update(context.Background(), plugins, []string{"all"})
+
+	lf, err := lock.Load(lockPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(lf.Plugins) != 2 {
+		t.Fatalf("expected 2 entries after update, got %+v", lf.Plugins)
+	}
+	yank, _ := lf.Get("tmux-yank")
+	if yank.Commit != "new" {
+		t.Errorf("tmux-yank commit = %q, want %q", yank.Commit, "new")
+	}
+	if yank.URL != "https://example.com/yank" {
+		t.Errorf("tmux-yank URL = %q, want previously locked URL kept", yank.URL)
+	}
+	if _, ok := lf.Get("removed"); ok {
+		t.Error("entries for undeclared plugins should be pruned")
+	}
+}
+
+func TestLockDisabledWithoutPath(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	checkouter := git.NewMockCheckouter()
+
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), ui.NewMockOutput(),
+		manager.WithRevParser(git.NewMockRevParser()),
+		manager.WithCheckouter(checkouter),
+	)
+	mgr.Install(context.Background(), []plug.Plugin{{Name: "repo", Spec: "user/repo"}})
+
+	if len(checkouter.Calls) != 0 {
+		t.Errorf("expected no checkout without a lockfile, got %v", checkouter.Calls)
+	}
+}
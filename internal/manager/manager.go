@@ -4,9 +4,14 @@ package manager
 import (
 	"context"
 	"os"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/tmuxpack/tpack/internal/git"
 	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/profile"
+	"github.com/tmuxpack/tpack/internal/tmux"
 	"github.com/tmuxpack/tpack/internal/ui"
 )
 
@@ -17,16 +22,109 @@ type Manager struct {
 	puller     git.Puller
 	validator  git.Validator
 	output     ui.Output
+
+	revParser  git.RevParser
+	logger     git.Logger
+	checkouter git.Checkouter
+	worktree   git.WorktreeChecker
+	tags       git.TagLister
+	lockPath   string
+	lockMu     sync.Mutex
+	statePath  string
+	runner     tmux.Runner
+
+	parallelSource bool
+	sourceTimeout  time.Duration
+	scriptEnv      []string
+	profile        *profile.Run
+	profileMu      sync.Mutex
+}
+
+// Option configures optional Manager behavior.
+type Option func(*Manager)
+
+// WithRevParser sets the RevParser used to record plugin revisions.
+func WithRevParser(rp git.RevParser) Option {
+	return func(m *Manager) { m.revParser = rp }
+}
+
+// WithLogger sets the Logger used to count the commits an update pulled in.
+func WithLogger(l git.Logger) Option {
+	return func(m *Manager) { m.logger = l }
+}
+
+// WithCheckouter sets the Checkouter used to move plugins to locked commits.
+func WithCheckouter(c git.Checkouter) Option {
+	return func(m *Manager) { m.checkouter = c }
+}
+
+// WithWorktreeChecker sets the checker used to detect modified plugin checkouts.
+func WithWorktreeChecker(w git.WorktreeChecker) Option {
+	return func(m *Manager) { m.worktree = w }
+}
+
+// WithTagLister sets the TagLister used to resolve version pins.
+func WithTagLister(t git.TagLister) Option {
+	return func(m *Manager) { m.tags = t }
+}
+
+// WithLockPath enables the lockfile at the given path. Locking also
+// requires a RevParser to record commits.
+func WithLockPath(path string) Option {
+	return func(m *Manager) { m.lockPath = path }
 }
 
-func New(pluginPath string, cloner git.Cloner, puller git.Puller, validator git.Validator, output ui.Output) *Manager {
-	return &Manager{
+// WithStatePath enables the operation history, the per-plugin revision
+// history used by Rollback, and the logs of plugin scripts' stderr.
+func WithStatePath(path string) Option {
+	return func(m *Manager) { m.statePath = path }
+}
+
+// WithRunner sets the tmux runner Source sets plugin options through.
+func WithRunner(r tmux.Runner) Option {
+	return func(m *Manager) { m.runner = r }
+}
+
+// WithParallelSource makes Source run plugins that don't depend on each
+// other concurrently.
+func WithParallelSource(on bool) Option {
+	return func(m *Manager) { m.parallelSource = on }
+}
+
+// WithSourceTimeout kills plugin scripts that run longer than d, along with
+// the processes they started. Zero means no limit.
+func WithSourceTimeout(d time.Duration) Option {
+	return func(m *Manager) { m.sourceTimeout = d }
+}
+
+// WithSourceEnv limits the environment of plugin scripts to the variables
+// named in allow, which may be glob patterns, plus the few every script
+// needs. A nil allow passes the whole environment.
+func WithSourceEnv(allow []string) Option {
+	return func(m *Manager) {
+		if allow != nil {
+			m.scriptEnv = filterEnv(os.Environ(), allow)
+		}
+	}
+}
+
+// WithProfile makes Source record the outcome of every plugin script in r.
+func WithProfile(r *profile.Run) Option {
+	return func(m *Manager) { m.profile = r }
+}
+
+func New(pluginPath string, cloner git.Cloner, puller git.Puller, validator git.Validator, output ui.Output, opts ...Option) *Manager {
+	m := &Manager{
 		pluginPath: pluginPath,
 		cloner:     cloner,
 		puller:     puller,
 		validator:  validator,
 		output:     output,
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
 func (m *Manager) EnsurePathExists() error {
@@ -50,9 +148,27 @@ func (m *Manager) Install(ctx context.Context, plugins []plug.Plugin) {
 		return
 	}
 	m.verifyPathPermissions()
+	lf := m.loadLock()
 	for _, p := range plugins {
-		m.installPlugin(ctx, p)
+		m.installPlugin(ctx, p, lf)
 	}
+	m.saveLock(lf, plugins)
+}
+
+// Installs the named plugins, leaving the other declared plugins alone.
+func (m *Manager) InstallNamed(ctx context.Context, plugins []plug.Plugin, names []string) {
+	if err := m.EnsurePathExists(); err != nil {
+		m.output.Err("Failed to create plugin directory: " + err.Error())
+		return
+	}
+	m.verifyPathPermissions()
+	lf := m.loadLock()
+	for _, p := range plugins {
+		if slices.Contains(names, p.Name) {
+			m.installPlugin(ctx, p, lf)
+		}
+	}
+	m.saveLock(lf, plugins)
 }
 
 // Updates the named plugins, or all if "all" is passed.
@@ -62,11 +178,13 @@ func (m *Manager) Update(ctx context.Context, plugins []plug.Plugin, names []str
 		m.output.Err("Failed to create plugin directory: " + err.Error())
 		return
 	}
+	lf := m.loadLock()
 	if len(names) == 1 && names[0] == "all" {
-		m.updateAll(ctx, plugins)
-		return
+		m.updateAll(ctx, plugins, lf)
+	} else {
+		m.updateSpecific(ctx, plugins, names, lf)
 	}
-	m.updateSpecific(ctx, plugins, names)
+	m.saveLock(lf, plugins)
 }
 
 // Removes plugin directories not in the list.
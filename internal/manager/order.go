@@ -0,0 +1,129 @@
+package manager
+
+import (
+	"strings"
+
+	"github.com/tmuxpack/tpack/internal/plug"
+)
+
+// dependencies returns, for each plugin, the indexes of the plugins it is
+// declared after=. Names that aren't declared are ignored, so a dependency
+// on a plugin limited to other hosts doesn't hold anything up.
+func dependencies(plugins []plug.Plugin) [][]int {
+	byName := make(map[string][]int, len(plugins))
+	for i, p := range plugins {
+		byName[p.Name] = append(byName[p.Name], i)
+	}
+	deps := make([][]int, len(plugins))
+	for i, p := range plugins {
+		for _, name := range p.After {
+			deps[i] = append(deps[i], byName[name]...)
+		}
+	}
+	return deps
+}
+
+// sourceOrder returns the indexes of the plugins in declared order, except
+// that each plugin is moved after the plugins it depends on. Plugins in or
+// behind a dependency cycle can't be ordered and are returned in stuck,
+// in declared order.
+func sourceOrder(deps [][]int) (order, stuck []int) {
+	done := make([]bool, len(deps))
+	ready := func(i int) bool {
+		for _, d := range deps[i] {
+			if !done[d] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for len(order) < len(deps) {
+		next := -1
+		for i := range deps {
+			if !done[i] && ready(i) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			break
+		}
+		done[next] = true
+		order = append(order, next)
+	}
+	for i := range deps {
+		if !done[i] {
+			stuck = append(stuck, i)
+		}
+	}
+	return order, stuck
+}
+
+// findCycle returns the names along one dependency cycle among the stuck
+// plugins, starting and ending with the same name. Every stuck plugin waits
+// on another stuck plugin, so following those waits must loop.
+func findCycle(plugins []plug.Plugin, deps [][]int, stuck []int) []string {
+	if len(stuck) == 0 {
+		return nil
+	}
+	isStuck := make(map[int]bool, len(stuck))
+	for _, i := range stuck {
+		isStuck[i] = true
+	}
+
+	seen := make(map[int]int)
+	var path []int
+	for i := stuck[0]; ; {
+		if at, ok := seen[i]; ok {
+			path = append(path[at:], i)
+			break
+		}
+		seen[i] = len(path)
+		path = append(path, i)
+		next := -1
+		for _, d := range deps[i] {
+			if isStuck[d] {
+				next = d
+				break
+			}
+		}
+		if next < 0 {
+			break
+		}
+		i = next
+	}
+
+	names := make([]string, len(path))
+	for k, i := range path {
+		names[k] = plugins[i].Name
+	}
+	return names
+}
+
+// sourceStages groups plugins into stages for parallel sourcing: each
+// plugin is placed in the first stage after those of the plugins it depends
+// on. Stuck plugins follow one per stage, in declared order.
+func sourceStages(plugins []plug.Plugin, deps [][]int, order, stuck []int) [][]plug.Plugin {
+	depth := make([]int, len(plugins))
+	var stages [][]plug.Plugin
+	for _, i := range order {
+		for _, d := range deps[i] {
+			depth[i] = max(depth[i], depth[d]+1)
+		}
+		if depth[i] == len(stages) {
+			stages = append(stages, nil)
+		}
+		stages[depth[i]] = append(stages[depth[i]], plugins[i])
+	}
+	for _, i := range stuck {
+		stages = append(stages, []plug.Plugin{plugins[i]})
+	}
+	return stages
+}
+
+// cycleError describes a dependency cycle for the user.
+func cycleError(cycle []string) string {
+	return "Plugin dependency cycle: " + strings.Join(cycle, " -> ") +
+		". Sourcing these plugins in declared order."
+}
@@ -0,0 +1,123 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/manager"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+// sourceLog creates a plugin directory for each plugin whose script
+// appends the plugin's name to a log, and returns the log's path.
+func sourceLog(t *testing.T, pluginDir string, plugins []plug.Plugin) string {
+	t.Helper()
+	log := filepath.Join(t.TempDir(), "sourced")
+	for _, p := range plugins {
+		dir := filepath.Join(pluginDir, p.Name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		script := "#!/bin/sh\necho " + p.Name + " >> " + log + "\n"
+		if err := os.WriteFile(filepath.Join(dir, p.Name+".tmux"), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return log
+}
+
+func readLog(t *testing.T, log string) []string {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Fields(string(data))
+}
+
+func TestSourceOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []string
+		want    string
+		wantErr string
+	}{
+		{"declared order", []string{"u/a", "u/b", "u/c"}, "a b c", ""},
+		{"after a later plugin", []string{"u/status after=theme", "u/a", "u/theme"}, "a theme status", ""},
+		{"after an earlier plugin", []string{"u/theme", "u/status after=theme", "u/a"}, "theme status a", ""},
+		{"several dependencies", []string{"u/c after=a,b", "u/b", "u/a after=b"}, "b a c", ""},
+		{"unknown dependency", []string{"u/a after=missing", "u/b"}, "a b", ""},
+		{"cycle", []string{"u/x", "u/a after=b", "u/b after=a", "u/c after=a"}, "x a b c", "a -> b -> a"},
+		{"self", []string{"u/a after=a", "u/b"}, "b a", "a -> a"},
+	}
+	for _, tt := range tests {
+		for _, parallel := range []bool{false, true} {
+			name := tt.name
+			if parallel {
+				name += " parallel"
+			}
+			t.Run(name, func(t *testing.T) {
+				var plugins []plug.Plugin
+				for _, s := range tt.specs {
+					plugins = append(plugins, plug.ParseSpec(s))
+				}
+				pluginDir := setupTestDir(t)
+				log := sourceLog(t, pluginDir, plugins)
+				output := ui.NewMockOutput()
+				mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output,
+					manager.WithParallelSource(parallel))
+
+				mgr.Source(context.Background(), plugins)
+
+				got := readLog(t, log)
+				if !parallel {
+					if strings.Join(got, " ") != tt.want {
+						t.Errorf("sourced %v, want %s", got, tt.want)
+					}
+				} else {
+					assertDependencyOrder(t, plugins, got)
+				}
+				errs := strings.Join(output.ErrMsgs, "\n")
+				if tt.wantErr == "" && errs != "" {
+					t.Errorf("unexpected errors: %s", errs)
+				}
+				if tt.wantErr != "" && !strings.Contains(errs, tt.wantErr) {
+					t.Errorf("errors = %q, want the cycle %q", errs, tt.wantErr)
+				}
+			})
+		}
+	}
+}
+
+// assertDependencyOrder checks that every plugin was sourced once, after
+// the plugins it depends on unless they depend on it in turn.
+func assertDependencyOrder(t *testing.T, plugins []plug.Plugin, got []string) {
+	t.Helper()
+	if len(got) != len(plugins) {
+		t.Fatalf("sourced %v, want each of %d plugins once", got, len(plugins))
+	}
+	at := make(map[string]int)
+	for i, name := range got {
+		at[name] = i
+	}
+	deps := make(map[string][]string)
+	for _, p := range plugins {
+		deps[p.Name] = p.After
+	}
+	for _, p := range plugins {
+		for _, d := range p.After {
+			dAt, ok := at[d]
+			if !ok || d == p.Name || strings.Contains(" "+strings.Join(deps[d], " ")+" ", " "+p.Name+" ") {
+				continue
+			}
+			if dAt > at[p.Name] {
+				t.Errorf("%s was sourced before its dependency %s: %v", p.Name, d, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/history"
+	"github.com/tmuxpack/tpack/internal/lock"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+// resolvePin resolves p's version pin against its remote, reporting failures.
+func (m *Manager) resolvePin(ctx context.Context, lf *lock.File, p plug.Plugin) (git.Pin, bool) {
+	if m.tags == nil && !git.IsCommitHash(p.Version) {
+		m.output.Err("  \"" + p.Name + "\" version pins are not supported")
+		return git.Pin{}, false
+	}
+	pin, err := git.ResolvePin(ctx, m.tags, m.remoteURL(lf, p), p.Version, plug.NormalizeURL)
+	if err != nil {
+		m.output.Err("  \"" + p.Name + "\" version " + p.Version + " not resolved")
+		m.output.Err(indentOutput(err.Error()))
+		return git.Pin{}, false
+	}
+	return pin, true
+}
+
+// remoteURL returns the URL p was locked from, or its spec when unknown.
+func (m *Manager) remoteURL(lf *lock.File, p plug.Plugin) string {
+	if lf != nil {
+		m.lockMu.Lock()
+		defer m.lockMu.Unlock()
+		if e, ok := lf.Get(p.Name); ok && e.Spec == p.Spec && e.URL != "" {
+			return e.URL
+		}
+	}
+	return p.Spec
+}
+
+// updatePinned moves a plugin pinned to a tag range to the newest tag that
+// still satisfies it. Commit pins never move.
+func (m *Manager) updatePinned(ctx context.Context, p plug.Plugin, lf *lock.File) {
+	pin, ok := m.resolvePin(ctx, lf, p)
+	if !ok {
+		m.record(history.Entry{Action: history.Update, Plugin: p.Name, Error: "version " + p.Version + " not resolved"})
+		return
+	}
+	if pin.Commit != "" {
+		m.output.Ok("  \"" + p.Name + "\" pinned to commit " + git.ShortHash(pin.Commit) + ", skipping")
+		m.report(ui.Result{Name: p.Name, Action: string(history.Update), Status: ui.StatusSkipped})
+		return
+	}
+	if m.checkouter == nil {
+		m.output.Err("  \"" + p.Name + "\" update fail: version pins are not supported")
+		m.record(history.Entry{Action: history.Update, Plugin: p.Name, Error: "version pins are not supported"})
+		return
+	}
+
+	dir := plug.PluginPath(p.Name, m.pluginPath)
+	before, rolledBack, err := git.WithRollback(ctx, m.revParser, m.checkouter, dir, func() error {
+		return m.checkouter.Checkout(ctx, dir, pin.Ref())
+	})
+	if err != nil {
+		m.output.Err("  \"" + p.Name + "\" update fail")
+		m.output.Err(indentOutput(err.Error()))
+		m.reportRollback(p.Name, before, rolledBack, err)
+		m.record(history.Entry{Action: history.Update, Plugin: p.Name, Before: before, Error: err.Error()})
+		return
+	}
+
+	after := m.head(ctx, p.Name)
+	if before != "" && before == after {
+		m.output.Ok("  \"" + p.Name + "\" already at " + pin.Ref())
+	} else {
+		m.output.Ok("  \"" + p.Name + "\" updated to " + pin.Ref())
+	}
+	m.recordUpdate(ctx, p.Name, before, after, m.buildUpdated(ctx, p, dir, before, after))
+	m.recordLock(ctx, lf, p, "")
+}
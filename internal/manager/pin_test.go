@@ -0,0 +1,166 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/lock"
+	"github.com/tmuxpack/tpack/internal/manager"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+const pinnedRemote = "https://git::@github.com/user/repo"
+
+func newPinManager(t *testing.T, opts ...manager.Option) (*manager.Manager, string, *git.MockCloner, *git.MockPuller, *git.MockCheckouter, *git.MockValidator, *ui.MockOutput) {
+	t.Helper()
+	pluginDir := setupTestDir(t)
+	cloner := git.NewMockCloner()
+	puller := git.NewMockPuller()
+	checkouter := git.NewMockCheckouter()
+	validator := git.NewMockValidator()
+	output := ui.NewMockOutput()
+
+	tags := git.NewMockTagLister()
+	tags.Tags[pinnedRemote] = []string{"v1.0.0", "v2.0.0", "v2.1.0", "v3.0.0", "1234567"}
+
+	opts = append([]manager.Option{
+		manager.WithTagLister(tags),
+		manager.WithCheckouter(checkouter),
+		manager.WithRevParser(git.NewMockRevParser()),
+	}, opts...)
+	mgr := manager.New(pluginDir, cloner, puller, validator, output, opts...)
+	return mgr, pluginDir, cloner, puller, checkouter, validator, output
+}
+
+func TestInstallResolvesTagRange(t *testing.T) {
+	mgr, _, cloner, _, checkouter, _, output := newPinManager(t)
+
+	mgr.Install(context.Background(), []plug.Plugin{plug.ParseSpec("user/repo@^2")})
+
+	if output.HasFailed() {
+		t.Fatalf("unexpected errors: %v", output.ErrMsgs)
+	}
+	if len(cloner.Calls) == 0 || cloner.Calls[0].Branch != "v2.1.0" {
+		t.Fatalf("expected clone of newest matching tag v2.1.0, got %+v", cloner.Calls)
+	}
+	if len(checkouter.Calls) != 0 {
+		t.Errorf("tag pins should not need a checkout, got %+v", checkouter.Calls)
+	}
+}
+
+func TestInstallChecksOutCommitPin(t *testing.T) {
+	mgr, pluginDir, cloner, _, checkouter, _, output := newPinManager(t)
+
+	mgr.Install(context.Background(), []plug.Plugin{plug.ParseSpec("user/repo@3f2a9c1")})
+
+	if output.HasFailed() {
+		t.Fatalf("unexpected errors: %v", output.ErrMsgs)
+	}
+	if len(cloner.Calls) == 0 || cloner.Calls[0].Branch != "" {
+		t.Fatalf("expected default branch clone, got %+v", cloner.Calls)
+	}
+	want := git.MockCheckoutCall{Dir: filepath.Join(pluginDir, "repo"), Ref: "3f2a9c1"}
+	if len(checkouter.Calls) != 1 || checkouter.Calls[0] != want {
+		t.Errorf("expected checkout %+v, got %+v", want, checkouter.Calls)
+	}
+}
+
+func TestInstallAllHexTagPin(t *testing.T) {
+	mgr, _, cloner, _, checkouter, _, output := newPinManager(t)
+
+	mgr.Install(context.Background(), []plug.Plugin{plug.ParseSpec("user/repo@1234567")})
+
+	if output.HasFailed() {
+		t.Fatalf("unexpected errors: %v", output.ErrMsgs)
+	}
+	if len(cloner.Calls) == 0 || cloner.Calls[0].Branch != "1234567" {
+		t.Fatalf("expected clone of tag 1234567, got %+v", cloner.Calls)
+	}
+	if len(checkouter.Calls) != 0 {
+		t.Errorf("a tag that looks like a hash should not be checked out as a commit, got %+v", checkouter.Calls)
+	}
+}
+
+func TestInstallUnresolvablePin(t *testing.T) {
+	mgr, _, cloner, _, _, _, output := newPinManager(t)
+
+	mgr.Install(context.Background(), []plug.Plugin{plug.ParseSpec("user/repo@^9")})
+
+	if !output.HasFailed() {
+		t.Error("expected failure for a range with no matching tag")
+	}
+	if len(cloner.Calls) != 0 {
+		t.Errorf("should not clone when the pin cannot be resolved, got %+v", cloner.Calls)
+	}
+}
+
+func TestInstallPinWithoutTagLister(t *testing.T) {
+	output := ui.NewMockOutput()
+	cloner := git.NewMockCloner()
+	mgr := manager.New(setupTestDir(t), cloner, git.NewMockPuller(), git.NewMockValidator(), output)
+
+	mgr.Install(context.Background(), []plug.Plugin{plug.ParseSpec("user/repo@^2")})
+
+	if !output.HasFailed() {
+		t.Error("expected failure when version pins cannot be resolved")
+	}
+	if len(cloner.Calls) != 0 {
+		t.Error("should not clone an unresolved pin")
+	}
+}
+
+func TestUpdateMovesToNewestMatchingTag(t *testing.T) {
+	mgr, pluginDir, _, puller, checkouter, validator, output := newPinManager(t)
+	dir := filepath.Join(pluginDir, "repo")
+	os.MkdirAll(dir, 0o755)
+	validator.Valid[dir] = true
+
+	mgr.Update(context.Background(), []plug.Plugin{plug.ParseSpec("user/repo@~2.0")}, []string{"all"})
+
+	if output.HasFailed() {
+		t.Fatalf("unexpected errors: %v", output.ErrMsgs)
+	}
+	if len(puller.Calls) != 0 {
+		t.Errorf("pinned plugins should not be pulled, got %+v", puller.Calls)
+	}
+	want := git.MockCheckoutCall{Dir: dir, Ref: "v2.0.0"}
+	if len(checkouter.Calls) != 1 || checkouter.Calls[0] != want {
+		t.Errorf("expected checkout %+v, got %+v", want, checkouter.Calls)
+	}
+}
+
+func TestUpdateSkipsCommitPin(t *testing.T) {
+	mgr, pluginDir, _, puller, checkouter, validator, output := newPinManager(t)
+	dir := filepath.Join(pluginDir, "repo")
+	os.MkdirAll(dir, 0o755)
+	validator.Valid[dir] = true
+
+	mgr.Update(context.Background(), []plug.Plugin{plug.ParseSpec("user/repo@3f2a9c1")}, []string{"all"})
+
+	if len(puller.Calls) != 0 || len(checkouter.Calls) != 0 {
+		t.Errorf("commit pins should not move, got pulls %+v checkouts %+v", puller.Calls, checkouter.Calls)
+	}
+	if !strings.Contains(strings.Join(output.OkMsgs, "\n"), "pinned to commit") {
+		t.Errorf("expected skip message, got %v", output.OkMsgs)
+	}
+}
+
+func TestPinRecordedInLockfile(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "tpack.lock")
+	mgr, _, _, _, _, _, _ := newPinManager(t, manager.WithLockPath(lockPath))
+
+	mgr.Install(context.Background(), []plug.Plugin{plug.ParseSpec("user/repo@^2")})
+
+	lf, err := lock.Load(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := lf.Get("repo"); !ok || e.Version != "^2" {
+		t.Errorf("expected version recorded in lock entry, got %+v", lf.Plugins)
+	}
+}
@@ -0,0 +1,32 @@
+package manager
+
+import (
+	"context"
+	"os"
+
+	"github.com/tmuxpack/tpack/internal/history"
+	"github.com/tmuxpack/tpack/internal/plug"
+)
+
+// Removes the directory of p, whose declaration was just removed from the
+// config. plugins are the plugins still declared; if one of them shares
+// p's name, the directory is still in use and is kept.
+func (m *Manager) Remove(_ context.Context, plugins []plug.Plugin, p plug.Plugin) {
+	for _, other := range plugins {
+		if other.Name == p.Name {
+			m.output.Ok("\"" + p.Name + "\" is still declared in " + other.Origin() + ", keeping its directory")
+			return
+		}
+	}
+
+	m.output.Ok("Removing \"" + p.Name + "\"")
+	ev := history.Entry{Action: history.Uninstall, Plugin: p.Name}
+	if err := os.RemoveAll(plug.PluginPath(p.Name, m.pluginPath)); err != nil {
+		m.output.Err("  \"" + p.Name + "\" remove fail")
+		ev.Error = err.Error()
+	} else {
+		m.output.Ok("  \"" + p.Name + "\" remove success")
+	}
+	m.record(ev)
+	m.saveLock(m.loadLock(), plugins)
+}
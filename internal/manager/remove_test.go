@@ -0,0 +1,54 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/manager"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+func TestRemove(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	setupInstalledPlugin(t, pluginDir, "tmux-yank")
+	setupInstalledPlugin(t, pluginDir, "tmux-sensible")
+	output := ui.NewMockOutput()
+
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output)
+
+	remaining := []plug.Plugin{{Name: "tmux-sensible"}}
+	mgr.Remove(context.Background(), remaining, plug.Plugin{Name: "tmux-yank"})
+
+	if _, err := os.Stat(filepath.Join(pluginDir, "tmux-yank")); !os.IsNotExist(err) {
+		t.Error("tmux-yank should have been removed")
+	}
+	if _, err := os.Stat(filepath.Join(pluginDir, "tmux-sensible")); err != nil {
+		t.Error("tmux-sensible should still exist")
+	}
+	if !slices.Contains(output.OkMsgs, "  \"tmux-yank\" remove success") {
+		t.Errorf("expected remove success message, got %v", output.OkMsgs)
+	}
+}
+
+func TestRemoveStillDeclared(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	setupInstalledPlugin(t, pluginDir, "tmux-yank")
+	output := ui.NewMockOutput()
+
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output)
+
+	remaining := []plug.Plugin{{Name: "tmux-yank", SourceFile: "/home/user/extra.conf", SourceLine: 2}}
+	mgr.Remove(context.Background(), remaining, plug.Plugin{Name: "tmux-yank"})
+
+	if _, err := os.Stat(filepath.Join(pluginDir, "tmux-yank")); err != nil {
+		t.Error("directory of a still-declared plugin should be kept")
+	}
+	if len(output.OkMsgs) != 1 || output.OkMsgs[0] != `"tmux-yank" is still declared in /home/user/extra.conf:2, keeping its directory` {
+		t.Errorf("unexpected output %v", output.OkMsgs)
+	}
+}
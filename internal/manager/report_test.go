@@ -0,0 +1,95 @@
+package manager_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/manager"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+func TestInstallReportsResults(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	setupInstalledPlugin(t, pluginDir, "tmux-sensible")
+	validator := git.NewMockValidator()
+	validator.Valid[filepath.Join(pluginDir, "tmux-sensible")] = true
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), validator, output,
+		manager.WithRevParser(git.NewMockRevParser()),
+	)
+
+	mgr.Install(context.Background(), []plug.Plugin{
+		{Name: "tmux-sensible", Spec: "tmux-plugins/tmux-sensible"},
+		{Name: "tmux-yank", Spec: "tmux-plugins/tmux-yank"},
+	})
+
+	want := []ui.Result{
+		{Name: "tmux-sensible", Action: "install", Status: ui.StatusUnchanged},
+		{Name: "tmux-yank", Action: "install", Status: ui.StatusOK, After: "abc123"},
+	}
+	assertResults(t, output.Results, want)
+}
+
+func TestUpdateReportsResults(t *testing.T) {
+	puller := git.NewMockPuller()
+	puller.Err = errors.New("exit status 1")
+	puller.Output = "fatal: unable to access remote"
+	mgr, _, _, _, output := newRollbackManager(t, puller, git.NewMockRevParser())
+
+	mgr.Update(context.Background(), []plug.Plugin{{Name: "tmux-yank"}}, []string{"tmux-yank", "missing"})
+
+	want := []ui.Result{
+		{Name: "missing", Action: "update", Status: ui.StatusFailed, Error: "not installed"},
+		{Name: "tmux-yank", Action: "update", Status: ui.StatusFailed, Before: "abc123", Error: "fatal: unable to access remote"},
+	}
+	assertResults(t, output.Results, want)
+}
+
+func TestUpdateReportsUnchanged(t *testing.T) {
+	mgr, _, _, _, output := newRollbackManager(t, git.NewMockPuller(), git.NewMockRevParser())
+
+	mgr.Update(context.Background(), []plug.Plugin{{Name: "tmux-yank"}}, []string{"tmux-yank"})
+
+	want := []ui.Result{
+		{Name: "tmux-yank", Action: "update", Status: ui.StatusUnchanged, Before: "abc123", After: "abc123"},
+	}
+	assertResults(t, output.Results, want)
+}
+
+func TestSourceReportsResults(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	good := filepath.Join(pluginDir, "good")
+	bad := filepath.Join(pluginDir, "bad")
+	os.MkdirAll(good, 0o755)
+	os.MkdirAll(bad, 0o755)
+	os.WriteFile(filepath.Join(good, "good.tmux"), []byte("#!/bin/sh\nexit 0\n"), 0o755)
+	os.WriteFile(filepath.Join(bad, "bad.tmux"), []byte("#!/bin/sh\nexit 3\n"), 0o755)
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output)
+
+	mgr.Source(context.Background(), []plug.Plugin{{Name: "good"}, {Name: "bad"}, {Name: "absent"}})
+
+	want := []ui.Result{
+		{Name: "good", Action: "source", Status: ui.StatusOK},
+		{Name: "bad", Action: "source", Status: ui.StatusFailed, Error: "bad.tmux: exit status 3"},
+		{Name: "absent", Action: "source", Status: ui.StatusSkipped},
+	}
+	assertResults(t, output.Results, want)
+}
+
+func assertResults(t *testing.T, got, want []ui.Result) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d results %+v, want %+v", len(got), got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/history"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/state"
+)
+
+// Rollback returns the named plugin to the revision it was at before its
+// most recent update. Each rollback consumes one entry of the plugin's
+// revision history, so repeated rollbacks step further back.
+func (m *Manager) Rollback(ctx context.Context, plugins []plug.Plugin, name string) {
+	if m.statePath == "" || m.checkouter == nil {
+		m.output.Err("Rollback is not configured")
+		return
+	}
+
+	name = plug.PluginName(name)
+	p := plug.Plugin{Name: name}
+	for _, candidate := range plugins {
+		if candidate.Name == name {
+			p = candidate
+			break
+		}
+	}
+	if p.IsLocal() {
+		m.output.Err("  \"" + name + "\" is a local plugin, nothing to roll back")
+		return
+	}
+	if !m.IsPluginInstalled(name) {
+		m.output.Err(name + " not installed!")
+		return
+	}
+
+	rev, ok := state.Load(m.statePath).LastRevision(name)
+	if !ok {
+		m.output.Err("  \"" + name + "\" has no previous revision to roll back to")
+		return
+	}
+
+	short := git.ShortHash(rev)
+	m.output.Ok("Rolling back \"" + name + "\" to " + short)
+	dir := plug.PluginPath(name, m.pluginPath)
+	ev := history.Entry{Action: history.Rollback, Plugin: name, Before: m.head(ctx, name), After: rev}
+	if err := m.checkouter.Checkout(ctx, dir, rev); err != nil {
+		m.output.Err("  \"" + name + "\" rollback fail")
+		m.output.Err(indentOutput(err.Error()))
+		ev.Error = err.Error()
+		m.record(ev)
+		return
+	}
+	m.record(ev)
+
+	err := state.LoadAndSave(m.statePath, func(s *state.State) {
+		if last, ok := s.LastRevision(name); ok && last == rev {
+			s.PopRevision(name)
+		}
+	})
+	if err != nil {
+		m.output.Err("  \"" + name + "\" failed to update revision history: " + err.Error())
+	}
+	m.output.Ok("  \"" + name + "\" rolled back to " + short)
+
+	lf := m.loadLock()
+	m.recordLock(ctx, lf, p, "")
+	m.saveLock(lf, plugins)
+}
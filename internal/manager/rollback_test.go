@@ -0,0 +1,182 @@
+package manager_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/manager"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/state"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+// headSequence returns each hash in turn, repeating the last one.
+type headSequence struct {
+	mu     sync.Mutex
+	hashes []string
+}
+
+func (h *headSequence) RevParse(_ context.Context, _ string) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hash := h.hashes[0]
+	if len(h.hashes) > 1 {
+		h.hashes = h.hashes[1:]
+	}
+	return hash, nil
+}
+
+func newRollbackManager(t *testing.T, puller *git.MockPuller, rp git.RevParser, opts ...manager.Option) (*manager.Manager, string, string, *git.MockCheckouter, *ui.MockOutput) {
+	t.Helper()
+	pluginDir := setupTestDir(t)
+	setupInstalledPlugin(t, pluginDir, "tmux-yank")
+	validator := git.NewMockValidator()
+	validator.Valid[filepath.Join(pluginDir, "tmux-yank")] = true
+	checkouter := git.NewMockCheckouter()
+	output := ui.NewMockOutput()
+	statePath := filepath.Join(t.TempDir(), "state")
+
+	opts = append([]manager.Option{
+		manager.WithRevParser(rp),
+		manager.WithCheckouter(checkouter),
+		manager.WithStatePath(statePath),
+	}, opts...)
+	mgr := manager.New(pluginDir, git.NewMockCloner(), puller, validator, output, opts...)
+	return mgr, pluginDir, statePath, checkouter, output
+}
+
+func hasMsg(msgs []string, substr string) bool {
+	for _, m := range msgs {
+		if strings.Contains(m, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestUpdateFailureRollsBack(t *testing.T) {
+	puller := git.NewMockPuller()
+	puller.Err = errors.New("submodule update failed")
+	mgr, pluginDir, statePath, checkouter, output := newRollbackManager(t, puller, git.NewMockRevParser())
+
+	mgr.Update(context.Background(), []plug.Plugin{{Name: "tmux-yank"}}, []string{"tmux-yank"})
+
+	want := git.MockCheckoutCall{Dir: filepath.Join(pluginDir, "tmux-yank"), Ref: "abc123"}
+	if len(checkouter.Calls) != 1 || checkouter.Calls[0] != want {
+		t.Fatalf("expected rollback checkout %+v, got %+v", want, checkouter.Calls)
+	}
+	if !hasMsg(output.ErrMsgs, `"tmux-yank" rolled back to abc123`) {
+		t.Errorf("expected rollback message, got %v", output.ErrMsgs)
+	}
+	if _, ok := state.Load(statePath).LastRevision("tmux-yank"); ok {
+		t.Error("failed update must not be recorded in the history")
+	}
+}
+
+func TestUpdateFailureRollbackFails(t *testing.T) {
+	puller := git.NewMockPuller()
+	puller.Err = errors.New("pull failed")
+	mgr, _, _, checkouter, output := newRollbackManager(t, puller, git.NewMockRevParser())
+	checkouter.Err = errors.New("reset failed")
+
+	mgr.Update(context.Background(), []plug.Plugin{{Name: "tmux-yank"}}, []string{"tmux-yank"})
+
+	if !hasMsg(output.ErrMsgs, "could not be rolled back") {
+		t.Errorf("expected rollback failure message, got %v", output.ErrMsgs)
+	}
+	if !hasMsg(output.ErrMsgs, "reset failed") {
+		t.Errorf("expected rollback error in output, got %v", output.ErrMsgs)
+	}
+}
+
+func TestUpdateRecordsRevisionHistory(t *testing.T) {
+	rp := &headSequence{hashes: []string{"aaa111", "bbb222"}}
+	mgr, _, statePath, _, output := newRollbackManager(t, git.NewMockPuller(), rp)
+
+	mgr.Update(context.Background(), []plug.Plugin{{Name: "tmux-yank"}}, []string{"tmux-yank"})
+
+	if output.HasFailed() {
+		t.Fatalf("unexpected errors: %v", output.ErrMsgs)
+	}
+	if rev, ok := state.Load(statePath).LastRevision("tmux-yank"); !ok || rev != "aaa111" {
+		t.Errorf("LastRevision = %q, %v, want aaa111, true", rev, ok)
+	}
+}
+
+func TestUpdateUnchangedSkipsHistory(t *testing.T) {
+	mgr, _, statePath, _, _ := newRollbackManager(t, git.NewMockPuller(), git.NewMockRevParser())
+
+	mgr.Update(context.Background(), []plug.Plugin{{Name: "tmux-yank"}}, []string{"tmux-yank"})
+
+	if _, ok := state.Load(statePath).LastRevision("tmux-yank"); ok {
+		t.Error("an update that did not move HEAD must not be recorded")
+	}
+}
+
+func TestRollback(t *testing.T) {
+	mgr, pluginDir, statePath, checkouter, output := newRollbackManager(t, git.NewMockPuller(), git.NewMockRevParser())
+	if err := state.RecordRevision(statePath, "tmux-yank", "aaa111"); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.RecordRevision(statePath, "tmux-yank", "bbb222"); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr.Rollback(context.Background(), []plug.Plugin{{Name: "tmux-yank"}}, "tmux-yank")
+
+	if output.HasFailed() {
+		t.Fatalf("unexpected errors: %v", output.ErrMsgs)
+	}
+	want := git.MockCheckoutCall{Dir: filepath.Join(pluginDir, "tmux-yank"), Ref: "bbb222"}
+	if len(checkouter.Calls) != 1 || checkouter.Calls[0] != want {
+		t.Fatalf("expected checkout %+v, got %+v", want, checkouter.Calls)
+	}
+	if rev, _ := state.Load(statePath).LastRevision("tmux-yank"); rev != "aaa111" {
+		t.Errorf("expected history to step back to aaa111, got %q", rev)
+	}
+}
+
+func TestRollbackFailureKeepsHistory(t *testing.T) {
+	mgr, _, statePath, checkouter, output := newRollbackManager(t, git.NewMockPuller(), git.NewMockRevParser())
+	checkouter.Err = errors.New("reset failed")
+	if err := state.RecordRevision(statePath, "tmux-yank", "aaa111"); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr.Rollback(context.Background(), []plug.Plugin{{Name: "tmux-yank"}}, "tmux-yank")
+
+	if !hasMsg(output.ErrMsgs, "rollback fail") {
+		t.Errorf("expected failure message, got %v", output.ErrMsgs)
+	}
+	if rev, _ := state.Load(statePath).LastRevision("tmux-yank"); rev != "aaa111" {
+		t.Errorf("failed rollback must keep history, got %q", rev)
+	}
+}
+
+func TestRollbackWithoutHistory(t *testing.T) {
+	mgr, _, _, checkouter, output := newRollbackManager(t, git.NewMockPuller(), git.NewMockRevParser())
+
+	mgr.Rollback(context.Background(), []plug.Plugin{{Name: "tmux-yank"}}, "tmux-yank")
+
+	if !hasMsg(output.ErrMsgs, "no previous revision") {
+		t.Errorf("expected no-history message, got %v", output.ErrMsgs)
+	}
+	if len(checkouter.Calls) != 0 {
+		t.Errorf("expected no checkout, got %+v", checkouter.Calls)
+	}
+}
+
+func TestRollbackNotInstalled(t *testing.T) {
+	mgr, _, _, _, output := newRollbackManager(t, git.NewMockPuller(), git.NewMockRevParser())
+
+	mgr.Rollback(context.Background(), nil, "tmux-sensible")
+
+	if !hasMsg(output.ErrMsgs, "tmux-sensible not installed!") {
+		t.Errorf("expected not-installed message, got %v", output.ErrMsgs)
+	}
+}
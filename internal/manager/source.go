@@ -2,61 +2,291 @@ package manager
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/tmuxpack/tpack/internal/parallel"
 	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/profile"
+	"github.com/tmuxpack/tpack/internal/shell"
+	"github.com/tmuxpack/tpack/internal/sourcelog"
+	"github.com/tmuxpack/tpack/internal/state"
+	"github.com/tmuxpack/tpack/internal/ui"
 )
 
+const maxConcurrentSources = 8
+
 // Source executes all *.tmux files from each plugin directory.
+// Local plugins are sourced in place from their working tree.
+// With a runner, each plugin's options are set just before its files run.
+// With a state path, what the files write to stderr is kept in a log per
+// plugin. Files running longer than the source timeout are killed.
+// Disabled plugins, including those turned off in the state directory with
+// `tpack disable`, are skipped. Plugins loaded on demand, declared with
+// on-key= or on-hook=, are left for Load.
+//
+// Plugins are sourced in declared order, except that a plugin declared
+// after= another comes after it. With parallel sourcing, plugins that don't
+// depend on each other are sourced concurrently.
 func (m *Manager) Source(ctx context.Context, plugins []plug.Plugin) {
+	eager := make([]plug.Plugin, 0, len(plugins))
 	for _, p := range plugins {
+		if !p.IsLazy() {
+			eager = append(eager, p)
+		}
+	}
+	m.Load(ctx, eager)
+}
+
+// Load sources plugins as Source does, including plugins loaded on demand.
+// It is run for a lazy plugin when its key is first pressed or its hook
+// first runs.
+func (m *Manager) Load(ctx context.Context, plugins []plug.Plugin) {
+	plugins = m.enabled(plugins)
+	deps := dependencies(plugins)
+	order, stuck := sourceOrder(deps)
+	if len(stuck) > 0 {
+		m.output.Err(cycleError(findCycle(plugins, deps, stuck)))
+	}
+
+	source := func(p plug.Plugin) {
 		dir := plug.PluginPath(p.Name, m.pluginPath)
-		m.sourcePlugin(ctx, dir)
+		if p.IsLocal() {
+			dir = p.LocalPath
+		}
+		m.report(m.sourcePlugin(ctx, p, dir))
+	}
+
+	if m.profile != nil {
+		m.profile.Parallel = m.parallelSource
+	}
+	if !m.parallelSource {
+		for _, i := range append(order, stuck...) {
+			source(plugins[i])
+		}
+		return
+	}
+	for _, stage := range sourceStages(plugins, deps, order, stuck) {
+		parallel.Do(stage, maxConcurrentSources, source)
+	}
+}
+
+// enabled returns the plugins that aren't disabled, reporting the others as
+// skipped.
+func (m *Manager) enabled(plugins []plug.Plugin) []plug.Plugin {
+	var st state.State
+	if m.statePath != "" {
+		st = state.Load(m.statePath)
 	}
+	enabled := make([]plug.Plugin, 0, len(plugins))
+	for _, p := range plugins {
+		if p.Disabled || st.IsDisabled(p.Name) {
+			m.report(ui.Result{Name: p.Name, Action: "source", Status: ui.StatusSkipped})
+			continue
+		}
+		enabled = append(enabled, p)
+	}
+	return enabled
 }
 
-func (m *Manager) sourcePlugin(ctx context.Context, dir string) {
+// sourcePlugin runs every *.tmux file in dir and returns the outcome.
+func (m *Manager) sourcePlugin(ctx context.Context, p plug.Plugin, dir string) ui.Result {
+	res := ui.Result{Name: p.Name, Action: "source", Status: ui.StatusOK}
 	info, err := os.Stat(dir)
 	if err != nil || !info.IsDir() {
-		return
+		res.Status = ui.StatusSkipped
+		return res
+	}
+
+	var failures []string
+
+	if m.runner != nil {
+		for _, o := range p.Options {
+			if err := m.runner.SetOption(o.Name, o.Value); err != nil {
+				m.output.Err("error setting " + o.Name + " for " + p.Name + ": " + err.Error())
+				failures = append(failures, o.Name+": "+err.Error())
+			}
+		}
 	}
 
 	matches, err := filepath.Glob(filepath.Join(dir, "*.tmux"))
 	if err != nil {
 		m.output.Err("glob error for " + dir + ": " + err.Error())
-		return
+		res.Status, res.Error = ui.StatusFailed, err.Error()
+		return res
 	}
 
+	start := time.Now()
+	scripts := make([]profile.Script, 0, len(matches))
 	for _, file := range matches {
-		cmd := exec.CommandContext(ctx, file) //nolint:gosec // plugin files are user-configured
-		cmd.Stdout = io.Discard
-		cmd.Stderr = io.Discard
-		if err := cmd.Run(); err != nil {
-			// Retry with the interpreter from the shebang looked up via
-			// PATH if the absolute interpreter path was not found (e.g.
-			// Termux where /usr/bin/env does not exist).
-			if errors.Is(err, syscall.ENOENT) {
-				if interp := parseShebangInterpreter(file); interp != "" {
-					fallback := exec.CommandContext(ctx, interp, file) //nolint:gosec // plugin files are user-configured
-					fallback.Stdout = io.Discard
-					fallback.Stderr = io.Discard
-					err = fallback.Run()
-				}
-			}
-			if err != nil {
-				m.output.Err("error sourcing " + filepath.Base(file) + ": " + err.Error())
+		s, err := m.runScript(ctx, file)
+		s.Plugin = p.Name
+		m.recordScript(s)
+		scripts = append(scripts, s)
+		if err != nil {
+			msg := scriptError(s, err)
+			m.output.Err("error sourcing " + s.File + ": " + msg)
+			failures = append(failures, s.File+": "+msg)
+		}
+	}
+	m.writeSourceLog(p.Name, start, scripts)
+	if len(failures) > 0 {
+		res.Status, res.Error = ui.StatusFailed, strings.Join(failures, "; ")
+	}
+	return res
+}
+
+// maxStderr bounds how much of a script's stderr is kept.
+const maxStderr = 16 << 10
+
+// ErrScriptTimeout is reported for plugin scripts killed for running longer
+// than the source timeout.
+var ErrScriptTimeout = errors.New("timed out")
+
+// scriptWaitDelay is how long a script that has exited, or been killed, is
+// given to close its output. Scripts often leave a background process
+// holding stderr open, which must not hold up sourcing.
+const scriptWaitDelay = 500 * time.Millisecond
+
+// runScript runs a plugin script, discarding its output except for the
+// start of stderr, and returns how long it took and how it exited. A script
+// still running after the source timeout is killed along with every
+// process it started.
+func (m *Manager) runScript(ctx context.Context, file string) (profile.Script, error) {
+	scriptCtx := ctx
+	if m.sourceTimeout > 0 {
+		var cancel context.CancelFunc
+		scriptCtx, cancel = context.WithTimeout(ctx, m.sourceTimeout)
+		defer cancel()
+	}
+	s := profile.Script{File: filepath.Base(file)}
+	stderr := &limitedBuffer{limit: maxStderr}
+	start := time.Now()
+
+	err := m.scriptCommand(scriptCtx, stderr, file).Run()
+	// Retry with the interpreter from the shebang looked up via PATH if the
+	// absolute interpreter path was not found (e.g. Termux where
+	// /usr/bin/env does not exist).
+	if errors.Is(err, syscall.ENOENT) {
+		if interp := parseShebangInterpreter(file); interp != "" {
+			err = m.scriptCommand(scriptCtx, stderr, interp, file).Run()
+		}
+	}
+	// Only the script's own timeout is reported as one; a script killed
+	// because the caller gave up fails with the caller's error.
+	switch {
+	case err != nil && ctx.Err() != nil:
+		err = ctx.Err()
+	case m.sourceTimeout > 0 && errors.Is(scriptCtx.Err(), context.DeadlineExceeded):
+		err = fmt.Errorf("%w after %s", ErrScriptTimeout, m.sourceTimeout)
+		s.TimedOut = true
+	case errors.Is(err, exec.ErrWaitDelay):
+		// The script exited successfully but left stderr open.
+		err = nil
+	}
+
+	s.Duration = time.Since(start)
+	s.Stderr = stderr.String()
+	if err != nil {
+		s.ExitCode, s.Error = -1, err.Error()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			s.ExitCode = exitErr.ExitCode()
+		}
+	}
+	return s, err
+}
+
+// scriptCommand returns the command running a plugin script in its own
+// process group, so that canceling it kills everything the script started.
+func (m *Manager) scriptCommand(ctx context.Context, stderr io.Writer, name string, args ...string) *exec.Cmd {
+	cmd := shell.Command(ctx, scriptWaitDelay, name, args...)
+	cmd.Stdout = io.Discard
+	cmd.Stderr = stderr
+	cmd.Env = m.scriptEnv
+	return cmd
+}
+
+// alwaysPassedEnv lists the variables plugin scripts get even with an
+// environment allowlist: without them scripts can't find their tools, the
+// tmux server, or the plugin directory.
+var alwaysPassedEnv = []string{"PATH", "HOME", "TMUX", "TMUX_PANE", "TPACK_PLUGIN_PATH", "TMUX_PLUGIN_MANAGER_PATH"}
+
+// filterEnv returns the entries of environ whose names are in allow or
+// alwaysPassedEnv. Names in allow may be glob patterns such as "LC_*".
+func filterEnv(environ, allow []string) []string {
+	patterns := append(slices.Clone(alwaysPassedEnv), allow...)
+	filtered := []string{}
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				filtered = append(filtered, kv)
+				break
 			}
 		}
 	}
+	return filtered
+}
+
+// scriptError describes a failed script run, adding the first line the
+// script wrote to stderr, which usually says why it failed.
+func scriptError(s profile.Script, err error) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s.Stderr), "\n")
+	if line == "" {
+		return err.Error()
+	}
+	return err.Error() + ": " + line
 }
 
+// writeSourceLog replaces the source log of the named plugin, when a state
+// path is configured.
+func (m *Manager) writeSourceLog(name string, at time.Time, scripts []profile.Script) {
+	if m.statePath == "" {
+		return
+	}
+	if err := sourcelog.Write(m.statePath, name, at, scripts); err != nil {
+		m.output.Err("Failed to write source log for " + name + ": " + err.Error())
+	}
+}
+
+// recordScript adds s to the profile, if one is being recorded.
+func (m *Manager) recordScript(s profile.Script) {
+	if m.profile == nil {
+		return
+	}
+	m.profileMu.Lock()
+	defer m.profileMu.Unlock()
+	m.profile.Scripts = append(m.profile.Scripts, s)
+}
+
+// limitedBuffer keeps the first limit bytes written to it and drops the
+// rest, so a noisy script can't use unbounded memory.
+type limitedBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string { return b.buf.String() }
+
 // parseShebangInterpreter reads the shebang line from a script and returns
 // the interpreter base name (e.g. "bash" from "#!/usr/bin/env bash" or
 // "#!/bin/bash"). Returns "" if no shebang is found.
@@ -2,13 +2,21 @@ package manager_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/tmuxpack/tpack/internal/git"
 	"github.com/tmuxpack/tpack/internal/manager"
 	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/profile"
+	"github.com/tmuxpack/tpack/internal/sourcelog"
+	"github.com/tmuxpack/tpack/internal/state"
+	"github.com/tmuxpack/tpack/internal/tmux"
 	"github.com/tmuxpack/tpack/internal/ui"
 )
 
@@ -97,3 +105,272 @@ func TestSourceSkipsNonExistentDir(t *testing.T) {
 	// Should not panic.
 	mgr.Source(context.Background(), plugins)
 }
+
+// markerRunner records, for each option set, whether the plugin's script
+// had already run.
+type markerRunner struct {
+	*tmux.MockRunner
+	marker string
+	set    []string
+}
+
+func (r *markerRunner) SetOption(option, value string) error {
+	_, err := os.Stat(r.marker)
+	r.set = append(r.set, fmt.Sprintf("%s=%s sourced=%t", option, value, err == nil))
+	return r.MockRunner.SetOption(option, value)
+}
+
+func TestSourceSetsOptionsBeforeScripts(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	pDir := filepath.Join(pluginDir, "tmux-test")
+	os.MkdirAll(pDir, 0o755)
+	marker := filepath.Join(t.TempDir(), "sourced")
+	os.WriteFile(filepath.Join(pDir, "test.tmux"), []byte("#!/bin/sh\ntouch "+marker+"\n"), 0o755)
+
+	runner := &markerRunner{MockRunner: tmux.NewMockRunner(), marker: marker}
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output,
+		manager.WithRunner(runner))
+
+	mgr.Source(context.Background(), []plug.Plugin{
+		{Name: "tmux-test", Options: []plug.Option{{Name: "@test-a", Value: "1"}, {Name: "@test-b", Value: "on"}}},
+		{Name: "missing", Options: []plug.Option{{Name: "@missing", Value: "x"}}},
+	})
+
+	want := []string{"@test-a=1 sourced=false", "@test-b=on sourced=false"}
+	if strings.Join(runner.set, "; ") != strings.Join(want, "; ") {
+		t.Errorf("SetOption calls = %v, want %v", runner.set, want)
+	}
+	if output.HasFailed() {
+		t.Errorf("expected no errors, got: %v", output.ErrMsgs)
+	}
+}
+
+func TestSourceReportsOptionErrors(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	pDir := filepath.Join(pluginDir, "tmux-test")
+	os.MkdirAll(pDir, 0o755)
+	marker := filepath.Join(t.TempDir(), "sourced")
+	os.WriteFile(filepath.Join(pDir, "test.tmux"), []byte("#!/bin/sh\ntouch "+marker+"\n"), 0o755)
+
+	runner := tmux.NewMockRunner()
+	runner.Errors["SetOption:@bad"] = errors.New("invalid option")
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output,
+		manager.WithRunner(runner))
+
+	mgr.Source(context.Background(), []plug.Plugin{
+		{Name: "tmux-test", Options: []plug.Option{{Name: "@bad", Value: "1"}}},
+	})
+
+	if !output.HasFailed() {
+		t.Error("expected the option error to be reported")
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("expected the plugin to be sourced despite the option error")
+	}
+}
+
+func TestSourceLogsScriptStderr(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	stateDir := t.TempDir()
+	pDir := filepath.Join(pluginDir, "tmux-test")
+	os.MkdirAll(pDir, 0o755)
+	os.WriteFile(filepath.Join(pDir, "test.tmux"), []byte("#!/bin/sh\necho 'xclip: not found' >&2\nexit 3\n"), 0o755)
+
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output,
+		manager.WithStatePath(stateDir))
+
+	mgr.Source(context.Background(), []plug.Plugin{{Name: "tmux-test"}})
+
+	if len(output.Results) != 1 || output.Results[0].Status != ui.StatusFailed ||
+		!strings.Contains(output.Results[0].Error, "exit status 3: xclip: not found") {
+		t.Errorf("expected a failed result with the script's stderr, got %+v", output.Results)
+	}
+	log, err := sourcelog.Read(stateDir, "tmux-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(log, "==> test.tmux (exit 3,") || !strings.Contains(log, "xclip: not found") {
+		t.Errorf("unexpected source log:\n%s", log)
+	}
+
+	// A clean run clears the log.
+	os.WriteFile(filepath.Join(pDir, "test.tmux"), []byte("#!/bin/sh\nexit 0\n"), 0o755)
+	mgr.Source(context.Background(), []plug.Plugin{{Name: "tmux-test"}})
+	if log, _ := sourcelog.Read(stateDir, "tmux-test"); log != "" {
+		t.Errorf("expected the log to be cleared, got:\n%s", log)
+	}
+}
+
+func TestSourceTimeoutKillsProcessGroup(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	pDir := filepath.Join(pluginDir, "tmux-slow")
+	os.MkdirAll(pDir, 0o755)
+	marker := filepath.Join(t.TempDir(), "survived")
+	// The background child would touch the marker if it outlived the script.
+	os.WriteFile(filepath.Join(pDir, "slow.tmux"),
+		[]byte("#!/bin/sh\n(sleep 1; touch "+marker+") &\nsleep 10\n"), 0o755)
+
+	output := ui.NewMockOutput()
+	run := &profile.Run{}
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output,
+		manager.WithSourceTimeout(100*time.Millisecond), manager.WithProfile(run))
+
+	start := time.Now()
+	mgr.Source(context.Background(), []plug.Plugin{{Name: "tmux-slow"}})
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("Source took %v, expected the script to be killed", elapsed)
+	}
+
+	if len(output.Results) != 1 || !strings.Contains(output.Results[0].Error, "slow.tmux: timed out after 100ms") {
+		t.Errorf("expected a timed out result, got %+v", output.Results)
+	}
+	if len(run.Scripts) != 1 || !run.Scripts[0].TimedOut {
+		t.Errorf("expected the profile to record the timeout, got %+v", run.Scripts)
+	}
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("expected the script's background child to be killed too")
+	}
+}
+
+func TestSourceCallerDeadlineIsNotATimeout(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	pDir := filepath.Join(pluginDir, "tmux-slow")
+	os.MkdirAll(pDir, 0o755)
+	os.WriteFile(filepath.Join(pDir, "slow.tmux"), []byte("#!/bin/sh\nsleep 10\n"), 0o755)
+
+	output := ui.NewMockOutput()
+	run := &profile.Run{}
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output,
+		manager.WithProfile(run))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	mgr.Source(ctx, []plug.Plugin{{Name: "tmux-slow"}})
+
+	if len(output.Results) != 1 || !strings.Contains(output.Results[0].Error, context.DeadlineExceeded.Error()) {
+		t.Errorf("expected the caller's deadline as the error, got %+v", output.Results)
+	}
+	if len(run.Scripts) != 1 || run.Scripts[0].TimedOut {
+		t.Errorf("expected no script timeout to be recorded, got %+v", run.Scripts)
+	}
+}
+
+func TestSourceDoesNotWaitForBackgroundChildren(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	pDir := filepath.Join(pluginDir, "tmux-daemon")
+	os.MkdirAll(pDir, 0o755)
+	os.WriteFile(filepath.Join(pDir, "daemon.tmux"), []byte("#!/bin/sh\nsleep 5 &\nexit 0\n"), 0o755)
+
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output)
+
+	start := time.Now()
+	mgr.Source(context.Background(), []plug.Plugin{{Name: "tmux-daemon"}})
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Source took %v, expected it not to wait for the background child", elapsed)
+	}
+	if output.HasFailed() {
+		t.Errorf("expected no errors, got %v", output.ErrMsgs)
+	}
+}
+
+func TestSourceEnvAllowlist(t *testing.T) {
+	t.Setenv("TPACK_TEST_ALLOWED", "yes")
+	t.Setenv("TPACK_TEST_SECRET", "hunter2")
+
+	pluginDir := setupTestDir(t)
+	pDir := filepath.Join(pluginDir, "tmux-env")
+	os.MkdirAll(pDir, 0o755)
+	envFile := filepath.Join(t.TempDir(), "env")
+	os.WriteFile(filepath.Join(pDir, "env.tmux"), []byte("#!/bin/sh\nenv > "+envFile+"\n"), 0o755)
+
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), ui.NewMockOutput(),
+		manager.WithSourceEnv([]string{"TPACK_TEST_ALLOW*"}))
+	mgr.Source(context.Background(), []plug.Plugin{{Name: "tmux-env"}})
+
+	data, err := os.ReadFile(envFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := string(data)
+	if !strings.Contains(env, "TPACK_TEST_ALLOWED=yes") || !strings.Contains(env, "PATH=") {
+		t.Errorf("expected allowed variables and PATH, got:\n%s", env)
+	}
+	if strings.Contains(env, "hunter2") {
+		t.Errorf("expected TPACK_TEST_SECRET to be withheld, got:\n%s", env)
+	}
+}
+
+func TestSourceSkipsDisabledPlugins(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	stateDir := t.TempDir()
+	markers := t.TempDir()
+	for _, name := range []string{"tmux-on", "tmux-off", "tmux-paused", "tmux-dependent"} {
+		pDir := filepath.Join(pluginDir, name)
+		os.MkdirAll(pDir, 0o755)
+		os.WriteFile(filepath.Join(pDir, "p.tmux"), []byte("#!/bin/sh\ntouch "+filepath.Join(markers, name)+"\n"), 0o755)
+	}
+	if err := state.SetDisabled(stateDir, true, "tmux-paused"); err != nil {
+		t.Fatal(err)
+	}
+
+	output := ui.NewMockOutput()
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output,
+		manager.WithStatePath(stateDir))
+	mgr.Source(context.Background(), []plug.Plugin{
+		{Name: "tmux-on"},
+		{Name: "tmux-off", Disabled: true},
+		{Name: "tmux-paused"},
+		{Name: "tmux-dependent", After: []string{"tmux-off"}},
+	})
+
+	for name, want := range map[string]bool{"tmux-on": true, "tmux-off": false, "tmux-paused": false, "tmux-dependent": true} {
+		_, err := os.Stat(filepath.Join(markers, name))
+		if sourced := err == nil; sourced != want {
+			t.Errorf("%s sourced = %v, want %v", name, sourced, want)
+		}
+	}
+	skipped := 0
+	for _, r := range output.Results {
+		if r.Status == ui.StatusSkipped {
+			skipped++
+		}
+	}
+	if skipped != 2 {
+		t.Errorf("expected 2 skipped results, got %+v", output.Results)
+	}
+}
+
+func TestSourceDefersLazyPlugins(t *testing.T) {
+	pluginDir := setupTestDir(t)
+	markers := t.TempDir()
+	for _, name := range []string{"tmux-eager", "tmux-keyed", "tmux-hooked"} {
+		pDir := filepath.Join(pluginDir, name)
+		os.MkdirAll(pDir, 0o755)
+		os.WriteFile(filepath.Join(pDir, "p.tmux"), []byte("#!/bin/sh\ntouch "+filepath.Join(markers, name)+"\n"), 0o755)
+	}
+	plugins := []plug.Plugin{
+		{Name: "tmux-eager"},
+		{Name: "tmux-keyed", OnKey: []string{"C-f"}},
+		{Name: "tmux-hooked", OnHook: []string{"session-created"}},
+	}
+	sourced := func(name string) bool {
+		_, err := os.Stat(filepath.Join(markers, name))
+		return err == nil
+	}
+
+	mgr := manager.New(pluginDir, git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), ui.NewMockOutput())
+	mgr.Source(context.Background(), plugins)
+	if !sourced("tmux-eager") || sourced("tmux-keyed") || sourced("tmux-hooked") {
+		t.Fatal("expected Source to source only the eager plugin")
+	}
+
+	mgr.Load(context.Background(), plugins[1:2])
+	if !sourced("tmux-keyed") || sourced("tmux-hooked") {
+		t.Error("expected Load to source the lazy plugin it was given")
+	}
+}
@@ -0,0 +1,141 @@
+package manager
+
+import (
+	"context"
+	"os"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/lock"
+	"github.com/tmuxpack/tpack/internal/plug"
+)
+
+// Sync makes the plugin directory match the lockfile exactly: missing
+// plugins are cloned at their locked commit and installed plugins that
+// drifted or have local modifications are reset. If the lockfile does not
+// agree with the declared plugins, nothing is changed and an error is
+// reported.
+func (m *Manager) Sync(ctx context.Context, plugins []plug.Plugin) {
+	if !m.lockEnabled() || m.checkouter == nil {
+		m.output.Err("Lockfile support is not configured")
+		return
+	}
+	lf, err := lock.Load(m.lockPath)
+	if err != nil {
+		m.output.Err("Failed to read lockfile: " + err.Error())
+		return
+	}
+
+	if problems := lockProblems(lf, plugins); len(problems) > 0 {
+		for _, p := range problems {
+			m.output.Err(p)
+		}
+		m.output.Err("Lockfile " + m.lockPath + " is out of date; run \"tpack install\" or \"tpack update\" to refresh it")
+		return
+	}
+
+	if err := m.EnsurePathExists(); err != nil {
+		m.output.Err("Failed to create plugin directory: " + err.Error())
+		return
+	}
+	m.verifyPathPermissions()
+
+	for _, p := range plugins {
+		if p.IsLocal() {
+			m.installLocal(p)
+			continue
+		}
+		entry, _ := lf.Get(p.Name)
+		m.syncPlugin(ctx, p, entry)
+	}
+}
+
+// lockProblems lists every disagreement between the lockfile and the
+// declared plugins.
+func lockProblems(lf *lock.File, plugins []plug.Plugin) []string {
+	var problems []string
+	declared := make(map[string]bool, len(plugins))
+	for _, p := range plugins {
+		if p.IsLocal() {
+			continue
+		}
+		declared[p.Name] = true
+		e, ok := lf.Get(p.Name)
+		switch {
+		case !ok:
+			problems = append(problems, "\""+p.Name+"\" is not in the lockfile")
+		case !e.Matches(p.Spec, p.Branch, p.Version):
+			problems = append(problems, "\""+p.Name+"\" changed since it was locked")
+		case e.Commit == "":
+			problems = append(problems, "\""+p.Name+"\" has no locked commit")
+		}
+	}
+	for _, e := range lf.Plugins {
+		if !declared[e.Name] {
+			problems = append(problems, "\""+e.Name+"\" is locked but no longer declared")
+		}
+	}
+	return problems
+}
+
+func (m *Manager) syncPlugin(ctx context.Context, p plug.Plugin, entry lock.Entry) {
+	name := p.Name
+	dir := plug.PluginPath(name, m.pluginPath)
+	short := git.ShortHash(entry.Commit)
+
+	if !m.IsPluginInstalled(name) {
+		// Clear out leftovers (e.g. a non-git directory) before cloning.
+		if err := os.RemoveAll(dir); err != nil {
+			m.output.Err("  \"" + name + "\" sync fail: " + err.Error())
+			return
+		}
+		m.output.Ok("Installing \"" + name + "\" at " + short)
+		url := entry.URL
+		if url == "" {
+			url = p.Spec
+		}
+		err := git.CloneWithFallback(ctx, m.cloner, git.CloneOptions{
+			URL:    url,
+			Dir:    dir,
+			Branch: p.Branch,
+		}, plug.NormalizeURL)
+		if err != nil {
+			m.output.Err("  \"" + name + "\" download fail")
+			return
+		}
+		if err := m.checkouter.Checkout(ctx, dir, entry.Commit); err != nil {
+			m.output.Err("  \"" + name + "\" checkout of locked commit " + short + " failed")
+			return
+		}
+		m.output.Ok("  \"" + name + "\" download success")
+		return
+	}
+
+	head, err := m.revParser.RevParse(ctx, dir)
+	if err != nil {
+		m.output.Err("  \"" + name + "\" sync fail: " + err.Error())
+		return
+	}
+	dirty := false
+	if m.worktree != nil {
+		if dirty, err = m.worktree.IsDirty(ctx, dir); err != nil {
+			m.output.Err("  \"" + name + "\" sync fail: " + err.Error())
+			return
+		}
+	}
+
+	if head == entry.Commit && !dirty {
+		m.output.Ok("Already at locked commit \"" + name + "\" (" + short + ")")
+		return
+	}
+
+	reason := "drifted from " + short + " to " + git.ShortHash(head)
+	if head == entry.Commit {
+		reason = "has local modifications"
+	}
+	m.output.Ok("Resetting \"" + name + "\": " + reason)
+	if err := m.checkouter.Checkout(ctx, dir, entry.Commit); err != nil {
+		m.output.Err("  \"" + name + "\" reset to " + short + " failed")
+		return
+	}
+	m.output.Ok("  \"" + name + "\" reset to " + short)
+}
@@ -0,0 +1,193 @@
+package manager_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/lock"
+	"github.com/tmuxpack/tpack/internal/manager"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/ui"
+)
+
+type syncFixture struct {
+	pluginDir  string
+	lockPath   string
+	cloner     *git.MockCloner
+	validator  *git.MockValidator
+	revParser  *git.MockRevParser
+	checkouter *git.MockCheckouter
+	worktree   *git.MockWorktreeChecker
+	output     *ui.MockOutput
+	mgr        *manager.Manager
+}
+
+func newSyncFixture(t *testing.T, entries ...lock.Entry) *syncFixture {
+	t.Helper()
+	f := &syncFixture{
+		pluginDir:  setupTestDir(t),
+		lockPath:   filepath.Join(t.TempDir(), "tpack.lock"),
+		cloner:     git.NewMockCloner(),
+		validator:  git.NewMockValidator(),
+		revParser:  git.NewMockRevParser(),
+		checkouter: git.NewMockCheckouter(),
+		worktree:   git.NewMockWorktreeChecker(),
+		output:     ui.NewMockOutput(),
+	}
+	if len(entries) > 0 {
+		if err := lock.Save(f.lockPath, &lock.File{Plugins: entries}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.mgr = manager.New(f.pluginDir, f.cloner, git.NewMockPuller(), f.validator, f.output,
+		manager.WithRevParser(f.revParser),
+		manager.WithCheckouter(f.checkouter),
+		manager.WithWorktreeChecker(f.worktree),
+		manager.WithLockPath(f.lockPath),
+	)
+	return f
+}
+
+func (f *syncFixture) install(t *testing.T, name string) string {
+	t.Helper()
+	dir := filepath.Join(f.pluginDir, name)
+	os.MkdirAll(dir, 0o755)
+	f.validator.Valid[dir] = true
+	return dir
+}
+
+func TestSyncClonesMissingAtLockedCommit(t *testing.T) {
+	f := newSyncFixture(t, lock.Entry{Name: "repo", Spec: "user/repo", URL: "https://example.com/user/repo", Commit: "abcdef1234"})
+
+	f.mgr.Sync(context.Background(), []plug.Plugin{{Name: "repo", Spec: "user/repo"}})
+
+	if f.output.HasFailed() {
+		t.Fatalf("unexpected errors: %v", f.output.ErrMsgs)
+	}
+	if len(f.cloner.Calls) != 1 || f.cloner.Calls[0].URL != "https://example.com/user/repo" {
+		t.Fatalf("expected clone from locked URL, got %+v", f.cloner.Calls)
+	}
+	if len(f.checkouter.Calls) != 1 || f.checkouter.Calls[0].Ref != "abcdef1234" {
+		t.Errorf("expected checkout of locked commit, got %+v", f.checkouter.Calls)
+	}
+}
+
+func TestSyncResetsDriftedPlugin(t *testing.T) {
+	f := newSyncFixture(t, lock.Entry{Name: "repo", Spec: "user/repo", Commit: "locked"})
+	dir := f.install(t, "repo")
+	f.revParser.Hash = "drifted"
+
+	f.mgr.Sync(context.Background(), []plug.Plugin{{Name: "repo", Spec: "user/repo"}})
+
+	if len(f.checkouter.Calls) != 1 || f.checkouter.Calls[0].Dir != dir || f.checkouter.Calls[0].Ref != "locked" {
+		t.Errorf("expected reset to locked commit, got %+v", f.checkouter.Calls)
+	}
+	if len(f.cloner.Calls) != 0 {
+		t.Error("installed plugin should not be cloned")
+	}
+}
+
+func TestSyncResetsDirtyPlugin(t *testing.T) {
+	f := newSyncFixture(t, lock.Entry{Name: "repo", Spec: "user/repo", Commit: "locked"})
+	dir := f.install(t, "repo")
+	f.revParser.Hash = "locked"
+	f.worktree.Dirty[dir] = true
+
+	f.mgr.Sync(context.Background(), []plug.Plugin{{Name: "repo", Spec: "user/repo"}})
+
+	if len(f.checkouter.Calls) != 1 {
+		t.Fatalf("expected dirty plugin to be reset, got %+v", f.checkouter.Calls)
+	}
+	found := false
+	for _, msg := range f.output.OkMsgs {
+		if strings.Contains(msg, "local modifications") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected local modifications message, got %v", f.output.OkMsgs)
+	}
+}
+
+func TestSyncLeavesMatchingPluginAlone(t *testing.T) {
+	f := newSyncFixture(t, lock.Entry{Name: "repo", Spec: "user/repo", Commit: "locked"})
+	f.install(t, "repo")
+	f.revParser.Hash = "locked"
+
+	f.mgr.Sync(context.Background(), []plug.Plugin{{Name: "repo", Spec: "user/repo"}})
+
+	if len(f.checkouter.Calls) != 0 {
+		t.Errorf("expected no reset, got %+v", f.checkouter.Calls)
+	}
+	if f.output.HasFailed() {
+		t.Errorf("unexpected errors: %v", f.output.ErrMsgs)
+	}
+}
+
+func TestSyncStaleLockChangesNothing(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []lock.Entry
+		plugins []plug.Plugin
+		wantErr string
+	}{
+		{
+			name:    "missing lockfile",
+			plugins: []plug.Plugin{{Name: "repo", Spec: "user/repo"}},
+			wantErr: "\"repo\" is not in the lockfile",
+		},
+		{
+			name:    "branch changed",
+			entries: []lock.Entry{{Name: "repo", Spec: "user/repo", Commit: "c"}},
+			plugins: []plug.Plugin{{Name: "repo", Spec: "user/repo", Branch: "dev"}},
+			wantErr: "\"repo\" changed since it was locked",
+		},
+		{
+			name: "undeclared entry",
+			entries: []lock.Entry{
+				{Name: "repo", Spec: "user/repo", Commit: "c"},
+				{Name: "old", Spec: "user/old", Commit: "d"},
+			},
+			plugins: []plug.Plugin{{Name: "repo", Spec: "user/repo"}},
+			wantErr: "\"old\" is locked but no longer declared",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSyncFixture(t, tt.entries...)
+			dir := f.install(t, "repo")
+			f.revParser.Hash = "drifted"
+
+			f.mgr.Sync(context.Background(), tt.plugins)
+
+			if !f.output.HasFailed() {
+				t.Fatal("expected failure for stale lockfile")
+			}
+			if !strings.Contains(strings.Join(f.output.ErrMsgs, "\n"), tt.wantErr) {
+				t.Errorf("expected %q in errors, got %v", tt.wantErr, f.output.ErrMsgs)
+			}
+			if len(f.cloner.Calls) != 0 || len(f.checkouter.Calls) != 0 {
+				t.Error("stale lockfile must not change anything")
+			}
+			if _, err := os.Stat(dir); err != nil {
+				t.Errorf("installed plugin should be untouched: %v", err)
+			}
+		})
+	}
+}
+
+func TestSyncWithoutLockSupport(t *testing.T) {
+	output := ui.NewMockOutput()
+	mgr := manager.New(setupTestDir(t), git.NewMockCloner(), git.NewMockPuller(), git.NewMockValidator(), output)
+
+	mgr.Sync(context.Background(), []plug.Plugin{{Name: "repo", Spec: "user/repo"}})
+
+	if !output.HasFailed() {
+		t.Error("expected failure when lockfile support is not configured")
+	}
+}
@@ -2,32 +2,37 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/history"
+	"github.com/tmuxpack/tpack/internal/lock"
 	"github.com/tmuxpack/tpack/internal/parallel"
 	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/state"
+	"github.com/tmuxpack/tpack/internal/ui"
 )
 
 const maxConcurrentUpdates = 5
 
-func (m *Manager) updateAll(ctx context.Context, plugins []plug.Plugin) {
+func (m *Manager) updateAll(ctx context.Context, plugins []plug.Plugin, lf *lock.File) {
 	m.output.Ok("Updating all plugins!")
 	m.output.Ok("")
 
 	var installed []plug.Plugin
 	for _, p := range plugins {
-		if m.IsPluginInstalled(p.Name) {
+		if p.IsLocal() || m.IsPluginInstalled(p.Name) {
 			installed = append(installed, p)
 		}
 	}
 
 	parallel.Do(installed, maxConcurrentUpdates, func(p plug.Plugin) {
-		m.updatePlugin(ctx, p)
+		m.updatePlugin(ctx, p, lf)
 	})
 }
 
-func (m *Manager) updateSpecific(ctx context.Context, plugins []plug.Plugin, names []string) {
+func (m *Manager) updateSpecific(ctx context.Context, plugins []plug.Plugin, names []string, lf *lock.File) {
 	// Build lookup map for branch info.
 	pluginMap := make(map[string]plug.Plugin)
 	for _, p := range plugins {
@@ -37,11 +42,12 @@ func (m *Manager) updateSpecific(ctx context.Context, plugins []plug.Plugin, nam
 	var targets []plug.Plugin
 	for _, name := range names {
 		pName := plug.PluginName(name)
-		if !m.IsPluginInstalled(pName) {
+		p := pluginMap[pName] // Get full plugin for branch info.
+		if !p.IsLocal() && !m.IsPluginInstalled(pName) {
 			m.output.Err(pName + " not installed!")
+			m.report(ui.Result{Name: pName, Action: string(history.Update), Status: ui.StatusFailed, Error: "not installed"})
 			continue
 		}
-		p := pluginMap[pName] // Get full plugin for branch info.
 		if p.Name == "" {
 			p = plug.Plugin{Name: pName} // Fallback if not found in config.
 		}
@@ -49,21 +55,83 @@ func (m *Manager) updateSpecific(ctx context.Context, plugins []plug.Plugin, nam
 	}
 
 	parallel.Do(targets, maxConcurrentUpdates, func(p plug.Plugin) {
-		m.updatePlugin(ctx, p)
+		m.updatePlugin(ctx, p, lf)
 	})
 }
 
-func (m *Manager) updatePlugin(ctx context.Context, p plug.Plugin) {
+func (m *Manager) updatePlugin(ctx context.Context, p plug.Plugin, lf *lock.File) {
+	if p.IsLocal() {
+		m.output.Ok("  \"" + p.Name + "\" is a local plugin, skipping")
+		m.report(ui.Result{Name: p.Name, Action: string(history.Update), Status: ui.StatusSkipped})
+		return
+	}
+	if p.Version != "" {
+		m.updatePinned(ctx, p, lf)
+		return
+	}
+
 	dir := plug.PluginPath(p.Name, m.pluginPath)
-	output, err := m.puller.Pull(ctx, git.PullOptions{Dir: dir, Branch: p.Branch})
+	var output string
+	before, rolledBack, err := git.WithRollback(ctx, m.revParser, m.checkouter, dir, func() error {
+		var err error
+		output, err = m.puller.Pull(ctx, git.PullOptions{Dir: dir, Branch: p.Branch})
+		return err
+	})
 
 	indented := indentOutput(output)
 	if err != nil {
 		m.output.Err("  \"" + p.Name + "\" update fail")
 		m.output.Err(indented)
-	} else {
-		m.output.Ok("  \"" + p.Name + "\" update success")
-		m.output.Ok(indented)
+		if errors.Is(err, git.ErrRollbackFailed) {
+			m.output.Err(indentOutput(err.Error()))
+		}
+		m.reportRollback(p.Name, before, rolledBack, err)
+		m.record(history.Entry{Action: history.Update, Plugin: p.Name, Before: before, Error: failureText(output, err)})
+		return
+	}
+	m.output.Ok("  \"" + p.Name + "\" update success")
+	m.output.Ok(indented)
+	after := m.head(ctx, p.Name)
+	m.recordUpdate(ctx, p.Name, before, after, m.buildUpdated(ctx, p, dir, before, after))
+	m.recordLock(ctx, lf, p, "")
+}
+
+// reportRollback tells the user what state a failed update left name in.
+func (m *Manager) reportRollback(name, before string, rolledBack bool, err error) {
+	switch {
+	case rolledBack:
+		m.output.Err("  \"" + name + "\" rolled back to " + git.ShortHash(before))
+	case errors.Is(err, git.ErrRollbackFailed):
+		m.output.Err("  \"" + name + "\" could not be rolled back to " + git.ShortHash(before))
+	}
+}
+
+// recordUpdate logs an update of name from before to after and adds before to its
+// revision history for Rollback. Updates that did not move the plugin are
+// reported as unchanged but not recorded, unless the build that followed
+// them failed, which fails the update.
+func (m *Manager) recordUpdate(ctx context.Context, name, before, after string, buildErr error) {
+	if before != "" && after == before && buildErr == nil {
+		m.report(ui.Result{Name: name, Action: string(history.Update), Status: ui.StatusUnchanged, Before: before, After: after})
+		return
+	}
+	var failure string
+	if buildErr != nil {
+		failure = buildErr.Error()
+	}
+	m.record(history.Entry{
+		Action:  history.Update,
+		Plugin:  name,
+		Before:  before,
+		After:   after,
+		Commits: m.commitCount(ctx, name, before, after),
+		Error:   failure,
+	})
+	if m.statePath == "" || before == "" || after == "" || after == before {
+		return
+	}
+	if err := state.RecordRevision(m.statePath, name, before); err != nil {
+		m.output.Err("  \"" + name + "\" failed to record revision history: " + err.Error())
 	}
 }
 
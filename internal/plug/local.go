@@ -0,0 +1,54 @@
+package plug
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FilePrefix marks a plugin spec that points at a local directory.
+const FilePrefix = "file:"
+
+// ResolveLocalPath expands ~ and environment-style prefixes in a local
+// plugin path, and makes relative paths relative to baseDir.
+func ResolveLocalPath(path, baseDir, home, xdgConfigHome string) string {
+	path = ManualExpansion(path, home, xdgConfigHome)
+	if !filepath.IsAbs(path) && baseDir != "" {
+		path = filepath.Join(baseDir, path)
+	}
+	return filepath.Clean(path)
+}
+
+// IsLinked reports whether the plugin directory under pluginPath is a
+// symlink to p's local working tree.
+func IsLinked(p Plugin, pluginPath string) bool {
+	target, err := os.Readlink(PluginPath(p.Name, pluginPath))
+	return err == nil && filepath.Clean(target) == filepath.Clean(p.LocalPath)
+}
+
+// LinkLocal points the plugin directory under pluginPath at p's local
+// working tree, replacing a stale symlink. A real directory in the way
+// (e.g. an earlier clone) is left alone and reported as an error.
+func LinkLocal(p Plugin, pluginPath string) error {
+	info, err := os.Stat(p.LocalPath)
+	if err != nil {
+		return fmt.Errorf("local path %s: %w", p.LocalPath, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("local path %s is not a directory", p.LocalPath)
+	}
+
+	dir := PluginPath(p.Name, pluginPath)
+	if IsLinked(p, pluginPath) {
+		return nil
+	}
+	if fi, err := os.Lstat(dir); err == nil {
+		if fi.Mode()&os.ModeSymlink == 0 {
+			return fmt.Errorf("%s already exists and is not a symlink; remove it to use %s", dir, p.LocalPath)
+		}
+		if err := os.Remove(dir); err != nil {
+			return err
+		}
+	}
+	return os.Symlink(p.LocalPath, dir)
+}
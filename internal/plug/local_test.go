@@ -0,0 +1,102 @@
+package plug_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/plug"
+)
+
+func TestResolveLocalPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~/src/tmux-foo", "/home/user/src/tmux-foo"},
+		{"$HOME/src/tmux-foo/", "/home/user/src/tmux-foo"},
+		{"/srv/tmux-foo", "/srv/tmux-foo"},
+		{"plugins/tmux-foo", "/home/user/.config/tmux/plugins/tmux-foo"},
+		{"../tmux-foo", "/home/user/.config/tmux-foo"},
+	}
+	for _, tt := range tests {
+		got := plug.ResolveLocalPath(tt.path, "/home/user/.config/tmux", "/home/user", "")
+		if got != tt.want {
+			t.Errorf("ResolveLocalPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLinkLocal(t *testing.T) {
+	pluginPath := t.TempDir()
+	src := t.TempDir()
+	p := plug.Plugin{Name: "tmux-foo", LocalPath: src}
+
+	if plug.IsLinked(p, pluginPath) {
+		t.Fatal("expected plugin to be unlinked before LinkLocal")
+	}
+	if err := plug.LinkLocal(p, pluginPath); err != nil {
+		t.Fatalf("LinkLocal: %v", err)
+	}
+	if !plug.IsLinked(p, pluginPath) {
+		t.Fatal("expected plugin to be linked")
+	}
+	// Linking again is a no-op.
+	if err := plug.LinkLocal(p, pluginPath); err != nil {
+		t.Fatalf("second LinkLocal: %v", err)
+	}
+}
+
+func TestLinkLocalReplacesStaleLink(t *testing.T) {
+	pluginPath := t.TempDir()
+	old, src := t.TempDir(), t.TempDir()
+	if err := os.Symlink(old, filepath.Join(pluginPath, "tmux-foo")); err != nil {
+		t.Fatal(err)
+	}
+
+	p := plug.Plugin{Name: "tmux-foo", LocalPath: src}
+	if err := plug.LinkLocal(p, pluginPath); err != nil {
+		t.Fatalf("LinkLocal: %v", err)
+	}
+	if !plug.IsLinked(p, pluginPath) {
+		t.Error("expected stale link to be replaced")
+	}
+}
+
+func TestLinkLocalKeepsRealDirectory(t *testing.T) {
+	pluginPath := t.TempDir()
+	clone := filepath.Join(pluginPath, "tmux-foo")
+	if err := os.MkdirAll(clone, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := plug.LinkLocal(plug.Plugin{Name: "tmux-foo", LocalPath: t.TempDir()}, pluginPath)
+	if err == nil {
+		t.Fatal("expected error when a real directory is in the way")
+	}
+	if fi, err := os.Lstat(clone); err != nil || !fi.IsDir() {
+		t.Error("existing directory should be left alone")
+	}
+}
+
+func TestLinkLocalMissingSource(t *testing.T) {
+	p := plug.Plugin{Name: "tmux-foo", LocalPath: filepath.Join(t.TempDir(), "missing")}
+	if err := plug.LinkLocal(p, t.TempDir()); err == nil {
+		t.Error("expected error for missing local path")
+	}
+}
+
+func TestFindOrphansSkipsSymlinks(t *testing.T) {
+	pluginPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(pluginPath, "stale"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(t.TempDir(), filepath.Join(pluginPath, "linked")); err != nil {
+		t.Fatal(err)
+	}
+
+	orphans := plug.FindOrphans(nil, pluginPath)
+	if len(orphans) != 1 || orphans[0].Name != "stale" {
+		t.Errorf("expected only the real directory as orphan, got %+v", orphans)
+	}
+}
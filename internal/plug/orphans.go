@@ -12,6 +12,7 @@ type Orphan struct {
 }
 
 // Returns directories in pluginPath that don't match any plugin name.
+// Disabled plugins are still declared, so their directories are kept.
 func FindOrphans(plugins []Plugin, pluginPath string) []Orphan {
 	nameSet := make(map[string]bool, len(plugins))
 	for _, p := range plugins {
@@ -25,7 +26,9 @@ func FindOrphans(plugins []Plugin, pluginPath string) []Orphan {
 
 	var orphans []Orphan
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		// Symlinks are local plugins (or links the user manages); removing
+		// them is never tpack's call. Plain files are not plugins either.
+		if entry.Type()&os.ModeSymlink != 0 || !entry.IsDir() {
 			continue
 		}
 		name := PluginName(entry.Name())
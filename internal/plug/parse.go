@@ -1,76 +1,43 @@
 package plug
 
 import (
-	"regexp"
 	"strings"
-)
-
-var (
-	// Matches: set -g @plugin "...", set-option -g @plugin '...',
-	// or unquoted set -g @plugin value, with optional leading whitespace.
-	// Three alternations handle double-quoted, single-quoted, and unquoted values.
-	pluginLineRe = regexp.MustCompile(
-		`^[ \t]*set(?:-option)?\s+-g\s+@plugin\s+(?:"([^"]+)"|'([^']+)'|(\S+))`)
 
-	// Matches: source "...", source-file -q "...", source '...', or unquoted path.
-	// Three alternations handle double-quoted, single-quoted, and unquoted values.
-	sourcedFileRe = regexp.MustCompile(
-		`^[ \t]*source(?:-file)?\s+(?:-q\s+)?(?:"([^"]+)"|'([^']+)'|(\S+))`)
+	"github.com/tmuxpack/tpack/internal/tmuxconf"
 )
 
-// extractMatches scans content line by line and collects the first
-// non-empty capture group from re for each non-comment line that matches.
-func extractMatches(content string, re *regexp.Regexp) []string {
+// declValues parses content as tmux configuration and returns the values
+// selected by decls. A syntax error ends parsing, as it does in tmux; the
+// declarations before it are kept.
+func declValues(content string, decls func(*tmuxconf.File) []tmuxconf.Decl) []string {
+	f, _ := tmuxconf.Parse("", []byte(content))
 	var results []string
-	for line := range strings.SplitSeq(content, "\n") {
-		line = strings.TrimRight(line, "\r")
-		if strings.HasPrefix(strings.TrimSpace(line), "#") {
-			continue
-		}
-		if m := re.FindStringSubmatch(line); m != nil {
-			// m[1] = double-quoted, m[2] = single-quoted, m[3] = unquoted
-			val := m[1]
-			if val == "" {
-				val = m[2]
-			}
-			if val == "" {
-				val = m[3]
-			}
-			val = strings.TrimSpace(val)
-			if val != "" {
-				results = append(results, val)
-			}
-		}
+	for _, d := range decls(f) {
+		results = append(results, d.Value)
 	}
 	return results
 }
 
 // MatchesPluginLine reports whether line is a @plugin declaration for the given spec.
 func MatchesPluginLine(line, spec string) bool {
-	m := pluginLineRe.FindStringSubmatch(line)
-	if m == nil {
-		return false
-	}
-	val := m[1]
-	if val == "" {
-		val = m[2]
-	}
-	if val == "" {
-		val = m[3]
+	for _, v := range declValues(line, (*tmuxconf.File).Plugins) {
+		if v == spec {
+			return true
+		}
 	}
-	return strings.TrimSpace(val) == spec
+	return false
 }
 
 // ExtractPluginsFromConfig parses tmux config content and returns all
 // plugin specifications found in @plugin declarations.
 func ExtractPluginsFromConfig(content string) []string {
-	return extractMatches(content, pluginLineRe)
+	return declValues(content, (*tmuxconf.File).Plugins)
 }
 
 // ExtractSourcedFiles parses tmux config content and returns all
 // file paths referenced by source or source-file commands.
 func ExtractSourcedFiles(content string) []string {
-	return extractMatches(content, sourcedFileRe)
+	return declValues(content, (*tmuxconf.File).SourcedFiles)
 }
 
 // ManualExpansion expands ~, $HOME, ${HOME}, $XDG_CONFIG_HOME, and
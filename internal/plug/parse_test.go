@@ -93,6 +93,26 @@ set -g @plugin "tmux-plugins/tmux-yank"`,
 			content: "set -g @plugin tmux-plugins/tpm   ",
 			want:    []string{"tmux-plugins/tpm"},
 		},
+		{
+			name:    "combined and extra flags",
+			content: "set -ga @plugin 'tmux-plugins/tpm'\nset-option -gq @plugin tmux-plugins/tmux-yank",
+			want:    []string{"tmux-plugins/tpm", "tmux-plugins/tmux-yank"},
+		},
+		{
+			name:    "backslash-continued line",
+			content: "set -g \\\n  @plugin 'tmux-plugins/tpm'",
+			want:    []string{"tmux-plugins/tpm"},
+		},
+		{
+			name:    "semicolon-separated commands",
+			content: "set -g mouse on; set -g @plugin a ; set -g @plugin b",
+			want:    []string{"a", "b"},
+		},
+		{
+			name:    "inline comment after value",
+			content: "set -g @plugin 'tmux-plugins/tpm' # plugin manager",
+			want:    []string{"tmux-plugins/tpm"},
+		},
 	}
 
 	for _, tt := range tests {
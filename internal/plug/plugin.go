@@ -1,18 +1,98 @@
 // Package plug provides the plugin model and parsing for tpack.
 package plug
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// LegacyOption is the tmux option tpm read space-separated plugin specs
+// from before @plugin declarations.
+const LegacyOption = "@tpm_plugins"
+
 // Plugin represents a tmux plugin definition.
 type Plugin struct {
-	// Raw is the original plugin specification string (e.g. "user/repo#branch").
+	// Raw is the original plugin specification string (e.g. "user/repo@^2").
 	Raw string
 	// Name is the derived plugin name (e.g. "repo").
 	Name string
-	// Spec is the plugin specifier without branch (e.g. "user/repo" or full URL).
+	// Spec is the plugin specifier without branch or version (e.g. "user/repo" or full URL).
 	// This may be a shorthand that requires NormalizeURL before cloning.
 	Spec string
 	// Branch is the optional branch to check out (empty string = default).
 	Branch string
+	// Version is the optional version pin from "@version": an exact tag,
+	// a semver range such as "^2" or "~2.1", or a commit hash.
+	Version string
 	// Alias is the optional alias from "alias=X" in config.
 	// When set, Name is derived from Alias instead of the spec.
 	Alias string
+	// LocalPath is the local working tree from a "file:" spec or a
+	// "path=X" token. Local plugins are linked into the plugin directory
+	// instead of cloned, and are never pulled.
+	LocalPath string
+	// SourceFile is the config file that declares the plugin. It is empty
+	// for plugins from the legacy @tpm_plugins option.
+	SourceFile string
+	// SourceLine is the line of SourceFile the declaration starts on.
+	SourceLine int
+	// Options are tmux options (e.g. "@foo-bar") to set immediately before
+	// the plugin is sourced, from "opts=" tokens or the manifest, sorted by
+	// name.
+	Options []Option
+	// After names the plugins that must be sourced before this one, from
+	// "after=" tokens or the manifest.
+	After []string
+	// OnKey lists prefix-table keys, from "on-key=" tokens or the manifest,
+	// that load the plugin the first time one is pressed instead of at
+	// startup.
+	OnKey []string
+	// OnHook lists tmux hooks, from "on-hook=" tokens or the manifest, that
+	// load the plugin the first time one runs instead of at startup.
+	OnHook []string
+	// Build is a shell command run in the plugin directory after the plugin
+	// is cloned or updated, from a "build=" token or the manifest.
+	Build string
+	// Disabled plugins stay installed but are not sourced, from a
+	// "disabled" token or "enabled: false" in the manifest.
+	Disabled bool
+}
+
+// Option is a tmux option set on a plugin's behalf.
+type Option struct {
+	Name  string
+	Value string
+}
+
+// AddOption returns opts with name set to value, keeping it sorted by name.
+func AddOption(opts []Option, name, value string) []Option {
+	i, found := slices.BinarySearchFunc(opts, name, func(o Option, name string) int {
+		return strings.Compare(o.Name, name)
+	})
+	if found {
+		opts[i].Value = value
+		return opts
+	}
+	return slices.Insert(opts, i, Option{Name: name, Value: value})
+}
+
+// Origin returns where the plugin is declared as "file:line", or
+// "@tpm_plugins" for plugins from the legacy option.
+func (p Plugin) Origin() string {
+	if p.SourceFile == "" {
+		return LegacyOption
+	}
+	return fmt.Sprintf("%s:%d", p.SourceFile, p.SourceLine)
+}
+
+// IsLazy reports whether the plugin is loaded on demand, by a key or hook,
+// rather than at startup.
+func (p Plugin) IsLazy() bool {
+	return len(p.OnKey) > 0 || len(p.OnHook) > 0
+}
+
+// IsLocal reports whether the plugin is sourced from a local directory.
+func (p Plugin) IsLocal() bool {
+	return p.LocalPath != ""
 }
@@ -30,35 +30,81 @@ func PluginPath(raw, tpmPath string) string {
 }
 
 // NormalizeURL converts a shorthand plugin name to a full git URL.
-// If the input already has a protocol prefix or contains ":", it is returned as-is.
+// Inputs with a protocol prefix, "git@" addresses, "file:" specs and
+// filesystem paths are returned as-is.
 // Otherwise it is expanded to a GitHub HTTPS URL.
 // The "git::@" prefix is a credential placeholder used by the original TPM
 // to prevent git from prompting for authentication on non-existent repos.
 func NormalizeURL(shorthand string) string {
-	if strings.Contains(shorthand, "://") || strings.Contains(shorthand, "git@") {
+	if strings.Contains(shorthand, "://") || strings.Contains(shorthand, "git@") || isLocalPath(shorthand) {
 		return shorthand
 	}
 	return "https://git::@github.com/" + shorthand
 }
 
+// isLocalPath reports whether s names a local directory rather than a
+// GitHub shorthand.
+func isLocalPath(s string) bool {
+	return strings.HasPrefix(s, FilePrefix) ||
+		strings.HasPrefix(s, "/") ||
+		strings.HasPrefix(s, "~") ||
+		strings.HasPrefix(s, "./") ||
+		strings.HasPrefix(s, "../") ||
+		strings.HasPrefix(s, "$")
+}
+
 // ParseSpec parses a raw plugin specification into a Plugin struct.
-// The format is "spec#branch" where #branch is optional.
+// The format is "spec@version#branch" where @version and #branch are optional.
+// A version pins the plugin to a tag, a semver range, or a commit; it is only
+// recognized in the last path segment so "git@host:..." URLs are unaffected.
 // An optional "alias=X" token may follow the spec to override the plugin name.
+// An "opts=@a=1,@b=2" token lists tmux options to set before the plugin is
+// sourced; values can't contain spaces or commas. An "after=a,b" token names
+// plugins to source before this one. A "disabled" token keeps the plugin
+// installed but stops it from being sourced. "on-key=C-f" and
+// "on-hook=session-created" tokens, also comma-separated lists, defer
+// sourcing until the key is pressed or the hook runs. A "build=CMD" token
+// takes the rest of the spec as a command to run in the plugin directory
+// after it is installed or updated, so it must come last.
+// A "file:DIR" spec, or a "path=DIR" token with or without a spec, makes the
+// plugin local: DIR is linked into the plugin directory instead of cloned.
 // The branch suffix "#branch" may appear on either the spec or the alias token.
-// Example: "catppuccin/tmux alias=catppuccin-tmux#v2"
+// Example: "catppuccin/tmux@^2 alias=catppuccin-tmux"
 func ParseSpec(raw string) Plugin {
 	raw = strings.TrimSpace(raw)
 	original := raw
 
+	// The build command may contain spaces: it runs to the end of the spec.
+	var build string
+	if i := buildTokenIndex(raw); i >= 0 {
+		build = strings.TrimSpace(raw[i+len(buildPrefix):])
+		raw = strings.TrimSpace(raw[:i])
+	}
+
 	// Split on whitespace to find tokens.
 	tokens := strings.Fields(raw)
 
-	// Extract alias token if present.
-	var alias string
+	// Extract alias and path tokens if present.
+	var alias, localPath string
+	var options []Option
+	var sourceAfter, onKey, onHook []string
+	var disabled bool
 	var specTokens []string
 	for _, tok := range tokens {
-		if after, ok := strings.CutPrefix(tok, "alias="); ok {
+		if tok == "disabled" {
+			disabled = true
+		} else if after, ok := strings.CutPrefix(tok, "alias="); ok {
 			alias = after
+		} else if after, ok := strings.CutPrefix(tok, "path="); ok {
+			localPath = after
+		} else if after, ok := strings.CutPrefix(tok, "opts="); ok {
+			options = parseOptions(raw, after, options)
+		} else if names, ok := strings.CutPrefix(tok, "after="); ok {
+			sourceAfter = appendList(sourceAfter, names)
+		} else if keys, ok := strings.CutPrefix(tok, "on-key="); ok {
+			onKey = appendList(onKey, keys)
+		} else if hooks, ok := strings.CutPrefix(tok, "on-hook="); ok {
+			onHook = appendList(onHook, hooks)
 		} else {
 			specTokens = append(specTokens, tok)
 		}
@@ -73,6 +119,33 @@ func ParseSpec(raw string) Plugin {
 		warnExtraTokens(raw, specTokens[1:])
 	}
 
+	// Local specs are paths: no branch or version suffixes.
+	if after, ok := strings.CutPrefix(spec, FilePrefix); ok {
+		localPath = after
+	}
+	if localPath != "" && (spec == "" || strings.HasPrefix(spec, FilePrefix)) {
+		if spec == "" {
+			spec = FilePrefix + localPath
+		}
+		name := PluginName(strings.TrimRight(localPath, "/"))
+		if alias != "" {
+			name = alias
+		}
+		return Plugin{
+			Raw:       original,
+			Name:      name,
+			Spec:      spec,
+			Alias:     alias,
+			LocalPath: localPath,
+			Options:   options,
+			After:     sourceAfter,
+			OnKey:     onKey,
+			OnHook:    onHook,
+			Build:     build,
+			Disabled:  disabled,
+		}
+	}
+
 	// Extract branch from spec if present.
 	var branch string
 	if idx := strings.LastIndex(spec, "#"); idx > 0 {
@@ -80,6 +153,13 @@ func ParseSpec(raw string) Plugin {
 		spec = spec[:idx]
 	}
 
+	// Extract version pin from the last path segment if present.
+	var version string
+	if idx := strings.LastIndex(spec, "@"); idx > 0 && idx > strings.LastIndexAny(spec, "/:") {
+		version = spec[idx+1:]
+		spec = spec[:idx]
+	}
+
 	// Extract branch from alias if present (and no branch found on spec).
 	if alias != "" {
 		if idx := strings.LastIndex(alias, "#"); idx > 0 {
@@ -96,10 +176,61 @@ func ParseSpec(raw string) Plugin {
 	}
 
 	return Plugin{
-		Raw:    original,
-		Name:   name,
-		Spec:   spec,
-		Branch: branch,
-		Alias:  alias,
+		Raw:       original,
+		Name:      name,
+		Spec:      spec,
+		Branch:    branch,
+		Version:   version,
+		Alias:     alias,
+		LocalPath: localPath,
+		Options:   options,
+		After:     sourceAfter,
+		OnKey:     onKey,
+		OnHook:    onHook,
+		Build:     build,
+		Disabled:  disabled,
+	}
+}
+
+const buildPrefix = "build="
+
+// buildTokenIndex returns where the "build=" token starts in raw, or -1.
+func buildTokenIndex(raw string) int {
+	for i := 0; i < len(raw); {
+		j := strings.Index(raw[i:], buildPrefix)
+		if j < 0 {
+			return -1
+		}
+		j += i
+		if j == 0 || raw[j-1] == ' ' || raw[j-1] == '\t' {
+			return j
+		}
+		i = j + len(buildPrefix)
+	}
+	return -1
+}
+
+// appendList adds the non-empty items of a comma-separated list to items.
+func appendList(items []string, list string) []string {
+	for item := range strings.SplitSeq(list, ",") {
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseOptions adds the options of an "opts=" token, a comma-separated
+// list of @name=value pairs, to opts. Malformed pairs are warned about and
+// skipped.
+func parseOptions(raw, list string, opts []Option) []Option {
+	for pair := range strings.SplitSeq(list, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || len(name) < 2 || name[0] != '@' {
+			fmt.Fprintf(os.Stderr, "tpack: warning: plugin spec %q has an invalid option %q (want @name=value)\n", raw, pair)
+			continue
+		}
+		opts = AddOption(opts, name, value)
 	}
+	return opts
 }
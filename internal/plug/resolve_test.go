@@ -1,6 +1,7 @@
 package plug_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/tmuxpack/tpack/internal/plug"
@@ -59,6 +60,10 @@ func TestNormalizeURL(t *testing.T) {
 		{"https://github.com/user/repo.git", "https://github.com/user/repo.git"},
 		{"git@github.com:user/repo.git", "git@github.com:user/repo.git"},
 		{"https://git::@github.com/user/repo", "https://git::@github.com/user/repo"},
+		{"file:~/src/tmux-foo", "file:~/src/tmux-foo"},
+		{"/srv/repos/tmux-foo", "/srv/repos/tmux-foo"},
+		{"~/src/tmux-foo", "~/src/tmux-foo"},
+		{"../tmux-foo", "../tmux-foo"},
 	}
 
 	for _, tt := range tests {
@@ -73,19 +78,29 @@ func TestNormalizeURL(t *testing.T) {
 
 func TestParseSpec(t *testing.T) {
 	tests := []struct {
-		raw    string
-		name   string
-		spec   string
-		branch string
-		alias  string
+		raw     string
+		name    string
+		spec    string
+		branch  string
+		version string
+		alias   string
 	}{
-		{"user/repo", "repo", "user/repo", "", ""},
-		{"user/repo#develop", "repo", "user/repo", "develop", ""},
-		{"https://github.com/user/plugin.git#main", "plugin", "https://github.com/user/plugin.git", "main", ""},
-		{"simple", "simple", "simple", "", ""},
-		{"catppuccin/tmux alias=catppuccin-tmux", "catppuccin-tmux", "catppuccin/tmux", "", "catppuccin-tmux"},
-		{"catppuccin/tmux alias=catppuccin-tmux#v2", "catppuccin-tmux", "catppuccin/tmux", "v2", "catppuccin-tmux"},
-		{"https://github.com/user/repo.git alias=my-plugin", "my-plugin", "https://github.com/user/repo.git", "", "my-plugin"},
+		{"user/repo", "repo", "user/repo", "", "", ""},
+		{"user/repo#develop", "repo", "user/repo", "develop", "", ""},
+		{"https://github.com/user/plugin.git#main", "plugin", "https://github.com/user/plugin.git", "main", "", ""},
+		{"simple", "simple", "simple", "", "", ""},
+		{"catppuccin/tmux alias=catppuccin-tmux", "catppuccin-tmux", "catppuccin/tmux", "", "", "catppuccin-tmux"},
+		{"catppuccin/tmux alias=catppuccin-tmux#v2", "catppuccin-tmux", "catppuccin/tmux", "v2", "", "catppuccin-tmux"},
+		{"https://github.com/user/repo.git alias=my-plugin", "my-plugin", "https://github.com/user/repo.git", "", "", "my-plugin"},
+		{"user/repo@v2.1.0", "repo", "user/repo", "", "v2.1.0", ""},
+		{"user/repo@^2", "repo", "user/repo", "", "^2", ""},
+		{"user/repo@3f2a9c1", "repo", "user/repo", "", "3f2a9c1", ""},
+		{"user/repo@~1.4#develop", "repo", "user/repo", "develop", "~1.4", ""},
+		{"git@github.com:user/repo.git", "repo", "git@github.com:user/repo.git", "", "", ""},
+		{"git@github.com:user/repo.git@v1.0.0", "repo", "git@github.com:user/repo.git", "", "v1.0.0", ""},
+		{"https://github.com/user/repo.git@^3", "repo", "https://github.com/user/repo.git", "", "^3", ""},
+		{"catppuccin/tmux@^2 alias=catppuccin-tmux", "catppuccin-tmux", "catppuccin/tmux", "", "^2", "catppuccin-tmux"},
+		{"file:~/src/tmux-foo@work#wip", "tmux-foo@work#wip", "file:~/src/tmux-foo@work#wip", "", "", ""},
 	}
 
 	for _, tt := range tests {
@@ -100,9 +115,174 @@ func TestParseSpec(t *testing.T) {
 			if p.Branch != tt.branch {
 				t.Errorf("Branch = %q, want %q", p.Branch, tt.branch)
 			}
+			if p.Version != tt.version {
+				t.Errorf("Version = %q, want %q", p.Version, tt.version)
+			}
 			if p.Alias != tt.alias {
 				t.Errorf("Alias = %q, want %q", p.Alias, tt.alias)
 			}
 		})
 	}
 }
+
+func TestParseSpecLocal(t *testing.T) {
+	tests := []struct {
+		raw       string
+		name      string
+		spec      string
+		localPath string
+	}{
+		{"file:~/src/tmux-foo", "tmux-foo", "file:~/src/tmux-foo", "~/src/tmux-foo"},
+		{"file:/srv/tmux-foo/", "tmux-foo", "file:/srv/tmux-foo/", "/srv/tmux-foo/"},
+		{"path=~/src/tmux-foo", "tmux-foo", "file:~/src/tmux-foo", "~/src/tmux-foo"},
+		{"file:~/src/tmux-foo alias=foo", "foo", "file:~/src/tmux-foo", "~/src/tmux-foo"},
+		{"user/tmux-foo path=~/src/tmux-foo", "tmux-foo", "user/tmux-foo", "~/src/tmux-foo"},
+		{"user/repo", "repo", "user/repo", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			p := plug.ParseSpec(tt.raw)
+			if p.Name != tt.name {
+				t.Errorf("Name = %q, want %q", p.Name, tt.name)
+			}
+			if p.Spec != tt.spec {
+				t.Errorf("Spec = %q, want %q", p.Spec, tt.spec)
+			}
+			if p.LocalPath != tt.localPath {
+				t.Errorf("LocalPath = %q, want %q", p.LocalPath, tt.localPath)
+			}
+			if p.IsLocal() != (tt.localPath != "") {
+				t.Errorf("IsLocal() = %v", p.IsLocal())
+			}
+		})
+	}
+}
+
+func TestParseSpecOptions(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"tmux-plugins/tmux-resurrect opts=@resurrect-strategy-nvim=session", "@resurrect-strategy-nvim=session"},
+		{"user/repo opts=@b=2,@a=1", "@a=1 @b=2"},
+		{"user/repo opts=@a=1 opts=@a=2,@c=", "@a=2 @c="},
+		{"user/repo opts=nope=1,@ok=yes,@bad", "@ok=yes"},
+		{"file:~/src/tmux-foo opts=@foo=bar", "@foo=bar"},
+		{"user/repo", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			p := plug.ParseSpec(tt.raw)
+			var got []string
+			for _, o := range p.Options {
+				got = append(got, o.Name+"="+o.Value)
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("Options = %v, want %q", got, tt.want)
+			}
+			if p.Spec == "" || strings.Contains(p.Spec, "opts=") {
+				t.Errorf("Spec = %q", p.Spec)
+			}
+		})
+	}
+}
+
+func TestParseSpecAfter(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"user/status after=theme", "theme"},
+		{"user/status after=theme,tmux-sensible after=cpu", "theme tmux-sensible cpu"},
+		{"user/status after=,theme,", "theme"},
+		{"file:~/src/status after=theme", "theme"},
+		{"user/status", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			p := plug.ParseSpec(tt.raw)
+			if got := strings.Join(p.After, " "); got != tt.want {
+				t.Errorf("After = %q, want %q", got, tt.want)
+			}
+			if strings.Contains(p.Spec, "after=") {
+				t.Errorf("Spec = %q", p.Spec)
+			}
+		})
+	}
+}
+
+func TestParseSpecLazy(t *testing.T) {
+	tests := []struct {
+		raw    string
+		onKey  string
+		onHook string
+	}{
+		{"sainnhe/tmux-fzf on-key=F", "F", ""},
+		{"tmux-plugins/tmux-resurrect on-key=C-s,C-r on-hook=session-created", "C-s C-r", "session-created"},
+		{"file:~/src/picker on-hook=client-attached,", "", "client-attached"},
+		{"tmux-plugins/tmux-cpu", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			p := plug.ParseSpec(tt.raw)
+			if got := strings.Join(p.OnKey, " "); got != tt.onKey {
+				t.Errorf("OnKey = %q, want %q", got, tt.onKey)
+			}
+			if got := strings.Join(p.OnHook, " "); got != tt.onHook {
+				t.Errorf("OnHook = %q, want %q", got, tt.onHook)
+			}
+			if p.IsLazy() != (tt.onKey != "" || tt.onHook != "") {
+				t.Errorf("IsLazy() = %v", p.IsLazy())
+			}
+			if strings.Contains(p.Spec, "on-") {
+				t.Errorf("Spec = %q", p.Spec)
+			}
+		})
+	}
+}
+
+func TestParseSpecBuild(t *testing.T) {
+	tests := []struct {
+		raw   string
+		name  string
+		build string
+	}{
+		{"fcsonline/tmux-thumbs build=cargo build --release", "tmux-thumbs", "cargo build --release"},
+		{"Morantron/tmux-fingers alias=fingers build=./install-wizard.sh", "fingers", "./install-wizard.sh"},
+		{"file:~/src/picker build=make  ", "picker", "make"},
+		{"user/rebuild=x", "rebuild=x", ""},
+		{"tmux-plugins/tmux-cpu", "tmux-cpu", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			p := plug.ParseSpec(tt.raw)
+			if p.Name != tt.name || p.Build != tt.build {
+				t.Errorf("ParseSpec() = %q build=%q, want %q build=%q", p.Name, p.Build, tt.name, tt.build)
+			}
+		})
+	}
+}
+
+func TestParseSpecDisabled(t *testing.T) {
+	tests := []struct {
+		raw      string
+		name     string
+		disabled bool
+	}{
+		{"tmux-plugins/tmux-cpu disabled", "tmux-cpu", true},
+		{"catppuccin/tmux#v2 disabled alias=theme", "theme", true},
+		{"file:~/src/tmux-foo disabled", "tmux-foo", true},
+		{"tmux-plugins/tmux-cpu", "tmux-cpu", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			p := plug.ParseSpec(tt.raw)
+			if p.Name != tt.name || p.Disabled != tt.disabled {
+				t.Errorf("ParseSpec() = %q disabled=%v, want %q disabled=%v", p.Name, p.Disabled, tt.name, tt.disabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Package profile records how long each plugin script takes to source, so
+// the plugins slowing down tmux startup can be found.
+//
+// The last run is kept as profile.json inside the tpack state directory.
+package profile
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+const profileFile = "profile.json"
+
+// Script is the outcome of running one plugin script.
+type Script struct {
+	Plugin   string        `json:"plugin"`
+	File     string        `json:"file"`
+	Duration time.Duration `json:"duration"`
+	// ExitCode is the script's exit status, or -1 if it couldn't be run.
+	ExitCode int    `json:"exit_code"`
+	Stderr   string `json:"stderr,omitempty"`
+	// Error describes a failure to run the script at all.
+	Error string `json:"error,omitempty"`
+	// TimedOut is set when the script was killed for running too long.
+	TimedOut bool `json:"timed_out,omitempty"`
+}
+
+// Failed reports whether the script exited non-zero or couldn't be run.
+func (s Script) Failed() bool {
+	return s.ExitCode != 0
+}
+
+// Run is one profiled sourcing of every plugin.
+type Run struct {
+	Time time.Time `json:"time"`
+	// Total is the wall time of the whole run, which is less than the sum
+	// of the scripts' durations when plugins are sourced in parallel.
+	Total    time.Duration `json:"total"`
+	Parallel bool          `json:"parallel,omitempty"`
+	Scripts  []Script      `json:"scripts"`
+}
+
+// Sort orders the scripts slowest first.
+func (r *Run) Sort() {
+	slices.SortStableFunc(r.Scripts, func(a, b Script) int {
+		return cmp.Compare(b.Duration, a.Duration)
+	})
+}
+
+// Plugin is the combined time of a plugin's scripts.
+type Plugin struct {
+	Name     string
+	Duration time.Duration
+	Scripts  int
+	Failed   int
+}
+
+// Plugins returns the time taken by each plugin, slowest first.
+func (r *Run) Plugins() []Plugin {
+	var plugins []Plugin
+	index := make(map[string]int)
+	for _, s := range r.Scripts {
+		i, ok := index[s.Plugin]
+		if !ok {
+			i = len(plugins)
+			index[s.Plugin] = i
+			plugins = append(plugins, Plugin{Name: s.Plugin})
+		}
+		plugins[i].Duration += s.Duration
+		plugins[i].Scripts++
+		if s.Failed() {
+			plugins[i].Failed++
+		}
+	}
+	slices.SortStableFunc(plugins, func(a, b Plugin) int {
+		return cmp.Compare(b.Duration, a.Duration)
+	})
+	return plugins
+}
+
+// Path returns the profile file location inside statePath.
+func Path(statePath string) string {
+	return filepath.Join(statePath, profileFile)
+}
+
+// Save replaces the last run saved in statePath with r.
+func Save(statePath string, r *Run) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(statePath, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(statePath, ".profile-*")
+	if err != nil {
+		return fmt.Errorf("write profile: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // best-effort cleanup after rename
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write profile: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write profile: %w", err)
+	}
+	return os.Rename(tmp.Name(), Path(statePath))
+}
+
+// Load reads the last run saved in statePath. It returns nil if no run has
+// been profiled yet.
+func Load(statePath string) (*Run, error) {
+	data, err := os.ReadFile(Path(statePath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var r Run
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", Path(statePath), err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,88 @@
+package profile_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tmuxpack/tpack/internal/profile"
+)
+
+func TestLoadMissing(t *testing.T) {
+	r, err := profile.Load(t.TempDir())
+	if err != nil || r != nil {
+		t.Errorf("Load() = %v, %v; want nothing", r, err)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+	run := &profile.Run{
+		Time:  at,
+		Total: 900 * time.Millisecond,
+		Scripts: []profile.Script{
+			{Plugin: "tmux-sensible", File: "sensible.tmux", Duration: 20 * time.Millisecond},
+			{Plugin: "tmux-cpu", File: "cpu.tmux", Duration: 700 * time.Millisecond, ExitCode: 1, Stderr: "boom\n"},
+		},
+	}
+	if err := profile.Save(dir, run); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got, err := profile.Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !got.Time.Equal(at) || got.Total != run.Total || len(got.Scripts) != 2 {
+		t.Fatalf("Load() = %+v", got)
+	}
+	if s := got.Scripts[1]; s.Stderr != "boom\n" || !s.Failed() || s.Duration != 700*time.Millisecond {
+		t.Errorf("Scripts[1] = %+v", s)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected only the profile in the state directory, got %d entries", len(entries))
+	}
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(profile.Path(dir), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := profile.Load(dir); err == nil {
+		t.Error("expected an error for a corrupt profile")
+	}
+}
+
+func TestSortAndPlugins(t *testing.T) {
+	run := &profile.Run{Scripts: []profile.Script{
+		{Plugin: "a", File: "a.tmux", Duration: 10 * time.Millisecond},
+		{Plugin: "b", File: "b1.tmux", Duration: 30 * time.Millisecond},
+		{Plugin: "c", File: "c.tmux", Duration: 50 * time.Millisecond},
+		{Plugin: "b", File: "b2.tmux", Duration: 40 * time.Millisecond, ExitCode: 2},
+	}}
+
+	run.Sort()
+	var files []string
+	for _, s := range run.Scripts {
+		files = append(files, s.File)
+	}
+	if want := "c.tmux b2.tmux b1.tmux a.tmux"; strings.Join(files, " ") != want {
+		t.Errorf("sorted scripts = %v, want %s", files, want)
+	}
+
+	plugins := run.Plugins()
+	if len(plugins) != 3 {
+		t.Fatalf("Plugins() = %+v", plugins)
+	}
+	if b := plugins[0]; b.Name != "b" || b.Duration != 70*time.Millisecond || b.Scripts != 2 || b.Failed != 1 {
+		t.Errorf("Plugins()[0] = %+v", b)
+	}
+	if plugins[1].Name != "c" || plugins[2].Name != "a" {
+		t.Errorf("Plugins() = %+v, want slowest first", plugins)
+	}
+}
@@ -24,6 +24,18 @@ type RegistryItem struct {
 	AddedDate   string `yaml:"added_date,omitempty"`
 }
 
+// DefaultHost is the host of registry plugins that don't name one.
+const DefaultHost = "github.com"
+
+// Spec returns the plugin spec that declares i: the repo shorthand for
+// GitHub, or a full URL for other hosts.
+func (i RegistryItem) Spec() string {
+	if i.Host != "" && i.Host != DefaultHost {
+		return "https://" + i.Host + "/" + i.Repo
+	}
+	return i.Repo
+}
+
 // Parse deserializes raw YAML bytes into a Registry.
 func Parse(data []byte) (*Registry, error) {
 	var reg Registry
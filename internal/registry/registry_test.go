@@ -345,3 +345,19 @@ func TestSearch_SortedByStars(t *testing.T) {
 			results[0].Stars, results[1].Stars, results[2].Stars)
 	}
 }
+
+func TestRegistryItemSpec(t *testing.T) {
+	tests := []struct {
+		item RegistryItem
+		want string
+	}{
+		{RegistryItem{Repo: "catppuccin/tmux"}, "catppuccin/tmux"},
+		{RegistryItem{Repo: "catppuccin/tmux", Host: "github.com"}, "catppuccin/tmux"},
+		{RegistryItem{Repo: "user/plugin", Host: "codeberg.org"}, "https://codeberg.org/user/plugin"},
+	}
+	for _, tt := range tests {
+		if got := tt.item.Spec(); got != tt.want {
+			t.Errorf("%+v.Spec() = %q, want %q", tt.item, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constraint is a version range parsed from a plugin pin.
+//
+// Supported forms:
+//
+//	"2.1.0", "v2.1.0"  exactly that version
+//	"2", "2.1"         any version with that prefix (2.x.x, 2.1.x)
+//	"^2.1.0"           compatible: >=2.1.0 <3.0.0 (>=0.3.1 <0.4.0 for 0.x)
+//	"~2.1.0"           patch updates: >=2.1.0 <2.2.0
+type Constraint struct {
+	raw   string
+	lower Version
+	upper Version // exclusive; zero means the lower bound must match exactly
+	exact bool
+}
+
+// ParseConstraint parses a version constraint.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: s}
+	op := ""
+	rest := s
+	if strings.HasPrefix(s, "^") || strings.HasPrefix(s, "~") {
+		op, rest = s[:1], s[1:]
+	}
+
+	v, n, err := parse(rest)
+	if err != nil {
+		return Constraint{}, fmt.Errorf("invalid version constraint %q", s)
+	}
+	if n < 3 && v.Prerelease != "" {
+		return Constraint{}, fmt.Errorf("invalid version constraint %q", s)
+	}
+	c.lower = v
+
+	switch op {
+	case "^":
+		c.upper = caretUpper(v, n)
+	case "~":
+		c.upper = tildeUpper(v, n)
+	default:
+		if n == 3 {
+			c.exact = true
+		} else {
+			c.upper = tildeUpper(v, n)
+		}
+	}
+	return c, nil
+}
+
+// caretUpper bumps the left-most non-zero component that was specified.
+func caretUpper(v Version, n int) Version {
+	switch {
+	case v.Major > 0 || n == 1:
+		return Version{Major: v.Major + 1}
+	case v.Minor > 0 || n == 2:
+		return Version{Minor: v.Minor + 1}
+	default:
+		return Version{Patch: v.Patch + 1}
+	}
+}
+
+// tildeUpper allows changes below the last specified component, or patch
+// changes when all three were given.
+func tildeUpper(v Version, n int) Version {
+	if n == 1 {
+		return Version{Major: v.Major + 1}
+	}
+	return Version{Major: v.Major, Minor: v.Minor + 1}
+}
+
+// String returns the constraint as written.
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// Check reports whether v satisfies the constraint. Prereleases only
+// satisfy a constraint that names that exact prerelease.
+func (c Constraint) Check(v Version) bool {
+	if c.exact {
+		return v.Compare(c.lower) == 0
+	}
+	if v.Prerelease != "" {
+		return false
+	}
+	return v.Compare(c.lower) >= 0 && v.Compare(c.upper) < 0
+}
+
+// Latest returns the highest tag in tags that satisfies the constraint.
+// Tags that are not semantic versions are ignored.
+func (c Constraint) Latest(tags []string) (string, bool) {
+	var (
+		best    string
+		bestVer Version
+		found   bool
+	)
+	for _, tag := range tags {
+		v, err := Parse(tag)
+		if err != nil || !c.Check(v) {
+			continue
+		}
+		if !found || v.Compare(bestVer) > 0 {
+			best, bestVer, found = tag, v, true
+		}
+	}
+	return best, found
+}
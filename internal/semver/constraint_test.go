@@ -0,0 +1,88 @@
+package semver_test
+
+import (
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/semver"
+)
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"v2.1.0", "2.1.0", true},
+		{"2.1.0", "2.1.1", false},
+		{"2", "2.9.9", true},
+		{"2", "3.0.0", false},
+		{"2.1", "2.1.7", true},
+		{"2.1", "2.2.0", false},
+		{"^2", "2.0.0", true},
+		{"^2", "2.5.1", true},
+		{"^2", "3.0.0", false},
+		{"^2.1.0", "2.0.9", false},
+		{"^2.1.0", "2.9.0", true},
+		{"^0.3.1", "0.3.9", true},
+		{"^0.3.1", "0.4.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"~2.1.0", "2.1.5", true},
+		{"~2.1.0", "2.2.0", false},
+		{"~2", "2.7.0", true},
+		{"^2", "2.1.0-rc.1", false},
+		{"2.1.0-rc.1", "2.1.0-rc.1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+"_"+tt.version, func(t *testing.T) {
+			c, err := semver.ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("ParseConstraint(%q): %v", tt.constraint, err)
+			}
+			v, err := semver.Parse(tt.version)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.version, err)
+			}
+			if got := c.Check(v); got != tt.want {
+				t.Errorf("%s.Check(%s) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConstraintInvalid(t *testing.T) {
+	for _, in := range []string{"", "^", "~x", "main", ">=2", "2.1-rc.1"} {
+		if _, err := semver.ParseConstraint(in); err == nil {
+			t.Errorf("ParseConstraint(%q) expected error", in)
+		}
+	}
+}
+
+func TestConstraintLatest(t *testing.T) {
+	tags := []string{"v1.9.0", "v2.0.0", "v2.3.1", "v2.10.0", "v3.0.0-rc.1", "v3.0.0", "nightly"}
+
+	tests := []struct {
+		constraint string
+		want       string
+		found      bool
+	}{
+		{"^2", "v2.10.0", true},
+		{"~2.3", "v2.3.1", true},
+		{"^3", "v3.0.0", true},
+		{"v1.9.0", "v1.9.0", true},
+		{"^4", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			c, err := semver.ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, found := c.Latest(tags)
+			if got != tt.want || found != tt.found {
+				t.Errorf("Latest = (%q, %v), want (%q, %v)", got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
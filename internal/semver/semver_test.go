@@ -0,0 +1,66 @@
+package semver_test
+
+import (
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/semver"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"v2.1.0", "2.1.0", false},
+		{"2.1.0", "2.1.0", false},
+		{"v2.1", "2.1.0", false},
+		{"3", "3.0.0", false},
+		{"v3.0.0-rc.1", "3.0.0-rc.1", false},
+		{"1.2.3+build.5", "1.2.3", false},
+		{"", "", true},
+		{"latest", "", true},
+		{"1.2.3.4", "", true},
+		{"01.2.3", "", true},
+		{"1.2.3-", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			v, err := semver.Parse(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && v.String() != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.in, v, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.0", "2.0.0", -1},
+		{"1.2.0", "1.1.9", 1},
+		{"1.0.1", "1.0.0", 1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"1.0.0-rc.1", "1.0.0-rc", 1},
+		{"1.0.0-1", "1.0.0-alpha", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			a, _ := semver.Parse(tt.a)
+			b, _ := semver.Parse(tt.b)
+			if got := a.Compare(b); got != tt.want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
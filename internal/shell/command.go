@@ -0,0 +1,22 @@
+package shell
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// Command returns the command running name in its own process group, so
+// that canceling ctx kills it along with every process it started. Once it
+// exits, or is killed, it is given waitDelay to close its output, so a
+// background process it left holding stdout or stderr can't hold up Wait.
+func Command(ctx context.Context, waitDelay time.Duration, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
@@ -0,0 +1,79 @@
+// Package sourcelog keeps what each plugin's scripts wrote to stderr the
+// last time the plugin was sourced, so a broken plugin can be diagnosed.
+//
+// Logs are plain text, one file per plugin in the logs directory inside
+// the tpack state directory. A plugin whose scripts all succeeded quietly
+// has no log.
+package sourcelog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tmuxpack/tpack/internal/profile"
+)
+
+const logDir = "logs"
+
+// Path returns the log file of the named plugin inside statePath.
+func Path(statePath, name string) string {
+	return filepath.Join(statePath, logDir, name+".log")
+}
+
+// Write replaces the log of the named plugin with the outcome of scripts,
+// run at the given time. If every script succeeded without writing to
+// stderr, the log is removed instead.
+func Write(statePath, name string, at time.Time, scripts []profile.Script) error {
+	path := Path(statePath, name)
+	if quiet(scripts) {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(Format(at, scripts)), 0o644)
+}
+
+// Read returns the log of the named plugin, or "" if it has none.
+func Read(statePath, name string) (string, error) {
+	data, err := os.ReadFile(Path(statePath, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	return string(data), err
+}
+
+// Format renders scripts as a log: a header per script with its exit
+// status and duration, followed by what it wrote to stderr.
+func Format(at time.Time, scripts []profile.Script) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Sourced %s\n", at.Format(time.DateTime))
+	for _, s := range scripts {
+		status := fmt.Sprintf("exit %d", s.ExitCode)
+		if s.ExitCode < 0 {
+			status = s.Error
+		}
+		fmt.Fprintf(&b, "\n==> %s (%s, %s) <==\n", s.File, status, s.Duration.Round(time.Millisecond))
+		if s.Stderr != "" {
+			b.WriteString(strings.TrimRight(s.Stderr, "\n") + "\n")
+		}
+	}
+	return b.String()
+}
+
+// quiet reports whether all scripts succeeded without writing to stderr.
+func quiet(scripts []profile.Script) bool {
+	for _, s := range scripts {
+		if s.Failed() || strings.TrimSpace(s.Stderr) != "" {
+			return false
+		}
+	}
+	return true
+}
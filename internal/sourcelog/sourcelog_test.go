@@ -0,0 +1,70 @@
+package sourcelog_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tmuxpack/tpack/internal/profile"
+	"github.com/tmuxpack/tpack/internal/sourcelog"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+	scripts := []profile.Script{
+		{File: "cpu.tmux", Duration: 12 * time.Millisecond, ExitCode: 1, Stderr: "cpu: command not found\n"},
+		{File: "extra.tmux", Duration: 3 * time.Millisecond},
+		{File: "broken.tmux", ExitCode: -1, Error: "permission denied"},
+	}
+	if err := sourcelog.Write(dir, "tmux-cpu", at, scripts); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	got, err := sourcelog.Read(dir, "tmux-cpu")
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	want := `Sourced 2026-03-10 09:30:00
+
+==> cpu.tmux (exit 1, 12ms) <==
+cpu: command not found
+
+==> extra.tmux (exit 0, 3ms) <==
+
+==> broken.tmux (permission denied, 0s) <==
+`
+	if got != want {
+		t.Errorf("Read() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteQuietRemovesLog(t *testing.T) {
+	dir := t.TempDir()
+	failed := []profile.Script{{File: "a.tmux", ExitCode: 2}}
+	if err := sourcelog.Write(dir, "a", time.Now(), failed); err != nil {
+		t.Fatal(err)
+	}
+
+	ok := []profile.Script{{File: "a.tmux"}}
+	if err := sourcelog.Write(dir, "a", time.Now(), ok); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(sourcelog.Path(dir, "a")); !os.IsNotExist(err) {
+		t.Errorf("expected the log to be removed, stat error = %v", err)
+	}
+	if got, err := sourcelog.Read(dir, "a"); got != "" || err != nil {
+		t.Errorf("Read() = %q, %v; want nothing", got, err)
+	}
+}
+
+func TestWriteKeepsStderrOfSuccessfulScripts(t *testing.T) {
+	dir := t.TempDir()
+	scripts := []profile.Script{{File: "a.tmux", Stderr: "deprecated option\n"}}
+	if err := sourcelog.Write(dir, "a", time.Now(), scripts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(sourcelog.Path(dir, "a")); err != nil {
+		t.Errorf("expected a log for a script that wrote to stderr: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"syscall"
 	"time"
 
@@ -14,10 +15,91 @@ import (
 const stateFile = "state.yml"
 const lockFile = "state.lock"
 
+// maxRevisions bounds the per-plugin revision history.
+const maxRevisions = 20
+
 // State holds persistent tpack state.
 type State struct {
 	LastUpdateCheck     time.Time `yaml:"last_update_check"`
 	LastSelfUpdateCheck time.Time `yaml:"last_self_update_check"`
+	// Revisions holds, per plugin, the commits it was at before each
+	// update, oldest first.
+	Revisions map[string][]string `yaml:"revisions,omitempty"`
+	// Disabled lists the plugins turned off with `tpack disable`, sorted.
+	Disabled []string `yaml:"disabled,omitempty"`
+	// FailedBuilds lists the plugins whose last build failed, sorted.
+	FailedBuilds []string `yaml:"failed_builds,omitempty"`
+}
+
+// IsDisabled reports whether name was turned off with `tpack disable`.
+func (s State) IsDisabled(name string) bool {
+	_, found := slices.BinarySearch(s.Disabled, name)
+	return found
+}
+
+// SetDisabled turns name off, or back on.
+func (s *State) SetDisabled(name string, disabled bool) {
+	s.Disabled = setMember(s.Disabled, name, disabled)
+}
+
+// BuildFailed reports whether name's last build failed.
+func (s State) BuildFailed(name string) bool {
+	_, found := slices.BinarySearch(s.FailedBuilds, name)
+	return found
+}
+
+// SetBuildFailed records whether name's last build failed.
+func (s *State) SetBuildFailed(name string, failed bool) {
+	s.FailedBuilds = setMember(s.FailedBuilds, name, failed)
+}
+
+// setMember adds name to, or removes it from, the sorted list.
+func setMember(list []string, name string, member bool) []string {
+	i, found := slices.BinarySearch(list, name)
+	switch {
+	case member && !found:
+		return slices.Insert(list, i, name)
+	case !member && found:
+		return slices.Delete(list, i, i+1)
+	}
+	return list
+}
+
+// PushRevision appends commit to name's history, dropping the oldest
+// entries beyond maxRevisions.
+func (s *State) PushRevision(name, commit string) {
+	if s.Revisions == nil {
+		s.Revisions = make(map[string][]string)
+	}
+	revs := append(s.Revisions[name], commit)
+	if len(revs) > maxRevisions {
+		revs = revs[len(revs)-maxRevisions:]
+	}
+	s.Revisions[name] = revs
+}
+
+// LastRevision returns the most recently recorded revision for name.
+func (s State) LastRevision(name string) (string, bool) {
+	revs := s.Revisions[name]
+	if len(revs) == 0 {
+		return "", false
+	}
+	return revs[len(revs)-1], true
+}
+
+// PopRevision removes and returns the most recently recorded revision for name.
+func (s *State) PopRevision(name string) (string, bool) {
+	rev, ok := s.LastRevision(name)
+	if !ok {
+		return "", false
+	}
+	revs := s.Revisions[name][:len(s.Revisions[name])-1]
+	if len(revs) == 0 {
+		delete(s.Revisions, name)
+	} else {
+		s.Revisions[name] = revs
+	}
+	return rev, true
 }
 
 // Load reads state from statePath/state.yml.
@@ -34,6 +116,11 @@ func Load(statePath string) State {
 		fmt.Fprintf(os.Stderr, "tpack: warning: corrupt state file %s: %v\n", p, err)
 		return State{}
 	}
+	// The lists may have been edited by hand.
+	slices.Sort(s.Disabled)
+	s.Disabled = slices.Compact(s.Disabled)
+	slices.Sort(s.FailedBuilds)
+	s.FailedBuilds = slices.Compact(s.FailedBuilds)
 	return s
 }
 
@@ -76,3 +163,26 @@ func LoadAndSave(statePath string, fn func(*State)) error {
 	fn(&s)
 	return Save(statePath, s)
 }
+
+// SetDisabled turns the named plugins off, or back on, on disk.
+func SetDisabled(statePath string, disabled bool, names ...string) error {
+	return LoadAndSave(statePath, func(s *State) {
+		for _, name := range names {
+			s.SetDisabled(name, disabled)
+		}
+	})
+}
+
+// SetBuildFailed records on disk whether name's last build failed.
+func SetBuildFailed(statePath, name string, failed bool) error {
+	return LoadAndSave(statePath, func(s *State) {
+		s.SetBuildFailed(name, failed)
+	})
+}
+
+// RecordRevision appends commit to name's revision history on disk.
+func RecordRevision(statePath, name, commit string) error {
+	return LoadAndSave(statePath, func(s *State) {
+		s.PushRevision(name, commit)
+	})
+}
@@ -110,3 +110,99 @@ func TestSaveOverwritesExisting(t *testing.T) {
 		t.Errorf("LastUpdateCheck = %v, want %v", loaded.LastUpdateCheck, second)
 	}
 }
+
+func TestRevisionHistory(t *testing.T) {
+	var s state.State
+	if _, ok := s.PopRevision("foo"); ok {
+		t.Fatal("expected no revision on empty state")
+	}
+
+	s.PushRevision("foo", "aaa")
+	s.PushRevision("foo", "bbb")
+	s.PushRevision("bar", "ccc")
+
+	if rev, ok := s.LastRevision("foo"); !ok || rev != "bbb" {
+		t.Errorf("LastRevision = %q, %v, want bbb, true", rev, ok)
+	}
+	if rev, _ := s.PopRevision("foo"); rev != "bbb" {
+		t.Errorf("first PopRevision = %q, want bbb", rev)
+	}
+	if rev, _ := s.PopRevision("foo"); rev != "aaa" {
+		t.Errorf("second PopRevision = %q, want aaa", rev)
+	}
+	if _, ok := s.Revisions["foo"]; ok {
+		t.Error("expected empty history to be removed")
+	}
+	if rev, _ := s.LastRevision("bar"); rev != "ccc" {
+		t.Errorf("other plugin history = %q, want ccc", rev)
+	}
+}
+
+func TestRevisionHistoryIsBounded(t *testing.T) {
+	var s state.State
+	for i := range 30 {
+		s.PushRevision("foo", string(rune('a'+i)))
+	}
+	if got := len(s.Revisions["foo"]); got != 20 {
+		t.Fatalf("history length = %d, want 20", got)
+	}
+	if s.Revisions["foo"][0] != string(rune('a'+10)) {
+		t.Errorf("oldest kept = %q, want %q", s.Revisions["foo"][0], string(rune('a'+10)))
+	}
+}
+
+func TestRecordRevision(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "tpack")
+
+	if err := state.RecordRevision(statePath, "foo", "abc123"); err != nil {
+		t.Fatalf("RecordRevision failed: %v", err)
+	}
+	if err := state.RecordRevision(statePath, "foo", "def456"); err != nil {
+		t.Fatalf("RecordRevision failed: %v", err)
+	}
+
+	loaded := state.Load(statePath)
+	want := []string{"abc123", "def456"}
+	got := loaded.Revisions["foo"]
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Revisions[foo] = %v, want %v", got, want)
+	}
+}
+
+func TestSetDisabled(t *testing.T) {
+	dir := t.TempDir()
+	if err := state.SetDisabled(dir, true, "tmux-yank", "tmux-cpu", "tmux-yank"); err != nil {
+		t.Fatal(err)
+	}
+
+	s := state.Load(dir)
+	if len(s.Disabled) != 2 || s.Disabled[0] != "tmux-cpu" || s.Disabled[1] != "tmux-yank" {
+		t.Errorf("Disabled = %v, want sorted and without duplicates", s.Disabled)
+	}
+	if !s.IsDisabled("tmux-cpu") || s.IsDisabled("tmux-sensible") {
+		t.Errorf("IsDisabled reports the wrong plugins: %v", s.Disabled)
+	}
+
+	if err := state.SetDisabled(dir, false, "tmux-cpu", "tmux-sensible"); err != nil {
+		t.Fatal(err)
+	}
+	if s := state.Load(dir); len(s.Disabled) != 1 || s.IsDisabled("tmux-cpu") {
+		t.Errorf("Disabled = %v, want only tmux-yank", s.Disabled)
+	}
+}
+
+func TestLoadSortsDisabled(t *testing.T) {
+	dir := t.TempDir()
+	data := "disabled:\n  - tmux-yank\n  - tmux-cpu\n  - tmux-yank\n"
+	if err := os.WriteFile(filepath.Join(dir, "state.yml"), []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := state.Load(dir)
+	if !s.IsDisabled("tmux-cpu") || !s.IsDisabled("tmux-yank") {
+		t.Errorf("IsDisabled misses hand-edited entries: %v", s.Disabled)
+	}
+	if len(s.Disabled) != 2 {
+		t.Errorf("Disabled = %v, want duplicates dropped", s.Disabled)
+	}
+}
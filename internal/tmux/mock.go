@@ -10,6 +10,9 @@ type MockRunner struct {
 	Options     map[string]string
 	Environment map[string]string
 	WindowOpts  map[string]string
+	Keys        map[string]string
+	Formats     map[string]string
+	Hooks       map[string]string
 	VersionStr  string
 	Errors      map[string]error
 
@@ -29,6 +32,9 @@ func NewMockRunner() *MockRunner {
 		Options:     make(map[string]string),
 		Environment: make(map[string]string),
 		WindowOpts:  make(map[string]string),
+		Keys:        make(map[string]string),
+		Formats:     make(map[string]string),
+		Hooks:       make(map[string]string),
 		Errors:      make(map[string]error),
 	}
 }
@@ -71,6 +77,26 @@ func (m *MockRunner) BindKey(key, cmd, description string) error {
 	return m.err("BindKey:" + key)
 }
 
+func (m *MockRunner) SetHook(hook, cmd string) error {
+	m.record("SetHook", hook, cmd)
+	return m.err("SetHook:" + hook)
+}
+
+func (m *MockRunner) UnsetHook(hook string) error {
+	m.record("UnsetHook", hook)
+	return m.err("UnsetHook:" + hook)
+}
+
+func (m *MockRunner) UnbindKey(key string) error {
+	m.record("UnbindKey", key)
+	return m.err("UnbindKey:" + key)
+}
+
+func (m *MockRunner) ShowHooks(hook string) (string, error) {
+	m.record("ShowHooks", hook)
+	return m.Hooks[hook], m.err("ShowHooks:" + hook)
+}
+
 func (m *MockRunner) SourceFile(path string) error {
 	m.record("SourceFile", path)
 	return m.err("SourceFile:" + path)
@@ -111,3 +137,13 @@ func (m *MockRunner) SetOption(option, value string) error {
 	m.Options[option] = value
 	return m.err("SetOption:" + option)
 }
+
+func (m *MockRunner) ListKeys(table string) (string, error) {
+	m.record("ListKeys", table)
+	return m.Keys[table], m.err("ListKeys:" + table)
+}
+
+func (m *MockRunner) DisplayFormat(format string) (string, error) {
+	m.record("DisplayFormat", format)
+	return m.Formats[format], m.err("DisplayFormat:" + format)
+}
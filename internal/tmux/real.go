@@ -57,6 +57,32 @@ func (r *RealRunner) BindKey(key, cmd, description string) error {
 	return err
 }
 
+func (r *RealRunner) SetHook(hook, cmd string) error {
+	_, err := r.runTmux("set-hook", "-ga", hook, "run-shell "+quoteCommandArg(cmd))
+	return err
+}
+
+func (r *RealRunner) UnsetHook(hook string) error {
+	_, err := r.runTmux("set-hook", "-gu", hook)
+	return err
+}
+
+func (r *RealRunner) UnbindKey(key string) error {
+	_, err := r.runTmux("unbind-key", key)
+	return err
+}
+
+func (r *RealRunner) ShowHooks(hook string) (string, error) {
+	return r.runTmux("show-hooks", "-g", hook)
+}
+
+// quoteCommandArg double-quotes s as an argument in a tmux command string,
+// escaping the characters tmux would otherwise interpret.
+func quoteCommandArg(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`)
+	return `"` + r.Replace(s) + `"`
+}
+
 func (r *RealRunner) SourceFile(path string) error {
 	_, err := r.runTmux("source-file", path)
 	return err
@@ -94,3 +120,11 @@ func (r *RealRunner) SetOption(option, value string) error {
 	_, err := r.runTmux("set-option", "-gq", option, value)
 	return err
 }
+
+func (r *RealRunner) ListKeys(table string) (string, error) {
+	return r.runTmux("list-keys", "-T", table)
+}
+
+func (r *RealRunner) DisplayFormat(format string) (string, error) {
+	return r.runTmux("display-message", "-p", format)
+}
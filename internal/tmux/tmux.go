@@ -20,6 +20,24 @@ type Runner interface {
 	// Equivalent to: tmux bind-key [-N "description"] <key> run-shell <cmd>
 	BindKey(key, cmd, description string) error
 
+	// SetHook appends a global hook that runs a shell command.
+	// Equivalent to: tmux set-hook -ga <hook> "run-shell <cmd>"
+	SetHook(hook, cmd string) error
+
+	// UnsetHook removes a global hook, or one of its commands when hook is
+	// given with an index as "hook[index]".
+	// Equivalent to: tmux set-hook -gu <hook>
+	UnsetHook(hook string) error
+
+	// UnbindKey removes a prefix table key binding.
+	// Equivalent to: tmux unbind-key <key>
+	UnbindKey(key string) error
+
+	// ShowHooks returns the global commands set for a hook, one per line
+	// as "hook[index] command".
+	// Equivalent to: tmux show-hooks -g <hook>
+	ShowHooks(hook string) (string, error)
+
 	// SourceFile sources a tmux configuration file.
 	// Equivalent to: tmux source-file <path>
 	SourceFile(path string) error
@@ -50,4 +68,12 @@ type Runner interface {
 	// SetOption sets a tmux global option.
 	// Equivalent to: tmux set-option -gq <option> <value>
 	SetOption(option, value string) error
+
+	// ListKeys returns the key bindings in a key table, one per line.
+	// Equivalent to: tmux list-keys -T <table>
+	ListKeys(table string) (string, error)
+
+	// DisplayFormat expands a tmux format string.
+	// Equivalent to: tmux display-message -p <format>
+	DisplayFormat(format string) (string, error)
 }
@@ -0,0 +1,184 @@
+// Package tmuxconf parses tmux configuration files into commands, following
+// tmux's own quoting, escaping, and line-continuation rules.
+//
+// Environment variables and ~ are left unexpanded in word values: their
+// meaning depends on the tmux server's environment, not the parser's.
+package tmuxconf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pos is a position in a configuration file. Line is 1-based.
+type Pos struct {
+	File string
+	Line int
+}
+
+func (p Pos) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("line %d", p.Line)
+	}
+	return fmt.Sprintf("%s:%d", p.File, p.Line)
+}
+
+// Word is a single command argument after quotes and escapes are removed.
+type Word struct {
+	Value string
+	Pos   Pos
+	// Start and End are byte offsets of the word in the source, End exclusive.
+	Start, End int
+	// Block holds the parsed commands of a { ... } argument. Value is then
+	// the source text between the braces.
+	Block []Command
+}
+
+// Command is a tmux command and its arguments, e.g. `set -g @plugin foo`.
+type Command struct {
+	Name string
+	Args []Word
+	Pos  Pos
+	// EndLine is the last line the command spans, after line continuations.
+	EndLine int
+	// Start and End are byte offsets of the command in the source, from its
+	// name to the end of its last argument. Separators and comments are not
+	// included.
+	Start, End int
+}
+
+// File is a parsed configuration file.
+type File struct {
+	Name     string
+	Commands []Command
+}
+
+// aliases maps tmux's command aliases to the full command name.
+var aliases = map[string]string{
+	"bind":    "bind-key",
+	"if":      "if-shell",
+	"run":     "run-shell",
+	"set":     "set-option",
+	"setw":    "set-window-option",
+	"show":    "show-options",
+	"source":  "source-file",
+	"unbind":  "unbind-key",
+	"display": "display-message",
+}
+
+// Is reports whether c runs the named command, directly or by an alias.
+func (c *Command) Is(name string) bool {
+	if full, ok := aliases[c.Name]; ok {
+		return full == name
+	}
+	return c.Name == name
+}
+
+// Values returns the values of c's arguments.
+func (c *Command) Values() []string {
+	vals := make([]string, len(c.Args))
+	for i, w := range c.Args {
+		vals[i] = w.Value
+	}
+	return vals
+}
+
+// Flags splits c's arguments the way tmux's getopt does: leading words of
+// the form -abc are flag clusters, "--" ends them, and a flag letter listed
+// in valued takes the next word as its value. Boolean flags map to "".
+func (c *Command) Flags(valued string) (flags map[byte]string, args []string) {
+	flags = make(map[byte]string)
+	vals := c.Values()
+	i := 0
+	for ; i < len(vals); i++ {
+		v := vals[i]
+		if v == "--" {
+			i++
+			break
+		}
+		if len(v) < 2 || v[0] != '-' {
+			break
+		}
+		for j := 1; j < len(v); j++ {
+			if !strings.ContainsRune(valued, rune(v[j])) {
+				flags[v[j]] = ""
+				continue
+			}
+			// The value is the rest of the cluster, or the next word.
+			if j+1 < len(v) {
+				flags[v[j]] = v[j+1:]
+			} else if i+1 < len(vals) {
+				i++
+				flags[v[j]] = vals[i]
+			}
+			break
+		}
+	}
+	return flags, vals[i:]
+}
+
+// Decl is a value declared by a configuration command.
+type Decl struct {
+	Value   string
+	Command *Command
+}
+
+// PluginOption is the user option tmux plugin managers read plugin specs from.
+const PluginOption = "@plugin"
+
+// PluginSpec returns the spec c declares if it is a `set-option @plugin`
+// command. Appending (-a) counts as a declaration; unsetting (-u) does not.
+func (c *Command) PluginSpec() (string, bool) {
+	if !c.Is("set-option") {
+		return "", false
+	}
+	flags, args := c.Flags("t")
+	if _, unset := flags['u']; unset || len(args) < 2 || args[0] != PluginOption {
+		return "", false
+	}
+	spec := strings.TrimSpace(args[1])
+	return spec, spec != ""
+}
+
+// SourcePaths returns the paths c loads if it is a source-file command.
+// format reports whether they are tmux formats (-F) to expand first.
+func (c *Command) SourcePaths() (paths []string, format bool) {
+	if !c.Is("source-file") {
+		return nil, false
+	}
+	flags, args := c.Flags("t")
+	_, format = flags['F']
+	for _, path := range args {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, format
+}
+
+// Plugins returns the spec of every top-level @plugin declaration in f,
+// in order.
+func (f *File) Plugins() []Decl {
+	var decls []Decl
+	for i := range f.Commands {
+		c := &f.Commands[i]
+		if spec, ok := c.PluginSpec(); ok {
+			decls = append(decls, Decl{Value: spec, Command: c})
+		}
+	}
+	return decls
+}
+
+// SourcedFiles returns every path passed to a top-level source-file
+// command in f, in order.
+func (f *File) SourcedFiles() []Decl {
+	var decls []Decl
+	for i := range f.Commands {
+		c := &f.Commands[i]
+		paths, _ := c.SourcePaths()
+		for _, path := range paths {
+			decls = append(decls, Decl{Value: path, Command: c})
+		}
+	}
+	return decls
+}
@@ -0,0 +1,91 @@
+package tmuxconf_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/tmuxconf"
+)
+
+func parse(t *testing.T, src string) *tmuxconf.File {
+	t.Helper()
+	f, err := tmuxconf.Parse("tmux.conf", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return f
+}
+
+func values(decls []tmuxconf.Decl) []string {
+	var out []string
+	for _, d := range decls {
+		out = append(out, d.Value)
+	}
+	return out
+}
+
+func TestCommandIs(t *testing.T) {
+	f := parse(t, "set -g a b\nset-option -g a b\nsetw -g a b\nsource x")
+	if !f.Commands[0].Is("set-option") || !f.Commands[1].Is("set-option") {
+		t.Error("expected set and set-option to be set-option")
+	}
+	if f.Commands[2].Is("set-option") {
+		t.Error("setw is not set-option")
+	}
+	if !f.Commands[3].Is("source-file") {
+		t.Error("expected source to be source-file")
+	}
+}
+
+func TestCommandFlags(t *testing.T) {
+	tests := []struct {
+		src   string
+		flags map[byte]string
+		args  []string
+	}{
+		{"set -g @plugin x", map[byte]string{'g': ""}, []string{"@plugin", "x"}},
+		{"set -gqa @plugin x", map[byte]string{'g': "", 'q': "", 'a': ""}, []string{"@plugin", "x"}},
+		{"set -g -t main @plugin x", map[byte]string{'g': "", 't': "main"}, []string{"@plugin", "x"}},
+		{"set -tmain -g @plugin x", map[byte]string{'t': "main", 'g': ""}, []string{"@plugin", "x"}},
+		{"set -g -- -weird x", map[byte]string{'g': ""}, []string{"-weird", "x"}},
+		{"set -g - x", map[byte]string{'g': ""}, []string{"-", "x"}},
+	}
+	for _, tt := range tests {
+		c := parse(t, tt.src).Commands[0]
+		flags, args := c.Flags("t")
+		if !reflect.DeepEqual(flags, tt.flags) || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%q: got %v %q, want %v %q", tt.src, flags, args, tt.flags, tt.args)
+		}
+	}
+}
+
+func TestFilePlugins(t *testing.T) {
+	src := `set -g @plugin 'tmux-plugins/tpm'
+set -ga @plugin "tmux-plugins/tmux-sensible"
+set-option -gq @plugin tmux-plugins/tmux-yank # clipboard
+set -g @plugin a \
+    ; set -g @plugin 'b alias=bee'
+set -gu @plugin
+set -g @plugin ''
+set -g @plugin-option foo
+setw -g @plugin nope
+# set -g @plugin commented/out
+`
+	got := values(parse(t, src).Plugins())
+	want := []string{"tmux-plugins/tpm", "tmux-plugins/tmux-sensible", "tmux-plugins/tmux-yank", "a", "b alias=bee"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestFileSourcedFiles(t *testing.T) {
+	src := `source ~/.tmux/theme.conf
+source-file -q "$XDG_CONFIG_HOME/tmux/local.conf" ~/.tmux/extra.conf
+source-file -t main -F '#{d:current_file}/keys.conf'
+`
+	got := values(parse(t, src).SourcedFiles())
+	want := []string{"~/.tmux/theme.conf", "$XDG_CONFIG_HOME/tmux/local.conf", "~/.tmux/extra.conf", "#{d:current_file}/keys.conf"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,101 @@
+package tmuxconf
+
+import (
+	"bytes"
+	"strings"
+)
+
+// RemoveCommand returns a copy of src, the source c was parsed from, with c
+// deleted. A command alone on its lines is removed along with the lines and
+// any trailing comment; otherwise only the command and one adjacent ";"
+// separator are removed, leaving the rest of the line intact.
+func RemoveCommand(src []byte, c *Command) []byte {
+	lineStart, lineEnd := lines(src, c)
+	after := bytes.TrimLeft(src[c.End:lineEnd], " \t\r")
+
+	start, end := c.Start, c.End
+	switch {
+	case alone(src, c):
+		start, end = lineStart, lineEnd
+		if end < len(src) {
+			end++ // the newline
+		}
+	case len(after) > 0 && after[0] == ';':
+		// Drop the separator after c; what follows moves into its place.
+		end = lineEnd - len(bytes.TrimLeft(after[1:], " \t"))
+	default:
+		// Drop the separator before c, and the blanks around it.
+		if trimmed := bytes.TrimRight(src[lineStart:c.Start], " \t"); bytes.HasSuffix(trimmed, []byte(";")) {
+			start = lineStart + len(bytes.TrimRight(trimmed[:len(trimmed)-1], " \t"))
+		}
+	}
+
+	out := make([]byte, 0, len(src)-(end-start))
+	out = append(out, src[:start]...)
+	return append(out, src[end:]...)
+}
+
+// CommentCommand returns a copy of src, the source c was parsed from, with c
+// commented out. A command alone on its lines has each line prefixed with
+// "# " after its indentation; a command sharing a line with others is
+// removed from it and kept as a comment on its own line above.
+func CommentCommand(src []byte, c *Command) []byte {
+	lineStart, lineEnd := lines(src, c)
+
+	var out []byte
+	if alone(src, c) {
+		out = append(out, src[:lineStart]...)
+		for i, line := range bytes.Split(src[lineStart:lineEnd], []byte("\n")) {
+			if i > 0 {
+				out = append(out, '\n')
+			}
+			rest := bytes.TrimLeft(line, " \t")
+			out = append(out, line[:len(line)-len(rest)]...)
+			out = append(out, "# "...)
+			out = append(out, rest...)
+		}
+		return append(out, src[lineEnd:]...)
+	}
+
+	before := src[lineStart:c.Start]
+	prefix := string(before[:len(before)-len(bytes.TrimLeft(before, " \t"))]) + "# "
+	text := strings.ReplaceAll(string(src[c.Start:c.End]), "\n", "\n"+prefix)
+
+	removed := RemoveCommand(src, c)
+	out = append(out, removed[:lineStart]...)
+	out = append(out, prefix+text+Newline(src)...)
+	return append(out, removed[lineStart:]...)
+}
+
+// Newline returns the line ending src uses: "\r\n" if its first line ends
+// with one, otherwise "\n".
+func Newline(src []byte) string {
+	if i := bytes.IndexByte(src, '\n'); i > 0 && src[i-1] == '\r' {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// lines returns the offsets of the start of c's first line and the end of
+// its last line, excluding the newline.
+func lines(src []byte, c *Command) (start, end int) {
+	start = bytes.LastIndexByte(src[:c.Start], '\n') + 1
+	end = len(src)
+	if i := bytes.IndexByte(src[c.End:], '\n'); i >= 0 {
+		end = c.End + i
+	}
+	return start, end
+}
+
+// alone reports whether c is the only command on its lines, ignoring a
+// trailing comment.
+func alone(src []byte, c *Command) bool {
+	lineStart, lineEnd := lines(src, c)
+	before := bytes.TrimLeft(src[lineStart:c.Start], " \t")
+	after := bytes.TrimLeft(src[c.End:lineEnd], " \t\r")
+	return len(before) == 0 && (len(after) == 0 || isComment(after))
+}
+
+func isComment(b []byte) bool {
+	return b[0] == '#' && (len(b) == 1 || b[1] != '{')
+}
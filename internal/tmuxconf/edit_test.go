@@ -0,0 +1,120 @@
+package tmuxconf_test
+
+import (
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/tmuxconf"
+)
+
+func TestRemoveCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "whole line",
+			src:  "set -g @plugin a\nset -g @plugin target\nset -g @plugin b\n",
+			want: "set -g @plugin a\nset -g @plugin b\n",
+		},
+		{
+			name: "trailing comment goes with the line",
+			src:  "set -g @plugin a\n  set -g @plugin target # theme\n",
+			want: "set -g @plugin a\n",
+		},
+		{
+			name: "last line without newline",
+			src:  "set -g @plugin a\nset -g @plugin target",
+			want: "set -g @plugin a\n",
+		},
+		{
+			name: "continued lines",
+			src:  "set -g \\\n  @plugin \\\n  target\nset -g mouse on\n",
+			want: "set -g mouse on\n",
+		},
+		{
+			name: "first of several on a line",
+			src:  "set -g @plugin target ; set -g @plugin b\n",
+			want: "set -g @plugin b\n",
+		},
+		{
+			name: "middle of several on a line",
+			src:  "set -g @plugin a ; set -g @plugin target; set -g @plugin b\n",
+			want: "set -g @plugin a ; set -g @plugin b\n",
+		},
+		{
+			name: "last of several on a line",
+			src:  "set -g @plugin a ; set -g @plugin target # note\n",
+			want: "set -g @plugin a # note\n",
+		},
+		{
+			name: "crlf",
+			src:  "set -g @plugin target\r\nset -g @plugin b\r\n",
+			want: "set -g @plugin b\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := parse(t, tt.src)
+			for _, d := range f.Plugins() {
+				if d.Value == "target" {
+					got := string(tmuxconf.RemoveCommand([]byte(tt.src), d.Command))
+					if got != tt.want {
+						t.Errorf("got %q\nwant %q", got, tt.want)
+					}
+					return
+				}
+			}
+			t.Fatal("target not found")
+		})
+	}
+}
+
+func TestCommentCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "whole line keeps indentation and comment",
+			src:  "set -g @plugin a\n  set -g @plugin 'target' # theme\n",
+			want: "set -g @plugin a\n  # set -g @plugin 'target' # theme\n",
+		},
+		{
+			name: "continued lines",
+			src:  "set -g \\\n  @plugin target\nset -g mouse on",
+			want: "# set -g \\\n  # @plugin target\nset -g mouse on",
+		},
+		{
+			name: "shared line moves to its own line",
+			src:  "  set -g @plugin a ; set -g @plugin target\n",
+			want: "  # set -g @plugin target\n  set -g @plugin a\n",
+		},
+		{
+			name: "crlf",
+			src:  "set -g @plugin target; set -g @plugin b\r\n",
+			want: "# set -g @plugin target\r\nset -g @plugin b\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := parse(t, tt.src)
+			for _, d := range f.Plugins() {
+				if d.Value == "target" {
+					got := string(tmuxconf.CommentCommand([]byte(tt.src), d.Command))
+					if got != tt.want {
+						t.Errorf("got %q\nwant %q", got, tt.want)
+					}
+					if decls := parse(t, got).Plugins(); len(decls) > 0 && decls[len(decls)-1].Value == "target" {
+						t.Errorf("target still declared in %q", got)
+					}
+					return
+				}
+			}
+			t.Fatal("target not found")
+		})
+	}
+}
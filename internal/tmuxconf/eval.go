@@ -0,0 +1,127 @@
+package tmuxconf
+
+// Evaluator decides the conditions that guard configuration commands.
+// Each method returns ok=false when it can't decide; every branch of that
+// condition is then treated as active.
+type Evaluator interface {
+	// Format reports whether a tmux format expands to a true value, as
+	// used by %if, %elif, and if-shell -F.
+	Format(format string) (result, ok bool)
+	// Shell reports whether a shell command succeeds, as used by if-shell.
+	Shell(command string) (result, ok bool)
+}
+
+// Active returns the commands of f that tmux would run: %if directives are
+// resolved with ev and removed, and each if-shell is replaced by the
+// commands of the branch ev selects. Commands parsed from a quoted if-shell
+// branch take the position and offsets of that branch argument.
+func (f *File) Active(ev Evaluator) *File {
+	return &File{Name: f.Name, Commands: active(f.Commands, ev)}
+}
+
+// branch tracks one %if ... %endif directive while walking commands.
+type branch struct {
+	outer bool // the code around the directive runs
+	on    bool // the current branch runs
+	taken bool // an earlier branch was chosen, ruling this one out
+}
+
+func active(cmds []Command, ev Evaluator) []Command {
+	var out []Command
+	var stack []branch
+	on := true
+
+	// choose updates b for a branch guarded by c's condition.
+	choose := func(b *branch, c *Command) {
+		b.on = false
+		if !b.outer || b.taken {
+			return
+		}
+		result, ok := false, true
+		if len(c.Args) > 0 {
+			result, ok = ev.Format(c.Args[0].Value)
+		}
+		b.on = result || !ok
+		b.taken = result && ok
+	}
+
+	for i := range cmds {
+		c := &cmds[i]
+		switch c.Name {
+		case "%if":
+			b := branch{outer: on}
+			choose(&b, c)
+			stack = append(stack, b)
+		case "%elif":
+			if len(stack) > 0 {
+				choose(&stack[len(stack)-1], c)
+			}
+		case "%else":
+			if len(stack) > 0 {
+				b := &stack[len(stack)-1]
+				b.on = b.outer && !b.taken
+			}
+		case "%endif":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		default:
+			if !on {
+				continue
+			}
+			if c.Is("if-shell") {
+				out = append(out, ifShell(c, ev)...)
+				continue
+			}
+			out = append(out, *c)
+			continue
+		}
+
+		on = true
+		if len(stack) > 0 {
+			on = stack[len(stack)-1].on
+		}
+	}
+	return out
+}
+
+// ifShell returns the active commands of the branch an if-shell selects.
+func ifShell(c *Command, ev Evaluator) []Command {
+	flags, args := c.Flags("t")
+	if len(args) < 2 {
+		return nil
+	}
+	words := c.Args[len(c.Args)-len(args):]
+
+	var result, ok bool
+	if _, format := flags['F']; format {
+		result, ok = ev.Format(words[0].Value)
+	} else {
+		result, ok = ev.Shell(words[0].Value)
+	}
+
+	var chosen []Word
+	switch {
+	case !ok:
+		chosen = words[1:min(len(words), 3)]
+	case result:
+		chosen = words[1:2]
+	case len(words) > 2:
+		chosen = words[2:3]
+	}
+
+	var out []Command
+	for _, w := range chosen {
+		if w.Block != nil {
+			out = append(out, active(w.Block, ev)...)
+			continue
+		}
+		f, _ := Parse(w.Pos.File, []byte(w.Value))
+		for _, sub := range active(f.Commands, ev) {
+			sub.Pos, sub.EndLine = w.Pos, w.Pos.Line
+			sub.Start, sub.End = w.Start, w.End
+			out = append(out, sub)
+		}
+	}
+	return out
+}
@@ -0,0 +1,123 @@
+package tmuxconf_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+// fakeEvaluator answers conditions from maps; anything missing is undecided.
+type fakeEvaluator struct {
+	formats map[string]bool
+	shell   map[string]bool
+}
+
+func (e fakeEvaluator) Format(format string) (bool, bool) {
+	r, ok := e.formats[format]
+	return r, ok
+}
+
+func (e fakeEvaluator) Shell(command string) (bool, bool) {
+	r, ok := e.shell[command]
+	return r, ok
+}
+
+func TestActiveDirectives(t *testing.T) {
+	src := `set -g @plugin always
+%if "#{==:#{host},work}"
+set -g @plugin work
+%elif "#{==:#{host},home}"
+set -g @plugin home
+  %if "#{m:*3.4*,#{version}}"
+  set -g @plugin new-tmux
+  %else
+  set -g @plugin old-tmux
+  %endif
+%else
+set -g @plugin elsewhere
+%endif
+set -g @plugin after
+`
+	tests := []struct {
+		name    string
+		formats map[string]bool
+		want    []string
+	}{
+		{
+			name:    "first branch",
+			formats: map[string]bool{"#{==:#{host},work}": true},
+			want:    []string{"always", "work", "after"},
+		},
+		{
+			name: "elif with nested else",
+			formats: map[string]bool{
+				"#{==:#{host},work}":    false,
+				"#{==:#{host},home}":    true,
+				"#{m:*3.4*,#{version}}": false,
+			},
+			want: []string{"always", "home", "old-tmux", "after"},
+		},
+		{
+			name:    "else",
+			formats: map[string]bool{"#{==:#{host},work}": false, "#{==:#{host},home}": false},
+			want:    []string{"always", "elsewhere", "after"},
+		},
+		{
+			name:    "undecided conditions keep every branch",
+			formats: map[string]bool{},
+			want:    []string{"always", "work", "home", "new-tmux", "old-tmux", "elsewhere", "after"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := parse(t, src).Active(fakeEvaluator{formats: tt.formats})
+			if got := values(f.Plugins()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActiveIfShell(t *testing.T) {
+	src := `if-shell 'uname | grep -q Darwin' {
+  set -g @plugin mac
+} {
+  set -g @plugin linux
+}
+if -b 'test -n "$SSH_TTY"' 'set -g @plugin remote ; set -g @plugin remote2'
+if-shell -F '#{==:#{host},work}' "set -g @plugin work" "set -g @plugin personal"
+if-shell 'nested' {
+  if-shell -F '#{inner}' { set -g @plugin inner }
+}
+`
+	ev := fakeEvaluator{
+		formats: map[string]bool{"#{==:#{host},work}": false, "#{inner}": true},
+		shell: map[string]bool{
+			"uname | grep -q Darwin": false,
+			`test -n "$SSH_TTY"`:     true,
+			"nested":                 true,
+		},
+	}
+
+	f := parse(t, src).Active(ev)
+
+	want := []string{"linux", "remote", "remote2", "personal", "inner"}
+	if got := values(f.Plugins()); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+
+	// Commands from a quoted branch point at the if-shell line.
+	if decls := f.Plugins(); decls[1].Command.Pos.Line != 6 {
+		t.Errorf("expected quoted branch command on line 6, got %d", decls[1].Command.Pos.Line)
+	}
+}
+
+func TestActiveIfShellUndecided(t *testing.T) {
+	src := "if-shell 'true' 'set -g @plugin a' 'set -g @plugin b'\n"
+
+	f := parse(t, src).Active(fakeEvaluator{})
+
+	if got := values(f.Plugins()); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("expected both branches, got %q", got)
+	}
+}
@@ -0,0 +1,292 @@
+package tmuxconf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is a syntax error at a position in a configuration file.
+type Error struct {
+	Pos Pos
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return e.Pos.String() + ": " + e.Msg
+}
+
+// Parse parses the configuration in src. name is recorded in positions and
+// errors. Like tmux, the first syntax error stops parsing; the commands
+// before it are still returned.
+func Parse(name string, src []byte) (*File, error) {
+	p := &parser{name: name, src: src, line: 1}
+	cmds, err := p.commands(false)
+	return &File{Name: name, Commands: cmds}, err
+}
+
+type parser struct {
+	name string
+	src  []byte
+	off  int
+	line int
+}
+
+func (p *parser) eof() bool { return p.off >= len(p.src) }
+
+// peek returns the byte n positions ahead, or 0 past the end.
+func (p *parser) peek(n int) byte {
+	if p.off+n >= len(p.src) {
+		return 0
+	}
+	return p.src[p.off+n]
+}
+
+func (p *parser) next() byte {
+	c := p.src[p.off]
+	p.off++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+func (p *parser) pos() Pos { return Pos{File: p.name, Line: p.line} }
+
+func (p *parser) errorf(format string, args ...any) error {
+	return &Error{Pos: p.pos(), Msg: fmt.Sprintf(format, args...)}
+}
+
+// skipSpace skips blanks and backslash-newline continuations.
+func (p *parser) skipSpace() {
+	for !p.eof() {
+		switch c := p.peek(0); {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.next()
+		case c == '\\' && p.peek(1) == '\n':
+			p.next()
+			p.next()
+		default:
+			return
+		}
+	}
+}
+
+// atComment reports whether a comment starts here. "#{" begins a format,
+// not a comment.
+func (p *parser) atComment() bool {
+	return p.peek(0) == '#' && p.peek(1) != '{'
+}
+
+// atBrace reports whether a lone brace, used to delimit command blocks,
+// starts here. A brace followed by other characters is an ordinary word.
+func (p *parser) atBrace(brace byte) bool {
+	if p.peek(0) != brace {
+		return false
+	}
+	switch p.peek(1) {
+	case 0, ' ', '\t', '\r', '\n', ';':
+		return true
+	}
+	return false
+}
+
+func (p *parser) skipComment() {
+	for !p.eof() && p.peek(0) != '\n' {
+		p.next()
+	}
+}
+
+// commands parses commands until the end of input or, inside a block, the
+// closing brace.
+func (p *parser) commands(inBlock bool) ([]Command, error) {
+	var cmds []Command
+	for {
+		p.skipSpace()
+		switch {
+		case p.eof():
+			if inBlock {
+				return cmds, p.errorf("unterminated {")
+			}
+			return cmds, nil
+		case p.peek(0) == '\n' || p.peek(0) == ';':
+			p.next()
+			continue
+		case p.atComment():
+			p.skipComment()
+			continue
+		case inBlock && p.atBrace('}'):
+			p.next()
+			return cmds, nil
+		}
+
+		cmd, err := p.command(inBlock)
+		if err != nil {
+			return cmds, err
+		}
+		cmds = append(cmds, cmd)
+	}
+}
+
+// command parses one command up to a newline, separator, comment, or
+// closing brace, none of which it consumes.
+func (p *parser) command(inBlock bool) (Command, error) {
+	cmd := Command{Pos: p.pos(), Start: p.off}
+	first := true
+	for {
+		p.skipSpace()
+		if p.eof() || p.peek(0) == '\n' || p.peek(0) == ';' || p.atComment() {
+			break
+		}
+		if inBlock && p.atBrace('}') {
+			break
+		}
+		w, err := p.word()
+		if err != nil {
+			return cmd, err
+		}
+		if first {
+			cmd.Name = w.Value
+			first = false
+		} else {
+			cmd.Args = append(cmd.Args, w)
+		}
+		cmd.End = w.End
+		cmd.EndLine = p.line
+	}
+	return cmd, nil
+}
+
+// word parses a single argument, joining adjacent quoted and unquoted parts.
+func (p *parser) word() (Word, error) {
+	w := Word{Pos: p.pos(), Start: p.off}
+
+	if p.atBrace('{') {
+		p.next()
+		inner := p.off
+		block, err := p.commands(true)
+		if err != nil {
+			return w, err
+		}
+		// commands consumed the closing brace.
+		w.Value = strings.TrimSpace(string(p.src[inner : p.off-1]))
+		w.Block = block
+		w.End = p.off
+		return w, nil
+	}
+
+	var b strings.Builder
+	for !p.eof() {
+		c := p.peek(0)
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == ';':
+			w.Value, w.End = b.String(), p.off
+			return w, nil
+		case c == '\\':
+			p.next()
+			if p.eof() {
+				b.WriteByte('\\')
+				continue
+			}
+			if p.peek(0) == '\n' {
+				// A continuation inside a word joins the lines.
+				p.next()
+				continue
+			}
+			b.WriteByte(unescape(p.next()))
+		case c == '\'':
+			if err := p.singleQuoted(&b); err != nil {
+				return w, err
+			}
+		case c == '"':
+			if err := p.doubleQuoted(&b); err != nil {
+				return w, err
+			}
+		case c == '#' && p.peek(1) == '{':
+			if err := p.format(&b); err != nil {
+				return w, err
+			}
+		default:
+			b.WriteByte(p.next())
+		}
+	}
+	w.Value, w.End = b.String(), p.off
+	return w, nil
+}
+
+// singleQuoted copies a '...' string verbatim; nothing is escaped inside.
+func (p *parser) singleQuoted(b *strings.Builder) error {
+	start := p.pos()
+	p.next()
+	for !p.eof() {
+		c := p.next()
+		if c == '\'' {
+			return nil
+		}
+		b.WriteByte(c)
+	}
+	return &Error{Pos: start, Msg: "unterminated single quote"}
+}
+
+// doubleQuoted copies a "..." string, processing backslash escapes.
+func (p *parser) doubleQuoted(b *strings.Builder) error {
+	start := p.pos()
+	p.next()
+	for !p.eof() {
+		c := p.next()
+		switch c {
+		case '"':
+			return nil
+		case '\\':
+			if p.eof() {
+				break
+			}
+			if p.peek(0) == '\n' {
+				p.next()
+				continue
+			}
+			b.WriteByte(unescape(p.next()))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return &Error{Pos: start, Msg: "unterminated double quote"}
+}
+
+// format copies a #{...} format verbatim, including nested formats and
+// backslash-escaped characters, so it can be expanded later.
+func (p *parser) format(b *strings.Builder) error {
+	start := p.pos()
+	depth := 0
+	for !p.eof() {
+		c := p.next()
+		b.WriteByte(c)
+		switch {
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		case c == '\\' && !p.eof():
+			b.WriteByte(p.next())
+		}
+	}
+	return &Error{Pos: start, Msg: "unterminated #{"}
+}
+
+// unescape returns the character a backslash escape stands for.
+func unescape(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'e':
+		return '\x1b'
+	default:
+		return c
+	}
+}
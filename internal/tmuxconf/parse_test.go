@@ -0,0 +1,219 @@
+package tmuxconf_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/tmuxpack/tpack/internal/tmuxconf"
+)
+
+// words flattens each command to its name followed by its argument values.
+func words(f *tmuxconf.File) [][]string {
+	var out [][]string
+	for _, c := range f.Commands {
+		out = append(out, append([]string{c.Name}, c.Values()...))
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want [][]string
+	}{
+		{
+			name: "simple command",
+			src:  "set -g mouse on\n",
+			want: [][]string{{"set", "-g", "mouse", "on"}},
+		},
+		{
+			name: "quotes",
+			src:  `set -g @plugin "foo'bar" 'a"b' "x\"y" 'c\d'`,
+			want: [][]string{{"set", "-g", "@plugin", "foo'bar", `a"b`, `x"y`, `c\d`}},
+		},
+		{
+			name: "adjacent quoted and unquoted parts join",
+			src:  `set -g status-left pre"fix "'and 'post`,
+			want: [][]string{{"set", "-g", "status-left", "prefix and post"}},
+		},
+		{
+			name: "unquoted escapes",
+			src:  `bind x send-keys a\ b \; display hi`,
+			want: [][]string{{"bind", "x", "send-keys", "a b", ";", "display", "hi"}},
+		},
+		{
+			name: "double-quoted escapes",
+			src:  `display "tab\there\\"`,
+			want: [][]string{{"display", "tab\there\\"}},
+		},
+		{
+			name: "comments",
+			src:  "# whole line\nset -g mouse on # trailing\n  # indented\n",
+			want: [][]string{{"set", "-g", "mouse", "on"}},
+		},
+		{
+			name: "hash inside a word is not a comment",
+			src:  "set -g @colour red#1",
+			want: [][]string{{"set", "-g", "@colour", "red#1"}},
+		},
+		{
+			name: "formats are kept verbatim",
+			src:  `set -g status-right #{?client_prefix,#[bg=red] P ,}#{pane_title} # done`,
+			want: [][]string{{"set", "-g", "status-right", "#{?client_prefix,#[bg=red] P ,}#{pane_title}"}},
+		},
+		{
+			name: "semicolon separates commands",
+			src:  "set -g @plugin a; set -g @plugin b ;set -g @plugin c",
+			want: [][]string{
+				{"set", "-g", "@plugin", "a"},
+				{"set", "-g", "@plugin", "b"},
+				{"set", "-g", "@plugin", "c"},
+			},
+		},
+		{
+			name: "backslash continues a line",
+			src:  "set -g \\\n  @plugin \\\n  'tmux-plugins/tmux-yank'\nset -g mouse on",
+			want: [][]string{
+				{"set", "-g", "@plugin", "tmux-plugins/tmux-yank"},
+				{"set", "-g", "mouse", "on"},
+			},
+		},
+		{
+			name: "crlf line endings",
+			src:  "set -g mouse on\r\nset -g base-index 1\r\n",
+			want: [][]string{{"set", "-g", "mouse", "on"}, {"set", "-g", "base-index", "1"}},
+		},
+		{
+			name: "quoted newline stays in the value",
+			src:  "display 'a\nb'",
+			want: [][]string{{"display", "a\nb"}},
+		},
+		{
+			name: "directives parse as commands",
+			src:  "%if #{>=:#{version},3.2}\nset -g @plugin a\n%endif",
+			want: [][]string{
+				{"%if", "#{>=:#{version},3.2}"},
+				{"set", "-g", "@plugin", "a"},
+				{"%endif"},
+			},
+		},
+		{
+			name: "quoted or attached braces are words",
+			src:  "bind '}' swap-pane -D\nbind {x swap-pane -U",
+			want: [][]string{{"bind", "}", "swap-pane", "-D"}, {"bind", "{x", "swap-pane", "-U"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tmuxconf.Parse("tmux.conf", []byte(tt.src))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got := words(f); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBlock(t *testing.T) {
+	src := "if-shell 'true' {\n  set -g @plugin a\n  set -g mouse on\n} {\n}\nset -g @plugin b\n"
+
+	f, err := tmuxconf.Parse("tmux.conf", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(f.Commands) != 2 {
+		t.Fatalf("expected 2 top-level commands, got %q", words(f))
+	}
+
+	ifShell := f.Commands[0]
+	if len(ifShell.Args) != 3 {
+		t.Fatalf("expected condition and two blocks, got %q", ifShell.Values())
+	}
+	then := ifShell.Args[1]
+	if len(then.Block) != 2 || then.Block[0].Name != "set" || then.Block[1].Pos.Line != 3 {
+		t.Errorf("unexpected block %+v", then.Block)
+	}
+	if then.Value != "set -g @plugin a\n  set -g mouse on" {
+		t.Errorf("block value = %q", then.Value)
+	}
+	if ifShell.Args[2].Block != nil || ifShell.Args[2].Value != "" {
+		t.Errorf("expected empty else block, got %+v", ifShell.Args[2])
+	}
+
+	// Only top-level declarations count.
+	if decls := f.Plugins(); len(decls) != 1 || decls[0].Value != "b" {
+		t.Errorf("expected only the top-level plugin, got %+v", decls)
+	}
+}
+
+func TestParsePositions(t *testing.T) {
+	src := "# header\nset -g mouse on\nset -g \\\n  @plugin x ; set -g @plugin y\n"
+
+	f, err := tmuxconf.Parse("/home/u/.tmux.conf", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := []struct {
+		line, endLine int
+		text          string
+	}{
+		{2, 2, "set -g mouse on"},
+		{3, 4, "set -g \\\n  @plugin x"},
+		{4, 4, "set -g @plugin y"},
+	}
+	if len(f.Commands) != len(want) {
+		t.Fatalf("expected %d commands, got %q", len(want), words(f))
+	}
+	for i, w := range want {
+		c := f.Commands[i]
+		if c.Pos.Line != w.line || c.EndLine != w.endLine {
+			t.Errorf("command %d spans lines %d-%d, want %d-%d", i, c.Pos.Line, c.EndLine, w.line, w.endLine)
+		}
+		if got := src[c.Start:c.End]; got != w.text {
+			t.Errorf("command %d text = %q, want %q", i, got, w.text)
+		}
+	}
+	if got := f.Commands[1].Pos.String(); got != "/home/u/.tmux.conf:3" {
+		t.Errorf("Pos.String() = %q", got)
+	}
+	if arg := f.Commands[1].Args[2]; src[arg.Start:arg.End] != "x" || arg.Pos.Line != 4 {
+		t.Errorf("unexpected argument position %+v", arg)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		line int
+		good int
+	}{
+		{"unterminated single quote", "set -g mouse on\nset -g @plugin 'foo\n", 2, 1},
+		{"unterminated double quote", `set -g @plugin "foo`, 1, 0},
+		{"unterminated format", "set -g mouse on\n\nset -g status-left #{foo", 3, 1},
+		{"unterminated block", "if-shell true {\n  set -g mouse on\n", 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tmuxconf.Parse("tmux.conf", []byte(tt.src))
+
+			var perr *tmuxconf.Error
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected *Error, got %v", err)
+			}
+			if perr.Pos.Line != tt.line {
+				t.Errorf("error at line %d, want %d (%v)", perr.Pos.Line, tt.line, err)
+			}
+			if len(f.Commands) != tt.good {
+				t.Errorf("expected %d commands before the error, got %q", tt.good, words(f))
+			}
+		})
+	}
+}
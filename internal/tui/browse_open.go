@@ -8,6 +8,7 @@ import (
 	"time"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/tmuxpack/tpack/internal/registry"
 )
 
 type clearBrowseStatusMsg struct{}
@@ -21,7 +22,7 @@ func (m Model) openFromBrowse() (tea.Model, tea.Cmd) {
 	selected := m.browseResults[m.browseScroll.cursor]
 	host := selected.Host
 	if host == "" {
-		host = defaultGitHubHost
+		host = registry.DefaultHost
 	}
 	url := "https://" + host + "/" + selected.Repo
 
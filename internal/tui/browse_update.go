@@ -169,6 +169,15 @@ func (m *Model) fetchRegistryCmd() tea.Cmd {
 	}
 }
 
+// pluginsFile returns the config file new plugins are added to.
+func (m *Model) pluginsFile() string {
+	decls := make([]plug.Plugin, len(m.plugins))
+	for i, p := range m.plugins {
+		decls[i] = plug.Plugin{Name: p.Name, SourceFile: p.SourceFile}
+	}
+	return config.PluginsFile(decls, m.cfg.TmuxConf)
+}
+
 func (m Model) installFromBrowse() (tea.Model, tea.Cmd) {
 	if m.browseScroll.cursor < 0 || m.browseScroll.cursor >= len(m.browseResults) {
 		return m, nil
@@ -176,10 +185,7 @@ func (m Model) installFromBrowse() (tea.Model, tea.Cmd) {
 
 	selected := m.browseResults[m.browseScroll.cursor]
 
-	spec := selected.Repo
-	if selected.Host != "" && selected.Host != defaultGitHubHost {
-		spec = "https://" + selected.Host + "/" + selected.Repo
-	}
+	spec := selected.Spec()
 
 	for _, p := range m.plugins {
 		if p.Spec == spec || p.Name == pluginNameFromRepo(selected.Repo) {
@@ -187,15 +193,19 @@ func (m Model) installFromBrowse() (tea.Model, tea.Cmd) {
 		}
 	}
 
-	if m.cfg.TmuxConf != "" {
-		_ = config.AppendPlugin(m.cfg.TmuxConf, spec)
+	file := m.pluginsFile()
+	if file != "" {
+		_ = config.AppendPlugin(file, spec, m.cfg.EditOptions()...)
 	}
 
 	name := pluginNameFromRepo(selected.Repo)
 	m.plugins = append(m.plugins, PluginItem{
-		Name:   name,
-		Spec:   spec,
-		Status: StatusNotInstalled,
+		Name:       name,
+		Spec:       spec,
+		Status:     StatusNotInstalled,
+		Raw:        spec,
+		SourceFile: file,
+		Origin:     file,
 	})
 
 	ops := []pendingOp{{
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"charm.land/bubbles/v2/key"
+	"github.com/tmuxpack/tpack/internal/registry"
 )
 
 func (m *Model) viewBrowse() string {
@@ -96,7 +97,7 @@ func (m *Model) renderBrowseResults() string {
 		stars := m.theme.BrowseStarsStyle.Render(formatStars(p.Stars))
 		host := p.Host
 		if host == "" {
-			host = defaultGitHubHost
+			host = registry.DefaultHost
 		}
 		repoURL := "https://" + host + "/" + p.Repo
 		repo := m.theme.BrowseRepoStyle.Hyperlink(repoURL).Render(p.Repo)
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/plug"
 )
 
 // Screen represents the current TUI screen.
@@ -15,6 +16,10 @@ const (
 	ScreenCommits
 	ScreenDebug
 	ScreenBrowse
+	ScreenHistory
+	ScreenDetail
+	ScreenProfile
+	ScreenOutput
 )
 
 // Operation represents the current plugin operation.
@@ -57,12 +62,13 @@ const (
 	StatusChecking
 	StatusOutdated
 	StatusCheckFailed
+	StatusLocal
 )
 
 // IsInstalled returns true for any status that means the plugin is on disk.
 func (s PluginStatus) IsInstalled() bool {
 	switch s {
-	case StatusInstalled, StatusChecking, StatusOutdated, StatusCheckFailed:
+	case StatusInstalled, StatusChecking, StatusOutdated, StatusCheckFailed, StatusLocal:
 		return true
 	case StatusNotInstalled:
 		return false
@@ -82,6 +88,8 @@ func (s PluginStatus) String() string {
 		return "Outdated"
 	case StatusCheckFailed:
 		return "Check Failed"
+	case StatusLocal:
+		return "Local"
 	default:
 		return "Unknown"
 	}
@@ -89,10 +97,29 @@ func (s PluginStatus) String() string {
 
 // PluginItem is an enriched plugin with install status.
 type PluginItem struct {
-	Name   string
-	Spec   string
-	Branch string
-	Status PluginStatus
+	Name    string
+	Spec    string
+	Branch  string
+	Version string
+	// LocalPath is set for local plugins, which are linked, never pulled.
+	LocalPath string
+	Status    PluginStatus
+	// Raw is the spec as declared, and SourceFile the config file that
+	// declares it (empty for @tpm_plugins). Removal edits that file.
+	Raw        string
+	SourceFile string
+	// Origin is where the plugin is declared, e.g. "~/.tmux.conf:12".
+	Origin string
+	// Options are the tmux options set before the plugin is sourced.
+	Options []plug.Option
+	// Build is the command run in the plugin directory after the plugin is
+	// cloned or updated.
+	Build string
+	// Disabled plugins are installed but not sourced. DisabledInConfig is
+	// set when the declaration itself disables the plugin, which the TUI
+	// can't undo.
+	Disabled         bool
+	DisabledInConfig bool
 }
 
 // OrphanItem represents a plugin directory not in config.
@@ -111,22 +138,29 @@ type ResultItem struct {
 	Dir       string
 	BeforeRef string
 	AfterRef  string
+	// BuildOutput is what the plugin's build command printed.
+	BuildOutput string
 }
 
 // pendingOp is a queued operation item.
 type pendingOp struct {
-	Name   string
-	Spec   string
-	Branch string
-	Path   string
+	Name      string
+	Spec      string
+	Branch    string
+	Version   string
+	LocalPath string
+	Path      string
+	Build     string
+	// Raw and SourceFile locate the declaration a remove edits.
+	Raw        string
+	SourceFile string
+	// Err is set when the operation failed before it was dispatched.
+	Err string
 }
 
 // escKeyName is the string representation of the Escape key.
 const escKeyName = "esc"
 
-// defaultGitHubHost is the default host for plugin repositories.
-const defaultGitHubHost = "github.com"
-
 // Fixed application dimensions.
 const (
 	// FixedWidth is the fixed width of the application in columns.
@@ -156,6 +190,9 @@ const (
 	// progressResultsReservedLines is the overhead for title, counter, progress bar, stats, and help
 	// on the progress screen.
 	progressResultsReservedLines = 15
+	// buildOutputLines is how many of its last lines of build output the
+	// progress screen shows for the result under the cursor.
+	buildOutputLines = 5
 	// browseReservedLines is the overhead for title, category bar, search input, and help
 	// on the browse screen.
 	browseReservedLines = 10
@@ -168,4 +205,5 @@ const (
 	CheckTimeout  = 15 * time.Second
 	CloneTimeout  = 2 * time.Minute
 	UpdateTimeout = 2 * time.Minute
+	BuildTimeout  = 10 * time.Minute
 )
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/tmuxpack/tpack/internal/plug"
+)
+
+// enterDetail switches to the detail screen for the plugin under the cursor.
+func (m Model) enterDetail() (tea.Model, tea.Cmd) {
+	if m.listScroll.cursor < 0 || m.listScroll.cursor >= len(m.plugins) {
+		return m, nil
+	}
+	m.screen = ScreenDetail
+	return m, nil
+}
+
+// handleKeyMsgDetail handles key events on the detail screen.
+func (m Model) handleKeyMsgDetail(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, SharedKeys.Quit):
+		return m, tea.Quit
+	case msg.String() == escKeyName:
+		m.screen = ScreenList
+	case key.Matches(msg, DetailKeys.Output):
+		return m.enterOutput()
+	}
+	return m, nil
+}
+
+// disabledReason says how p was disabled, or "" if it is enabled.
+func disabledReason(p PluginItem) string {
+	switch {
+	case p.DisabledInConfig:
+		return "by its declaration"
+	case p.Disabled:
+		return "with tpack disable (d on the plugin list)"
+	}
+	return ""
+}
+
+// viewDetail renders everything known about the plugin under the cursor,
+// including the config file and line that declare it and the options set
+// before it is sourced.
+func (m *Model) viewDetail() string {
+	var b strings.Builder
+
+	p := m.plugins[m.listScroll.cursor]
+	b.WriteString(m.centerText(m.theme.TitleStyle.Render("  " + p.Name + "  ")))
+	b.WriteString("\n\n")
+
+	path := p.LocalPath
+	if path == "" {
+		path = plug.PluginPath(p.Name, m.cfg.PluginPath)
+	}
+	rows := []struct{ label, value string }{
+		{"Spec:", p.Raw},
+		{"Branch:", p.Branch},
+		{"Version:", p.Version},
+		{"Status:", p.Status.String()},
+		{"Path:", path},
+		{"Declared:", p.Origin},
+		{"Disabled:", disabledReason(p)},
+	}
+	for i, o := range p.Options {
+		label := ""
+		if i == 0 {
+			label = "Options:"
+		}
+		rows = append(rows, struct{ label, value string }{label, o.Name + " " + o.Value})
+	}
+	for _, r := range rows {
+		if r.value == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "  %s  %s\n", m.theme.HelpKeyStyle.Render(fmt.Sprintf("%-9s", r.label)), r.value)
+	}
+
+	help := m.centerText(m.theme.renderHelp(m.width, DetailKeys.Output, SharedKeys.Back, SharedKeys.Quit))
+	return padToBottom(b.String(), help, m.height)
+}
@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/tmuxpack/tpack/internal/state"
+)
+
+// pluginDisabledResultMsg reports saving the disabled state of plugins.
+type pluginDisabledResultMsg struct {
+	Names    []string
+	Disabled bool
+	Err      error
+}
+
+type clearListStatusMsg struct{}
+
+// toggleTargets returns the target plugins the disable key can toggle,
+// leaving out those disabled by their declaration.
+func (m *Model) toggleTargets() []int {
+	var targets []int
+	for _, i := range m.targetIndices() {
+		if !m.plugins[i].DisabledInConfig {
+			targets = append(targets, i)
+		}
+	}
+	return targets
+}
+
+// disableBinding returns the help for the disable key: "enable" when every
+// target is disabled already. It reports false if there is nothing to toggle.
+func (m *Model) disableBinding() (key.Binding, bool) {
+	targets := m.toggleTargets()
+	if len(targets) == 0 {
+		return key.Binding{}, false
+	}
+	b := ListKeys.Disable
+	if !m.anyEnabled(targets) {
+		b.SetHelp("d", "enable")
+	}
+	return b, true
+}
+
+// anyEnabled reports whether any of the plugins at indices is enabled.
+func (m *Model) anyEnabled(indices []int) bool {
+	for _, i := range indices {
+		if !m.plugins[i].Disabled {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleDisabled turns the target plugins off, or back on if they are all
+// off already, and saves the change to the state directory. The change
+// takes effect the next time tmux loads its config.
+func (m Model) toggleDisabled() (tea.Model, tea.Cmd) {
+	targets := m.toggleTargets()
+	if len(targets) == 0 {
+		return m, nil
+	}
+	disable := m.anyEnabled(targets)
+	names := make([]string, len(targets))
+	for k, i := range targets {
+		m.plugins[i].Disabled = disable
+		names[k] = m.plugins[i].Name
+	}
+	return m, m.disabledCmd(names, disable)
+}
+
+// disabledCmd returns a command that saves the disabled state of the named
+// plugins, or nil when no state directory is configured.
+func (m *Model) disabledCmd(names []string, disabled bool) tea.Cmd {
+	statePath := m.cfg.StatePath
+	if statePath == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		err := state.SetDisabled(statePath, disabled, names...)
+		return pluginDisabledResultMsg{Names: names, Disabled: disabled, Err: err}
+	}
+}
+
+// handleDisabledResult flips the plugins back when their state couldn't be
+// saved, so the list doesn't show a change that wasn't persisted.
+func (m Model) handleDisabledResult(msg pluginDisabledResultMsg) (tea.Model, tea.Cmd) {
+	if msg.Err == nil {
+		return m, nil
+	}
+	for _, name := range msg.Names {
+		for i := range m.plugins {
+			if m.plugins[i].Name == name {
+				m.plugins[i].Disabled = !msg.Disabled
+			}
+		}
+	}
+	m.listStatus = "Failed to save: " + msg.Err.Error()
+	return m, tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+		return clearListStatusMsg{}
+	})
+}
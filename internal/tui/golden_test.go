@@ -8,8 +8,12 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/history"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/profile"
 	"github.com/tmuxpack/tpack/internal/registry"
 )
 
@@ -155,6 +159,17 @@ func TestGolden_ScreenList(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "list_disabled",
+			setup: func(m *Model) {
+				m.plugins = []PluginItem{
+					{Name: "tmux-sensible", Spec: "tmux-plugins/tmux-sensible", Status: StatusInstalled},
+					{Name: "tmux-cpu", Spec: "tmux-plugins/tmux-cpu", Status: StatusInstalled, Disabled: true},
+					{Name: "tmux-yank", Spec: "tmux-plugins/tmux-yank", Status: StatusInstalled},
+				}
+				m.listScroll.cursor = 1
+			},
+		},
 		{
 			name: "list_multiselect",
 			setup: func(m *Model) {
@@ -265,6 +280,21 @@ func TestGolden_ScreenProgress(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "progress_build_output",
+			setup: func(m *Model) {
+				m.screen = ScreenProgress
+				m.operation = OpInstall
+				m.totalItems = 2
+				m.completedItems = 2
+				m.processing = false
+				m.results = []ResultItem{
+					{Name: "tmux-thumbs", Success: false, Message: `build "cargo build --release": exit status 101`,
+						BuildOutput: "   Compiling thumbs v0.8.0\nerror[E0425]: cannot find value `alphabet`\n  --> src/main.rs:12:5\n\nerror: could not compile `thumbs`\n\tcaused by previous error"},
+					{Name: "tmux-sensible", Success: true, Message: "installed"},
+				}
+			},
+		},
 		{
 			name: "progress_auto_op",
 			setup: func(m *Model) {
@@ -372,6 +402,105 @@ func TestGolden_ScreenDebug(t *testing.T) {
 	assertGolden(t, "debug_view", m.View().Content)
 }
 
+func TestGolden_ScreenDetail(t *testing.T) {
+	m := newTestModel(t, nil)
+	m.plugins = []PluginItem{
+		{Name: "tmux-sensible", Spec: "tmux-plugins/tmux-sensible", Status: StatusInstalled},
+		{
+			Name: "theme", Spec: "catppuccin/tmux", Version: "v2.1.0", Status: StatusOutdated,
+			Raw: "catppuccin/tmux@v2.1.0 alias=theme", SourceFile: "/home/user/.config/tmux/plugins.conf",
+			Origin: "/home/user/.config/tmux/plugins.conf:3",
+			Options: []plug.Option{
+				{Name: "@catppuccin_flavor", Value: "mocha"},
+				{Name: "@catppuccin_window_status", Value: "icon"},
+			},
+		},
+	}
+	m.cfg.PluginPath = "/home/user/.tmux/plugins/"
+	m.listScroll.cursor = 1
+	m.screen = ScreenDetail
+	assertGolden(t, "detail_view", m.View().Content)
+}
+
+func TestGolden_ScreenHistory(t *testing.T) {
+	at := time.Date(2026, 3, 10, 9, 30, 0, 0, time.Local)
+	tests := []struct {
+		name    string
+		entries []history.Entry
+	}{
+		{"history_empty", nil},
+		{"history_entries", []history.Entry{
+			{Time: at.Add(2 * time.Hour), Action: history.Update, Plugin: "tmux-yank", Error: "could not read from remote"},
+			{Time: at.Add(time.Hour), Action: history.Update, Plugin: "tmux-sensible", Before: "aaaaaaa111", After: "bbbbbbb222", Commits: 3},
+			{Time: at, Action: history.Install, Plugin: "tmux-yank"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, nil)
+			m.screen = ScreenHistory
+			m.historyEntries = tt.entries
+			assertGolden(t, tt.name, m.View().Content)
+		})
+	}
+}
+
+func TestGolden_ScreenProfile(t *testing.T) {
+	at := time.Date(2026, 3, 10, 9, 30, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		run  *profile.Run
+	}{
+		{"profile_empty", nil},
+		{"profile_plugins", &profile.Run{
+			Time:  at,
+			Total: 1240 * time.Millisecond,
+			Scripts: []profile.Script{
+				{Plugin: "tmux-cpu", File: "cpu.tmux", Duration: 910 * time.Millisecond},
+				{Plugin: "tmux-yank", File: "yank.tmux", Duration: 210 * time.Millisecond, ExitCode: 1},
+				{Plugin: "tmux-sensible", File: "sensible.tmux", Duration: 80 * time.Millisecond},
+				{Plugin: "tmux-sensible", File: "extra.tmux", Duration: 40 * time.Millisecond},
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, nil)
+			m.screen = ScreenProfile
+			m.profileRun = tt.run
+			assertGolden(t, tt.name, m.View().Content)
+		})
+	}
+}
+
+func TestGolden_ScreenOutput(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+	}{
+		{"output_empty", nil},
+		{"output_log", []string{
+			"Sourced 2026-03-10 09:30:00",
+			"",
+			"==> cpu.tmux (exit 1, 12ms) <==",
+			"cpu.tmux: line 4: iostat: command not found",
+			"",
+			"==> extra.tmux (exit 0, 3ms) <==",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, []plug.Plugin{{Name: "tmux-cpu", Spec: "tmux-plugins/tmux-cpu"}})
+			m.screen = ScreenOutput
+			m.outputLines = tt.lines
+			assertGolden(t, tt.name, m.View().Content)
+		})
+	}
+}
+
 func TestGolden_ScreenBrowse(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -5,6 +5,7 @@ import (
 
 	"github.com/tmuxpack/tpack/internal/git"
 	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/state"
 )
 
 // buildPluginItems converts raw plugins into enriched PluginItems with status.
@@ -14,19 +15,51 @@ func buildPluginItems(plugins []plug.Plugin, pluginPath string, validator git.Va
 		status := StatusNotInstalled
 		dir := plug.PluginPath(p.Name, pluginPath)
 		info, err := os.Stat(dir)
-		if err == nil && info.IsDir() && validator.IsGitRepo(dir) {
+		switch {
+		case p.IsLocal():
+			if plug.IsLinked(p, pluginPath) {
+				status = StatusLocal
+			}
+		case err == nil && info.IsDir() && validator.IsGitRepo(dir):
 			status = StatusChecking
+			// Pinned plugins sit on a tag or commit, not a tracking branch.
+			if p.Version != "" {
+				status = StatusInstalled
+			}
 		}
 		items = append(items, PluginItem{
-			Name:   p.Name,
-			Spec:   p.Spec,
-			Branch: p.Branch,
-			Status: status,
+			Name:       p.Name,
+			Spec:       p.Spec,
+			Branch:     p.Branch,
+			Version:    p.Version,
+			LocalPath:  p.LocalPath,
+			Status:     status,
+			Raw:        p.Raw,
+			SourceFile: p.SourceFile,
+			Origin:     p.Origin(),
+			Options:    p.Options,
+			Build:      p.Build,
+
+			Disabled:         p.Disabled,
+			DisabledInConfig: p.Disabled,
 		})
 	}
 	return items
 }
 
+// markDisabled marks the items turned off with `tpack disable`.
+func markDisabled(items []PluginItem, statePath string) {
+	if statePath == "" {
+		return
+	}
+	st := state.Load(statePath)
+	for i := range items {
+		if st.IsDisabled(items[i].Name) {
+			items[i].Disabled = true
+		}
+	}
+}
+
 // findOrphans returns orphan items for the TUI.
 func findOrphans(plugins []plug.Plugin, pluginPath string) []OrphanItem {
 	shared := plug.FindOrphans(plugins, pluginPath)
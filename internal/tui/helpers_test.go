@@ -55,6 +55,24 @@ func TestBuildPluginItems_Installed(t *testing.T) {
 	}
 }
 
+func TestBuildPluginItems_Local(t *testing.T) {
+	pluginPath := t.TempDir()
+	linked := plug.Plugin{Name: "tmux-foo", LocalPath: t.TempDir()}
+	unlinked := plug.Plugin{Name: "tmux-bar", LocalPath: t.TempDir()}
+	if err := plug.LinkLocal(linked, pluginPath); err != nil {
+		t.Fatal(err)
+	}
+
+	items := buildPluginItems([]plug.Plugin{linked, unlinked}, pluginPath, git.NewMockValidator())
+
+	if items[0].Status != StatusLocal {
+		t.Errorf("expected StatusLocal for linked plugin, got %s", items[0].Status)
+	}
+	if items[1].Status != StatusNotInstalled {
+		t.Errorf("expected StatusNotInstalled for unlinked plugin, got %s", items[1].Status)
+	}
+}
+
 func TestBuildPluginItems_PreservesFields(t *testing.T) {
 	pluginPath := t.TempDir() + "/"
 	validator := git.NewMockValidator()
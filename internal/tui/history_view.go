@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/tmuxpack/tpack/internal/history"
+)
+
+// historyReservedLines is the overhead for title, help, and padding in the history screen.
+const historyReservedLines = 9
+
+// enterHistory loads the operation history and switches to the history screen.
+func (m Model) enterHistory() (tea.Model, tea.Cmd) {
+	m.historyEntries = nil
+	m.historyErr = nil
+	if m.cfg.StatePath != "" {
+		entries, err := history.Load(m.cfg.StatePath)
+		slices.Reverse(entries) // newest first
+		m.historyEntries = entries
+		m.historyErr = err
+	}
+	m.historyScroll.reset()
+	m.screen = ScreenHistory
+	return m, nil
+}
+
+// handleKeyMsgHistory handles key events on the history screen.
+func (m Model) handleKeyMsgHistory(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, SharedKeys.Quit):
+		return m, tea.Quit
+	case msg.String() == escKeyName:
+		m.screen = ScreenList
+	case key.Matches(msg, ListKeys.Up):
+		m.historyScroll.moveUp()
+	case key.Matches(msg, ListKeys.Down):
+		m.historyScroll.moveDown(len(m.historyEntries), m.historyMaxVisible())
+	}
+	return m, nil
+}
+
+// historyMaxVisible returns the number of history rows that fit in the current height.
+func (m *Model) historyMaxVisible() int {
+	v := m.height - historyReservedLines
+	if v < MinViewHeight {
+		return MinViewHeight
+	}
+	return v
+}
+
+// viewHistory renders the operation history screen, newest entry first.
+func (m *Model) viewHistory() string {
+	var b strings.Builder
+
+	b.WriteString(m.centerText(m.theme.TitleStyle.Render("  History  ")))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.historyErr != nil:
+		b.WriteString("  " + m.theme.ErrorStyle.Render("Failed to read history: "+m.historyErr.Error()) + "\n")
+	case len(m.historyEntries) == 0:
+		b.WriteString("  " + m.theme.MutedTextStyle.Render("No history recorded yet.") + "\n")
+	default:
+		m.renderHistoryList(&b)
+	}
+
+	help := m.centerText(m.theme.renderHelp(m.width, SharedKeys.Back, SharedKeys.Quit))
+	return padToBottom(b.String(), help, m.height)
+}
+
+// renderHistoryList writes the scrollable history rows into b.
+func (m *Model) renderHistoryList(b *strings.Builder) {
+	entries := m.historyEntries
+	nameWidth := 0
+	for _, e := range entries {
+		nameWidth = max(nameWidth, len(e.Plugin))
+	}
+
+	visible := min(len(entries), m.historyMaxVisible())
+	end := min(m.historyScroll.scrollOffset+visible, len(entries))
+	top, bottom, dataStart, dataEnd := m.theme.renderScrollIndicators(m.historyScroll.scrollOffset, end, len(entries))
+	b.WriteString(top)
+
+	for i := dataStart; i < dataEnd; i++ {
+		e := entries[i]
+		cursor := "  "
+		if i == m.historyScroll.cursor {
+			cursor = "> "
+		}
+		when := m.theme.MutedTextStyle.Render(e.Time.Local().Format("2006-01-02 15:04"))
+		row := fmt.Sprintf("%-9s  %-*s  ", e.Action, nameWidth, e.Plugin)
+		detail := e.Detail()
+		if e.Success() {
+			row = m.theme.SuccessStyle.Render("✓") + " " + row + m.theme.MutedTextStyle.Render(detail)
+		} else {
+			row = m.theme.ErrorStyle.Render("✗") + " " + row + m.theme.ErrorStyle.Render(detail)
+		}
+		b.WriteString(cursor + when + "  " + row + "\n")
+	}
+
+	b.WriteString(bottom)
+}
+
+// historyCmd returns a command that appends e to the operation history, or
+// nil when no state directory is configured.
+func (m *Model) historyCmd(e history.Entry) tea.Cmd {
+	statePath := m.cfg.StatePath
+	if statePath == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		_ = history.Append(statePath, e)
+		return nil
+	}
+}
@@ -19,6 +19,10 @@ type listKeys struct {
 	Uninstall key.Binding
 	Debug     key.Binding
 	Browse    key.Binding
+	History   key.Binding
+	Startup   key.Binding
+	Disable   key.Binding
+	Details   key.Binding
 	Search    key.Binding
 }
 
@@ -45,6 +49,10 @@ type browseKeys struct {
 	Open     key.Binding
 }
 
+type detailKeys struct {
+	Output key.Binding
+}
+
 type progressKeys struct {
 	ViewCommits key.Binding
 	BackToList  key.Binding
@@ -73,6 +81,13 @@ var BrowseKeys = browseKeys{
 	),
 }
 
+var DetailKeys = detailKeys{
+	Output: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "output"),
+	),
+}
+
 var ProgressKeys = progressKeys{
 	ViewCommits: key.NewBinding(
 		key.WithKeys("enter"),
@@ -122,6 +137,22 @@ var ListKeys = listKeys{
 		key.WithKeys("b"),
 		key.WithHelp("b", "browse"),
 	),
+	History: key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "history"),
+	),
+	Startup: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "startup"),
+	),
+	Disable: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "disable"),
+	),
+	Details: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "details"),
+	),
 	Search: key.NewBinding(
 		key.WithKeys("/"),
 		key.WithHelp("/", "search"),
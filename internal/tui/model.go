@@ -10,7 +10,9 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"github.com/tmuxpack/tpack/internal/config"
 	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/history"
 	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/profile"
 	"github.com/tmuxpack/tpack/internal/registry"
 	"github.com/tmuxpack/tpack/internal/tmux"
 )
@@ -23,7 +25,11 @@ type Deps struct {
 	Fetcher   git.Fetcher
 	RevParser git.RevParser
 	Logger    git.Logger
-	Runner    tmux.Runner // optional, for post-op tmux sourcing
+	// TagLister and Checkouter resolve and apply version pins; plugins
+	// pinned with "@version" fail to install or update without them.
+	TagLister  git.TagLister
+	Checkouter git.Checkouter
+	Runner     tmux.Runner // optional, for post-op tmux sourcing
 }
 
 // ModelOption configures optional Model behavior.
@@ -68,6 +74,7 @@ type Model struct {
 	autoOp    Operation
 
 	listScroll scrollState
+	listStatus string
 	width      int
 	height     int
 	viewHeight int
@@ -92,6 +99,18 @@ type Model struct {
 	commitViewCommits []git.Commit
 	commitScroll      scrollState
 
+	historyEntries []history.Entry
+	historyErr     error
+	historyScroll  scrollState
+
+	profileRun    *profile.Run
+	profileErr    error
+	profileScroll scrollState
+
+	outputLines  []string
+	outputErr    error
+	outputOffset int
+
 	// Browse screen state.
 	browseRegistry      *registry.Registry
 	browseResults       []registry.RegistryItem
@@ -113,6 +132,7 @@ type Model struct {
 // NewModel creates a new Model from the resolved config and gathered plugins.
 func NewModel(cfg *config.Config, plugins []plug.Plugin, deps Deps, opts ...ModelOption) Model {
 	items := buildPluginItems(plugins, cfg.PluginPath, deps.Validator)
+	markDisabled(items, cfg.StatePath)
 	orphans := findOrphans(plugins, cfg.PluginPath)
 
 	s := spinner.New()
@@ -195,6 +215,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleRemoveResult(msg)
 	case registryFetchResultMsg:
 		return m.handleRegistryFetch(msg)
+	case pluginDisabledResultMsg:
+		return m.handleDisabledResult(msg)
+	case clearListStatusMsg:
+		m.listStatus = ""
+		return m, nil
 	case openURLResultMsg:
 		return m.handleOpenURLResult(msg)
 	case sourceCompleteMsg, clearBrowseStatusMsg:
@@ -240,6 +265,14 @@ func (m Model) handleKeyMsg(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m.handleKeyMsgDebug(msg)
 	case ScreenBrowse:
 		return m.handleKeyMsgBrowse(msg)
+	case ScreenHistory:
+		return m.handleKeyMsgHistory(msg)
+	case ScreenProfile:
+		return m.handleKeyMsgProfile(msg)
+	case ScreenOutput:
+		return m.handleKeyMsgOutput(msg)
+	case ScreenDetail:
+		return m.handleKeyMsgDetail(msg)
 	case ScreenList:
 		return m.handleKeyMsgList(msg)
 	}
@@ -260,6 +293,14 @@ func (m Model) View() tea.View {
 		content = m.viewDebug()
 	case ScreenBrowse:
 		content = m.viewBrowse()
+	case ScreenHistory:
+		content = m.viewHistory()
+	case ScreenProfile:
+		content = m.viewProfile()
+	case ScreenOutput:
+		content = m.viewOutput()
+	case ScreenDetail:
+		content = m.viewDetail()
 	}
 	v := tea.NewView(m.theme.BaseStyle.Render(content))
 	v.AltScreen = true
@@ -282,6 +323,14 @@ func (m *Model) windowTitle() string {
 		return "tpack — Browse"
 	case ScreenCommits:
 		return "tpack — Commits"
+	case ScreenHistory:
+		return "tpack — History"
+	case ScreenProfile:
+		return "tpack — Startup"
+	case ScreenDetail:
+		return "tpack — " + m.plugins[m.listScroll.cursor].Name
+	case ScreenOutput:
+		return "tpack — " + m.plugins[m.listScroll.cursor].Name + " output"
 	case ScreenList, ScreenDebug:
 		return "tpack"
 	}
@@ -322,6 +371,14 @@ func (m Model) handleKeyMsgList(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m.startOperation(OpUninstall)
 	case key.Matches(msg, ListKeys.Browse):
 		return m.enterBrowse()
+	case key.Matches(msg, ListKeys.History):
+		return m.enterHistory()
+	case key.Matches(msg, ListKeys.Startup):
+		return m.enterProfile()
+	case key.Matches(msg, ListKeys.Disable):
+		return m.toggleDisabled()
+	case key.Matches(msg, ListKeys.Details):
+		return m.enterDetail()
 	case key.Matches(msg, ListKeys.Debug):
 		m.screen = ScreenDebug
 	}
@@ -368,6 +425,10 @@ func (m Model) handleKeyMsgProgress(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 // resultMaxVisible returns the number of result rows that fit in the current height.
 func (m *Model) resultMaxVisible() int {
 	v := m.height - progressResultsReservedLines
+	if hasBuildOutput(m.displayResults()) {
+		// Leave room for the build output below the results.
+		v -= buildOutputLines + 3
+	}
 	if v < MinViewHeight {
 		return MinViewHeight
 	}
@@ -453,14 +514,12 @@ func (m Model) startOperation(op Operation) (tea.Model, tea.Cmd) {
 		ops = m.buildInstallOps()
 	case OpRemove:
 		ops = m.buildRemoveOps()
-		// Remove config entries synchronously (file edits cannot be concurrent).
-		if m.cfg.TmuxConf != "" {
-			var kept []pendingOp
-			for _, op := range ops {
-				_ = config.RemovePlugin(m.cfg.TmuxConf, op.Spec)
-				kept = append(kept, op)
+		// Remove config entries synchronously (file edits cannot be concurrent),
+		// from whichever file declares each plugin.
+		for i := range ops {
+			if err := config.RemoveDeclaration(ops[i].SourceFile, ops[i].Raw, m.cfg.EditOptions()...); err != nil {
+				ops[i].Err = err.Error()
 			}
-			ops = kept
 		}
 	case OpUpdate:
 		ops = m.buildUpdateOps()
@@ -542,8 +601,15 @@ func (m *Model) removePlugin(name string) {
 
 // handleInstallResult processes an install result and dispatches next.
 func (m Model) handleInstallResult(msg pluginInstallResultMsg) (tea.Model, tea.Cmd) {
-	cmd := m.handleOpResult(ResultItem{Name: msg.Name, Success: msg.Success, Message: msg.Message}, func() {
-		m.setPluginStatus(msg.Name, StatusInstalled)
+	res := ResultItem{Name: msg.Name, Success: msg.Success, Message: msg.Message, BuildOutput: msg.BuildOutput}
+	cmd := m.handleOpResult(res, func() {
+		status := StatusInstalled
+		for _, p := range m.plugins {
+			if p.Name == msg.Name && p.LocalPath != "" {
+				status = StatusLocal
+			}
+		}
+		m.setPluginStatus(msg.Name, status)
 	})
 	return m, cmd
 }
@@ -559,6 +625,7 @@ func (m Model) handleUpdateResult(msg pluginUpdateResultMsg) (tea.Model, tea.Cmd
 // handleCleanResult processes a clean result and dispatches next.
 func (m Model) handleCleanResult(msg pluginCleanResultMsg) (tea.Model, tea.Cmd) {
 	cmd := m.handleOpResult(ResultItem{Name: msg.Name, Success: msg.Success, Message: msg.Message}, nil)
+	cmd = tea.Batch(cmd, m.historyCmd(opEntry(history.Clean, msg.Name, msg.Success, msg.Message)))
 	return m, cmd
 }
 
@@ -567,18 +634,32 @@ func (m Model) handleUninstallResult(msg pluginUninstallResultMsg) (tea.Model, t
 	cmd := m.handleOpResult(ResultItem{Name: msg.Name, Success: msg.Success, Message: msg.Message}, func() {
 		m.setPluginStatus(msg.Name, StatusNotInstalled)
 	})
+	cmd = tea.Batch(cmd, m.historyCmd(opEntry(history.Uninstall, msg.Name, msg.Success, msg.Message)))
 	return m, cmd
 }
 
 // handleRemoveResult processes a remove result (directory removal) and dispatches next.
 // The config entry was already removed synchronously before dispatch, so the plugin
-// is removed from the list regardless of whether directory removal succeeded.
+// is removed from the list regardless of whether directory removal succeeded, unless
+// the entry itself could not be removed.
 func (m Model) handleRemoveResult(msg pluginRemoveResultMsg) (tea.Model, tea.Cmd) {
-	m.removePlugin(msg.Name)
+	if !msg.Kept {
+		m.removePlugin(msg.Name)
+	}
 	cmd := m.handleOpResult(ResultItem{Name: msg.Name, Success: msg.Success, Message: msg.Message}, nil)
+	cmd = tea.Batch(cmd, m.historyCmd(opEntry(history.Uninstall, msg.Name, msg.Success, msg.Message)))
 	return m, cmd
 }
 
+// opEntry builds a history entry for a finished operation.
+func opEntry(action history.Action, name string, success bool, message string) history.Entry {
+	e := history.Entry{Action: action, Plugin: name}
+	if !success {
+		e.Error = message
+	}
+	return e
+}
+
 // handleSpinnerTick advances the spinner animation while checks are in progress.
 func (m Model) handleSpinnerTick(msg spinner.TickMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
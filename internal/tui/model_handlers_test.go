@@ -2,11 +2,20 @@ package tui
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/tmuxpack/tpack/internal/config"
 	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/history"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/profile"
+	"github.com/tmuxpack/tpack/internal/sourcelog"
+	"github.com/tmuxpack/tpack/internal/state"
 )
 
 func TestHandleCheckResult_Outdated(t *testing.T) {
@@ -264,6 +273,66 @@ func TestUpdateList_RemoveKey(t *testing.T) {
 	}
 }
 
+func TestStartRemove_EditsDeclaringFile(t *testing.T) {
+	sourced := t.TempDir() + "/plugins.conf"
+	if err := os.WriteFile(sourced, []byte("set -g @plugin 'user/alpha@v1'\nset -g mouse on\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(t, nil)
+	m.plugins = []PluginItem{
+		{Name: "alpha", Spec: "user/alpha", Raw: "user/alpha@v1", SourceFile: sourced, Status: StatusInstalled},
+	}
+
+	result, _ := m.startOperation(OpRemove)
+	m = result.(Model)
+
+	data, _ := os.ReadFile(sourced)
+	if string(data) != "set -g mouse on\n" {
+		t.Errorf("expected declaration removed from sourced file, got:\n%s", data)
+	}
+	if m.inFlight != 1 {
+		t.Errorf("expected the directory removal in flight, got %d", m.inFlight)
+	}
+}
+
+func TestStartRemove_UndeclaredKeepsPlugin(t *testing.T) {
+	m := newTestModel(t, nil)
+	m.plugins = []PluginItem{
+		{Name: "alpha", Spec: "user/alpha", Raw: "user/alpha", Status: StatusInstalled},
+	}
+
+	result, cmd := m.startOperation(OpRemove)
+	m = result.(Model)
+	result, _ = m.Update(cmd())
+	m = result.(Model)
+
+	if len(m.plugins) != 1 {
+		t.Errorf("expected plugin kept in the list, got %d plugins", len(m.plugins))
+	}
+	if len(m.results) != 1 || m.results[0].Success || !strings.Contains(m.results[0].Message, "@tpm_plugins") {
+		t.Errorf("expected a failed result naming @tpm_plugins, got %+v", m.results)
+	}
+}
+
+func TestUpdateList_DetailKey(t *testing.T) {
+	m := newTestModel(t, nil)
+	m.plugins = []PluginItem{
+		{Name: "alpha", Spec: "user/alpha", Status: StatusInstalled},
+	}
+
+	result, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = result.(Model)
+	if m.screen != ScreenDetail {
+		t.Fatalf("expected ScreenDetail after enter, got %d", m.screen)
+	}
+
+	result, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	m = result.(Model)
+	if m.screen != ScreenList {
+		t.Errorf("expected ScreenList after esc, got %d", m.screen)
+	}
+}
+
 func TestReturnToList_RemovesCleanedOrphans(t *testing.T) {
 	m := newTestModel(t, nil)
 	m.screen = ScreenProgress
@@ -958,3 +1027,170 @@ func TestReturnToProgress_ClearsState(t *testing.T) {
 		t.Errorf("expected commitViewScrollOffset reset to 0, got %d", m.commitScroll.scrollOffset)
 	}
 }
+
+func TestEnterHistory(t *testing.T) {
+	m := newTestModel(t, nil)
+	m.cfg.StatePath = t.TempDir()
+	err := history.Append(m.cfg.StatePath,
+		history.Entry{Action: history.Install, Plugin: "first"},
+		history.Entry{Action: history.Update, Plugin: "second"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, _ := m.Update(tea.KeyPressMsg{Code: 'h', Text: "h"})
+	m = result.(Model)
+
+	if m.screen != ScreenHistory {
+		t.Fatalf("expected ScreenHistory, got %d", m.screen)
+	}
+	if len(m.historyEntries) != 2 || m.historyEntries[0].Plugin != "second" {
+		t.Errorf("expected newest entry first, got %+v", m.historyEntries)
+	}
+
+	result, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	m = result.(Model)
+	if m.screen != ScreenList {
+		t.Errorf("expected esc to return to list, got %d", m.screen)
+	}
+}
+
+func TestEnterProfile(t *testing.T) {
+	m := newTestModel(t, nil)
+	m.cfg.StatePath = t.TempDir()
+	err := profile.Save(m.cfg.StatePath, &profile.Run{Scripts: []profile.Script{
+		{Plugin: "fast", File: "fast.tmux", Duration: time.Millisecond},
+		{Plugin: "slow", File: "slow.tmux", Duration: time.Second},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, _ := m.Update(tea.KeyPressMsg{Code: 's', Text: "s"})
+	m = result.(Model)
+
+	if m.screen != ScreenProfile {
+		t.Fatalf("expected ScreenProfile, got %d", m.screen)
+	}
+	if m.profileRun == nil || len(m.profileRun.Scripts) != 2 {
+		t.Fatalf("expected the saved profile, got %+v", m.profileRun)
+	}
+	if m.windowTitle() != "tpack — Startup" {
+		t.Errorf("windowTitle() = %q", m.windowTitle())
+	}
+
+	result, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	m = result.(Model)
+	if m.screen != ScreenList {
+		t.Errorf("expected esc to return to list, got %d", m.screen)
+	}
+}
+
+func TestEnterOutput(t *testing.T) {
+	m := newTestModel(t, []plug.Plugin{{Name: "tmux-cpu", Spec: "tmux-plugins/tmux-cpu"}})
+	m.cfg.StatePath = t.TempDir()
+	err := sourcelog.Write(m.cfg.StatePath, "tmux-cpu", time.Now(), []profile.Script{
+		{File: "cpu.tmux", ExitCode: 1, Stderr: "iostat: command not found\n"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.screen = ScreenDetail
+	result, _ := m.Update(tea.KeyPressMsg{Code: 'o', Text: "o"})
+	m = result.(Model)
+
+	if m.screen != ScreenOutput {
+		t.Fatalf("expected ScreenOutput, got %d", m.screen)
+	}
+	if len(m.outputLines) != 4 || m.outputLines[3] != "iostat: command not found" {
+		t.Errorf("unexpected output lines: %q", m.outputLines)
+	}
+
+	result, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	m = result.(Model)
+	if m.screen != ScreenDetail {
+		t.Errorf("expected esc to return to the details, got %d", m.screen)
+	}
+}
+
+func TestToggleDisabled(t *testing.T) {
+	m := newTestModel(t, []plug.Plugin{
+		{Name: "tmux-cpu", Spec: "tmux-plugins/tmux-cpu"},
+		{Name: "theme", Spec: "catppuccin/tmux", Disabled: true},
+	})
+	m.cfg.StatePath = t.TempDir()
+
+	result, cmd := m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	m = result.(Model)
+	if !m.plugins[0].Disabled {
+		t.Fatal("expected tmux-cpu to be disabled")
+	}
+	if cmd == nil {
+		t.Fatal("expected a command saving the change")
+	}
+	cmd()
+	if !state.Load(m.cfg.StatePath).IsDisabled("tmux-cpu") {
+		t.Error("expected the change to be saved")
+	}
+	if b, ok := m.disableBinding(); !ok || b.Help().Desc != "enable" {
+		t.Errorf("expected the help to offer enable, got %+v", b.Help())
+	}
+
+	result, cmd = m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	m = result.(Model)
+	cmd()
+	if m.plugins[0].Disabled || state.Load(m.cfg.StatePath).IsDisabled("tmux-cpu") {
+		t.Error("expected tmux-cpu to be enabled again")
+	}
+
+	// A plugin disabled by its declaration can't be toggled.
+	m.listScroll.cursor = 1
+	result, cmd = m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	m = result.(Model)
+	if !m.plugins[1].Disabled || cmd != nil {
+		t.Error("expected theme to stay disabled")
+	}
+}
+
+func TestToggleDisabled_SaveFails(t *testing.T) {
+	m := newTestModel(t, []plug.Plugin{{Name: "tmux-cpu", Spec: "tmux-plugins/tmux-cpu"}})
+	// A file where the state directory should be makes saving fail.
+	m.cfg.StatePath = filepath.Join(t.TempDir(), "state")
+	if err := os.WriteFile(m.cfg.StatePath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, cmd := m.Update(tea.KeyPressMsg{Code: 'd', Text: "d"})
+	m = result.(Model)
+	msg, ok := cmd().(pluginDisabledResultMsg)
+	if !ok || msg.Err == nil {
+		t.Fatalf("expected a failed save, got %+v", msg)
+	}
+
+	result, _ = m.Update(msg)
+	m = result.(Model)
+	if m.plugins[0].Disabled {
+		t.Error("expected tmux-cpu to be flipped back")
+	}
+	if !strings.Contains(m.viewList(), "Failed to save") {
+		t.Error("expected the error to be shown")
+	}
+
+	result, _ = m.Update(clearListStatusMsg{})
+	if m = result.(Model); m.listStatus != "" {
+		t.Errorf("expected the status to clear, got %q", m.listStatus)
+	}
+}
+
+func TestNewModel_MarksDisabledFromState(t *testing.T) {
+	cfg := &config.Config{PluginPath: t.TempDir() + "/", StatePath: t.TempDir()}
+	if err := state.SetDisabled(cfg.StatePath, true, "tmux-cpu"); err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(cfg, []plug.Plugin{{Name: "tmux-cpu"}, {Name: "tmux-yank"}}, Deps{Validator: git.NewMockValidator()})
+	if !m.plugins[0].Disabled || m.plugins[0].DisabledInConfig || m.plugins[1].Disabled {
+		t.Errorf("unexpected disabled state: %+v", m.plugins)
+	}
+}
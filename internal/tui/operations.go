@@ -3,18 +3,26 @@ package tui
 import (
 	"context"
 	"os"
+	"strings"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/tmuxpack/tpack/internal/config"
 	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/history"
+	"github.com/tmuxpack/tpack/internal/manager"
 	"github.com/tmuxpack/tpack/internal/plug"
 	"github.com/tmuxpack/tpack/internal/tmux"
+	"github.com/tmuxpack/tpack/internal/ui"
 )
 
 // Messages returned by operations.
 type pluginInstallResultMsg struct {
-	Name    string
-	Success bool
-	Message string
+	Name        string
+	Success     bool
+	Message     string
+	BuildOutput string
+	// AfterRef is the commit the plugin was installed at.
+	AfterRef string
 }
 
 type pluginUpdateResultMsg struct {
@@ -26,6 +34,8 @@ type pluginUpdateResultMsg struct {
 	Dir       string
 	BeforeRef string
 	AfterRef  string
+	// BuildOutput is what the plugin's build command printed.
+	BuildOutput string
 }
 
 type pluginCleanResultMsg struct {
@@ -44,6 +54,9 @@ type pluginRemoveResultMsg struct {
 	Name    string
 	Success bool
 	Message string
+	// Kept is set when the config entry could not be removed, so the
+	// plugin stays declared and its directory is left alone.
+	Kept bool
 }
 
 type pluginCheckResultMsg struct {
@@ -63,77 +76,131 @@ func checkPluginCmd(fetcher git.Fetcher, name string, dir string) tea.Cmd {
 	}
 }
 
-// clones a plugin
-func installPluginCmd(cloner git.Cloner, op pendingOp) tea.Cmd {
+// installs a plugin through the manager, which checks out its locked
+// commit or version pin, builds it, and records it in the lockfile and the
+// history. Local plugins are linked.
+func installPluginCmd(cfg *config.Config, deps Deps, plugins []plug.Plugin, op pendingOp) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), CloneTimeout)
+		// The build runs on the same context, so it gets its own allowance.
+		ctx, cancel := context.WithTimeout(context.Background(), CloneTimeout+BuildTimeout)
 		defer cancel()
 
-		err := git.CloneWithFallback(ctx, cloner, git.CloneOptions{
-			URL:    op.Spec,
-			Dir:    op.Path,
-			Branch: op.Branch,
-		}, plug.NormalizeURL)
-
-		if err != nil {
-			return pluginInstallResultMsg{
-				Name:    op.Name,
-				Success: false,
-				Message: err.Error(),
-			}
+		out := &opOutput{}
+		newManager(cfg, deps, out).InstallNamed(ctx, plugins, []string{op.Name})
+
+		res := out.result(op.Name, history.Install)
+		msg := pluginInstallResultMsg{
+			Name:        op.Name,
+			Success:     res.Status != ui.StatusFailed,
+			Message:     res.Error,
+			BuildOutput: out.buildOutput(op.Name),
+			AfterRef:    res.After,
 		}
-		return pluginInstallResultMsg{
-			Name:    op.Name,
-			Success: true,
-			Message: "installed successfully",
+		switch {
+		case !msg.Success:
+		case op.LocalPath != "":
+			msg.Message = "linked to " + op.LocalPath
+		default:
+			msg.Message = "installed successfully"
 		}
+		return msg
 	}
 }
 
-// pulls updates
-func updatePluginCmd(puller git.Puller, revParser git.RevParser, logger git.Logger, op pendingOp) tea.Cmd {
+// updates a plugin through the manager, which pulls it or moves it to the
+// newest tag matching its version pin, rolls it back if that fails, builds
+// it, and records the new commit in the lockfile and the history.
+func updatePluginCmd(cfg *config.Config, deps Deps, plugins []plug.Plugin, op pendingOp) tea.Cmd {
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), UpdateTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), UpdateTimeout+BuildTimeout)
 		defer cancel()
 
-		// Capture HEAD before pull for commit log comparison.
-		var beforeHash string
-		if revParser != nil {
-			beforeHash, _ = revParser.RevParse(ctx, op.Path)
+		out := &opOutput{}
+		newManager(cfg, deps, out).Update(ctx, plugins, []string{op.Name})
+
+		res := out.result(op.Name, history.Update)
+		msg := pluginUpdateResultMsg{
+			Name:        op.Name,
+			Success:     res.Status != ui.StatusFailed,
+			Message:     res.Error,
+			Output:      strings.Join(out.lines, "\n"),
+			Dir:         op.Path,
+			BeforeRef:   res.Before,
+			AfterRef:    res.After,
+			BuildOutput: out.buildOutput(op.Name),
 		}
-
-		output, err := puller.Pull(ctx, git.PullOptions{Dir: op.Path, Branch: op.Branch})
-		if err != nil {
-			return pluginUpdateResultMsg{
-				Name:    op.Name,
-				Success: false,
-				Message: err.Error(),
-				Output:  output,
-			}
+		switch {
+		case !msg.Success:
+		case res.Status == ui.StatusSkipped:
+			msg.Message = "skipped"
+		case res.Status == ui.StatusUnchanged:
+			msg.Message = "already up to date"
+		default:
+			msg.Message = "updated successfully"
 		}
+		if msg.Success && msg.BeforeRef != "" && msg.AfterRef != msg.BeforeRef && deps.Logger != nil {
+			msg.Commits, _ = deps.Logger.Log(ctx, op.Path, msg.BeforeRef, msg.AfterRef)
+		}
+		return msg
+	}
+}
 
-		// Get commits pulled if we captured the before hash.
-		var commits []git.Commit
-		var afterHash string
-		if beforeHash != "" && logger != nil {
-			var revErr error
-			afterHash, revErr = revParser.RevParse(ctx, op.Path)
-			if revErr == nil && afterHash != beforeHash {
-				commits, _ = logger.Log(ctx, op.Path, beforeHash, afterHash)
-			}
+// newManager returns a manager that installs and updates plugins with deps,
+// reporting to out.
+func newManager(cfg *config.Config, deps Deps, out ui.Output) *manager.Manager {
+	return manager.New(cfg.PluginPath, deps.Cloner, deps.Puller, deps.Validator, out,
+		manager.WithRevParser(deps.RevParser),
+		manager.WithLogger(deps.Logger),
+		manager.WithCheckouter(deps.Checkouter),
+		manager.WithTagLister(deps.TagLister),
+		manager.WithLockPath(cfg.LockPath),
+		manager.WithStatePath(cfg.StatePath),
+	)
+}
+
+// opOutput collects what the manager reports about a single operation.
+type opOutput struct {
+	lines   []string
+	results []ui.Result
+	failed  bool
+}
+
+func (o *opOutput) Ok(msg string) { o.lines = append(o.lines, msg) }
+
+func (o *opOutput) Err(msg string) {
+	o.lines = append(o.lines, msg)
+	o.failed = true
+}
+
+func (o *opOutput) Result(r ui.Result) { o.results = append(o.results, r) }
+
+func (o *opOutput) EndMessage() {}
+
+func (o *opOutput) HasFailed() bool { return o.failed }
+
+// result returns the result the manager reported for action on name. When
+// it reported none, the operation failed before reaching the plugin.
+func (o *opOutput) result(name string, action history.Action) ui.Result {
+	for _, r := range o.results {
+		if r.Name == name && r.Action == string(action) {
+			return r
 		}
+	}
+	msg := "failed"
+	if len(o.lines) > 0 {
+		msg = strings.TrimSpace(o.lines[len(o.lines)-1])
+	}
+	return ui.Result{Name: name, Action: string(action), Status: ui.StatusFailed, Error: msg}
+}
 
-		return pluginUpdateResultMsg{
-			Name:      op.Name,
-			Success:   true,
-			Message:   "updated successfully",
-			Output:    output,
-			Commits:   commits,
-			Dir:       op.Path,
-			BeforeRef: beforeHash,
-			AfterRef:  afterHash,
+// buildOutput returns what name's build command printed, if it ran.
+func (o *opOutput) buildOutput(name string) string {
+	for _, r := range o.results {
+		if r.Name == name && r.Action == manager.BuildAction {
+			return r.Output
 		}
 	}
+	return ""
 }
 
 func removeDirCmd(op pendingOp, msgFactory func(name string, success bool, message string) tea.Msg) tea.Cmd {
@@ -164,6 +231,13 @@ func removePluginDirCmd(op pendingOp) tea.Cmd {
 	})
 }
 
+// reports a remove whose config entry could not be removed.
+func removeFailedCmd(op pendingOp) tea.Cmd {
+	return func() tea.Msg {
+		return pluginRemoveResultMsg{Name: op.Name, Message: op.Err, Kept: true}
+	}
+}
+
 // sources tmux config file
 func sourceCmd(runner tmux.Runner, confPath string) tea.Cmd {
 	return func() tea.Msg {
@@ -202,11 +276,15 @@ func (m *Model) dispatchNext() tea.Cmd {
 		case OpNone:
 			// No-op; should not reach here.
 		case OpInstall:
-			cmds = append(cmds, installPluginCmd(m.deps.Cloner, op))
+			cmds = append(cmds, installPluginCmd(m.cfg, m.deps, m.declared(), op))
 		case OpRemove:
-			cmds = append(cmds, removePluginDirCmd(op))
+			if op.Err != "" {
+				cmds = append(cmds, removeFailedCmd(op))
+			} else {
+				cmds = append(cmds, removePluginDirCmd(op))
+			}
 		case OpUpdate:
-			cmds = append(cmds, updatePluginCmd(m.deps.Puller, m.deps.RevParser, m.deps.Logger, op))
+			cmds = append(cmds, updatePluginCmd(m.cfg, m.deps, m.declared(), op))
 		case OpClean:
 			cmds = append(cmds, cleanPluginCmd(op))
 		case OpUninstall:
@@ -232,10 +310,15 @@ func (m *Model) buildOpsFromTargeted(filter func(PluginItem) bool) []pendingOp {
 			continue
 		}
 		ops = append(ops, pendingOp{
-			Name:   p.Name,
-			Spec:   p.Spec,
-			Branch: p.Branch,
-			Path:   plug.PluginPath(p.Name, m.cfg.PluginPath),
+			Name:       p.Name,
+			Spec:       p.Spec,
+			Branch:     p.Branch,
+			Version:    p.Version,
+			LocalPath:  p.LocalPath,
+			Path:       plug.PluginPath(p.Name, m.cfg.PluginPath),
+			Build:      p.Build,
+			Raw:        p.Raw,
+			SourceFile: p.SourceFile,
 		})
 	}
 	return ops
@@ -249,17 +332,39 @@ func (m *Model) buildOpsFromAll(filter func(PluginItem) bool) []pendingOp {
 			continue
 		}
 		ops = append(ops, pendingOp{
-			Name:   p.Name,
-			Spec:   p.Spec,
-			Branch: p.Branch,
-			Path:   plug.PluginPath(p.Name, m.cfg.PluginPath),
+			Name:       p.Name,
+			Spec:       p.Spec,
+			Branch:     p.Branch,
+			Version:    p.Version,
+			LocalPath:  p.LocalPath,
+			Path:       plug.PluginPath(p.Name, m.cfg.PluginPath),
+			Build:      p.Build,
+			Raw:        p.Raw,
+			SourceFile: p.SourceFile,
 		})
 	}
 	return ops
 }
 
+// declared returns the plugins in the list as the config declares them.
+func (m *Model) declared() []plug.Plugin {
+	plugins := make([]plug.Plugin, len(m.plugins))
+	for i, p := range m.plugins {
+		plugins[i] = plug.Plugin{
+			Name:      p.Name,
+			Spec:      p.Spec,
+			Branch:    p.Branch,
+			Version:   p.Version,
+			LocalPath: p.LocalPath,
+			Build:     p.Build,
+		}
+	}
+	return plugins
+}
+
 func isNotInstalled(p PluginItem) bool { return p.Status == StatusNotInstalled }
 func isInstalled(p PluginItem) bool    { return p.Status.IsInstalled() }
+func isUpdatable(p PluginItem) bool    { return p.Status.IsInstalled() && p.Status != StatusLocal }
 
 func (m *Model) buildInstallOps() []pendingOp {
 	return m.buildOpsFromTargeted(isNotInstalled)
@@ -270,10 +375,10 @@ func (m *Model) buildRemoveOps() []pendingOp {
 }
 
 func (m *Model) buildUpdateOps() []pendingOp {
-	ops := m.buildOpsFromTargeted(isInstalled)
+	ops := m.buildOpsFromTargeted(isUpdatable)
 	// If nothing selected and no cursor match, update all installed.
 	if len(ops) == 0 && !m.multiSelectActive {
-		ops = m.buildOpsFromAll(isInstalled)
+		ops = m.buildOpsFromAll(isUpdatable)
 	}
 	return ops
 }
@@ -298,7 +403,7 @@ func (m *Model) buildAutoInstallOps() []pendingOp {
 }
 
 func (m *Model) buildAutoUpdateOps() []pendingOp {
-	return m.buildOpsFromAll(isInstalled)
+	return m.buildOpsFromAll(isUpdatable)
 }
 
 // returns the indices to operate on: selected if any, else cursor.
@@ -4,192 +4,347 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/tmuxpack/tpack/internal/config"
 	"github.com/tmuxpack/tpack/internal/git"
+	"github.com/tmuxpack/tpack/internal/history"
+	"github.com/tmuxpack/tpack/internal/lock"
+	"github.com/tmuxpack/tpack/internal/plug"
+	"github.com/tmuxpack/tpack/internal/state"
 	"github.com/tmuxpack/tpack/internal/tmux"
 )
 
-func TestInstallPluginCmd_Success(t *testing.T) {
-	cloner := git.NewMockCloner()
-	op := pendingOp{
-		Name: "test-plugin",
-		Spec: "user/test-plugin",
-		Path: t.TempDir() + "/test-plugin/",
+// newOpConfig returns a config whose plugin directory, lockfile and state
+// directory are in a temporary directory.
+func newOpConfig(t *testing.T) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	return &config.Config{
+		PluginPath: filepath.Join(dir, "plugins") + "/",
+		LockPath:   filepath.Join(dir, "tpack.lock"),
+		StatePath:  filepath.Join(dir, "state"),
 	}
+}
 
-	cmd := installPluginCmd(cloner, op)
-	msg := cmd()
+// newOp returns the pending operation on p.
+func newOp(cfg *config.Config, p plug.Plugin) pendingOp {
+	return pendingOp{
+		Name:      p.Name,
+		Spec:      p.Spec,
+		Version:   p.Version,
+		LocalPath: p.LocalPath,
+		Path:      plug.PluginPath(p.Name, cfg.PluginPath),
+		Build:     p.Build,
+	}
+}
+
+// installed creates op's plugin directory and marks it as a git repo.
+func installed(t *testing.T, deps Deps, op pendingOp) {
+	t.Helper()
+	if err := os.MkdirAll(op.Path, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	deps.Validator.(*git.MockValidator).Valid[op.Path] = true
+}
+
+func TestInstallPluginCmd_Success(t *testing.T) {
+	cfg := newOpConfig(t)
+	revParser := git.NewMockRevParser()
+	revParser.Hash = "def456"
+	deps := Deps{Cloner: git.NewMockCloner(), Validator: git.NewMockValidator(), RevParser: revParser}
+	p := plug.Plugin{Name: "test-plugin", Spec: "user/test-plugin"}
 
-	result, ok := msg.(pluginInstallResultMsg)
+	result, ok := installPluginCmd(cfg, deps, []plug.Plugin{p}, newOp(cfg, p))().(pluginInstallResultMsg)
 	if !ok {
-		t.Fatalf("expected pluginInstallResultMsg, got %T", msg)
+		t.Fatal("expected pluginInstallResultMsg")
 	}
-	if !result.Success {
-		t.Errorf("expected success, got failure: %s", result.Message)
+	if !result.Success || result.Name != "test-plugin" || result.AfterRef != "def456" {
+		t.Errorf("expected a successful install at the installed commit, got %+v", result)
 	}
-	if result.Name != "test-plugin" {
-		t.Errorf("expected name test-plugin, got %s", result.Name)
+	if e, _ := loadLock(t, cfg).Get("test-plugin"); e.Commit != "def456" {
+		t.Errorf("expected the installed commit to be locked, got %+v", e)
+	}
+	entries, _ := history.Load(cfg.StatePath)
+	if len(entries) != 1 || entries[0].Action != history.Install || entries[0].After != "def456" {
+		t.Errorf("unexpected history: %+v", entries)
 	}
 }
 
 func TestInstallPluginCmd_Failure(t *testing.T) {
+	cfg := newOpConfig(t)
 	cloner := git.NewMockCloner()
 	cloner.Err = errors.New("clone failed")
-	op := pendingOp{
-		Name: "test-plugin",
-		Spec: "user/test-plugin",
-		Path: t.TempDir() + "/test-plugin/",
-	}
-
-	cmd := installPluginCmd(cloner, op)
-	msg := cmd()
+	deps := Deps{Cloner: cloner, Validator: git.NewMockValidator()}
+	p := plug.Plugin{Name: "test-plugin", Spec: "user/test-plugin"}
 
-	result, ok := msg.(pluginInstallResultMsg)
-	if !ok {
-		t.Fatalf("expected pluginInstallResultMsg, got %T", msg)
-	}
+	result, _ := installPluginCmd(cfg, deps, []plug.Plugin{p}, newOp(cfg, p))().(pluginInstallResultMsg)
 	if result.Success {
 		t.Error("expected failure, got success")
 	}
 }
 
-func TestUpdatePluginCmd_Success(t *testing.T) {
-	puller := git.NewMockPuller()
-	puller.Output = "Already up to date."
-	revParser := git.NewMockRevParser()
-	revParser.Hash = "abc123"
-	logger := git.NewMockLogger()
-	dir := t.TempDir()
-	op := pendingOp{
-		Name: "test-plugin",
-		Path: dir + "/",
+func TestInstallPluginCmd_BuildFailure(t *testing.T) {
+	cfg := newOpConfig(t)
+	p := plug.Plugin{Name: "test-plugin", Spec: "user/test-plugin", Build: "echo 'make: not found'; exit 127"}
+	op := newOp(cfg, p)
+	if err := os.MkdirAll(op.Path, 0o755); err != nil {
+		t.Fatal(err)
 	}
+	deps := Deps{Cloner: git.NewMockCloner(), Validator: git.NewMockValidator()}
 
-	cmd := updatePluginCmd(puller, revParser, logger, op)
-	msg := cmd()
+	result, _ := installPluginCmd(cfg, deps, []plug.Plugin{p}, op)().(pluginInstallResultMsg)
+	if result.Success || !strings.Contains(result.Message, "exit status 127") {
+		t.Errorf("expected the failed build to fail the install, got %+v", result)
+	}
+	if result.BuildOutput != "make: not found" {
+		t.Errorf("BuildOutput = %q", result.BuildOutput)
+	}
+	if _, err := os.Stat(op.Path); !os.IsNotExist(err) {
+		t.Errorf("expected the unbuilt plugin to be removed, stat error = %v", err)
+	}
+}
 
-	result, ok := msg.(pluginUpdateResultMsg)
-	if !ok {
-		t.Fatalf("expected pluginUpdateResultMsg, got %T", msg)
+func TestInstallPluginCmd_LockedCommit(t *testing.T) {
+	cfg := newOpConfig(t)
+	locked := "2222222222222222222222222222222222222222"
+	err := lock.Save(cfg.LockPath, &lock.File{Plugins: []lock.Entry{
+		{Name: "test-plugin", Spec: "user/test-plugin", URL: "https://github.com/user/test-plugin", Commit: locked},
+	}})
+	if err != nil {
+		t.Fatal(err)
 	}
+	checkouter := git.NewMockCheckouter()
+	deps := Deps{Cloner: git.NewMockCloner(), Validator: git.NewMockValidator(), RevParser: git.NewMockRevParser(), Checkouter: checkouter}
+	p := plug.Plugin{Name: "test-plugin", Spec: "user/test-plugin"}
+
+	result, _ := installPluginCmd(cfg, deps, []plug.Plugin{p}, newOp(cfg, p))().(pluginInstallResultMsg)
 	if !result.Success {
-		t.Errorf("expected success, got failure: %s", result.Message)
+		t.Fatalf("expected success, got %s", result.Message)
 	}
-	if result.Output != "Already up to date." {
-		t.Errorf("expected output 'Already up to date.', got %q", result.Output)
+	if len(checkouter.Calls) != 1 || checkouter.Calls[0].Ref != locked {
+		t.Errorf("expected checkout of the locked commit, got %+v", checkouter.Calls)
 	}
-	// Same hash before/after → no commits.
-	if len(result.Commits) != 0 {
-		t.Errorf("expected 0 commits when hash unchanged, got %d", len(result.Commits))
+}
+
+func TestInstallPluginCmd_Pinned(t *testing.T) {
+	cfg := newOpConfig(t)
+	cloner := git.NewMockCloner()
+	tags := git.NewMockTagLister()
+	tags.Tags["https://git::@github.com/user/test-plugin"] = []string{"v1.0.0", "v1.2.0", "v2.0.0"}
+	deps := Deps{Cloner: cloner, Validator: git.NewMockValidator(), TagLister: tags, Checkouter: git.NewMockCheckouter()}
+	p := plug.Plugin{Name: "test-plugin", Spec: "user/test-plugin", Version: "^1"}
+
+	result, _ := installPluginCmd(cfg, deps, []plug.Plugin{p}, newOp(cfg, p))().(pluginInstallResultMsg)
+	if !result.Success {
+		t.Fatalf("expected success, got %s", result.Message)
+	}
+	if len(cloner.Calls) != 1 || cloner.Calls[0].Branch != "v1.2.0" {
+		t.Errorf("expected clone of v1.2.0, got %+v", cloner.Calls)
 	}
 }
 
-func TestUpdatePluginCmd_WithCommits(t *testing.T) {
-	puller := git.NewMockPuller()
-	puller.Output = "Updating abc..def"
+func TestInstallPluginCmd_Local(t *testing.T) {
+	cfg := newOpConfig(t)
+	src := t.TempDir()
+	p := plug.Plugin{Name: "tmux-foo", Spec: "file:" + src, LocalPath: src}
+	deps := Deps{Cloner: git.NewMockCloner(), Validator: git.NewMockValidator()}
 
-	callCount := 0
-	revParser := &sequentialMockRevParser{
-		hashes: []string{"abc123", "def456"},
-		count:  &callCount,
+	result, _ := installPluginCmd(cfg, deps, []plug.Plugin{p}, newOp(cfg, p))().(pluginInstallResultMsg)
+	if !result.Success || result.Message != "linked to "+src {
+		t.Fatalf("expected the plugin to be linked, got %+v", result)
+	}
+	if !plug.IsLinked(p, cfg.PluginPath) {
+		t.Error("expected plugin directory to be linked")
 	}
+}
+
+func TestUpdatePluginCmd_WritesLock(t *testing.T) {
+	cfg := newOpConfig(t)
+	revParser := &sequentialMockRevParser{hashes: []string{"abc123", "def456"}}
 	logger := git.NewMockLogger()
 	logger.Commits = []git.Commit{
 		{Hash: "def456", Message: "add feature"},
 		{Hash: "ccc333", Message: "fix bug"},
 	}
+	deps := Deps{Puller: git.NewMockPuller(), Validator: git.NewMockValidator(), RevParser: revParser, Logger: logger}
+	p := plug.Plugin{Name: "test-plugin", Spec: "user/test-plugin"}
+	op := newOp(cfg, p)
+	installed(t, deps, op)
 
-	op := pendingOp{
-		Name: "test-plugin",
-		Path: t.TempDir() + "/",
-	}
-
-	cmd := updatePluginCmd(puller, revParser, logger, op)
-	msg := cmd()
-
-	result, ok := msg.(pluginUpdateResultMsg)
+	result, ok := updatePluginCmd(cfg, deps, []plug.Plugin{p}, op)().(pluginUpdateResultMsg)
 	if !ok {
-		t.Fatalf("expected pluginUpdateResultMsg, got %T", msg)
+		t.Fatal("expected pluginUpdateResultMsg")
 	}
-	if !result.Success {
-		t.Errorf("expected success, got failure: %s", result.Message)
+	if !result.Success || result.BeforeRef != "abc123" || result.AfterRef != "def456" || result.Dir != op.Path {
+		t.Errorf("unexpected result: %+v", result)
 	}
-	if len(result.Commits) != 2 {
-		t.Fatalf("expected 2 commits, got %d", len(result.Commits))
+	if len(result.Commits) != 2 || result.Commits[0].Hash != "def456" {
+		t.Errorf("expected the pulled commits, got %+v", result.Commits)
 	}
-	if result.Commits[0].Hash != "def456" {
-		t.Errorf("expected first commit hash def456, got %s", result.Commits[0].Hash)
+	if e, _ := loadLock(t, cfg).Get("test-plugin"); e.Commit != "def456" {
+		t.Errorf("expected the updated commit to be locked, got %+v", e)
 	}
-	if result.Dir != op.Path {
-		t.Errorf("expected Dir=%q, got %q", op.Path, result.Dir)
+	if rev, _ := state.Load(cfg.StatePath).LastRevision("test-plugin"); rev != "abc123" {
+		t.Errorf("expected revision abc123 to be recorded, got %q", rev)
 	}
-	if result.BeforeRef != "abc123" {
-		t.Errorf("expected BeforeRef=abc123, got %q", result.BeforeRef)
+	entries, _ := history.Load(cfg.StatePath)
+	if len(entries) != 1 || entries[0].Before != "abc123" || entries[0].After != "def456" || entries[0].Commits != 2 {
+		t.Errorf("unexpected history: %+v", entries)
+	}
+}
+
+func TestUpdatePluginCmd_KeepsOtherLockEntries(t *testing.T) {
+	cfg := newOpConfig(t)
+	deps := Deps{Puller: git.NewMockPuller(), Validator: git.NewMockValidator(), RevParser: git.NewMockRevParser()}
+	plugins := []plug.Plugin{{Name: "a", Spec: "user/a"}, {Name: "b", Spec: "user/b"}}
+	for _, p := range plugins {
+		installed(t, deps, newOp(cfg, p))
 	}
-	if result.AfterRef != "def456" {
-		t.Errorf("expected AfterRef=def456, got %q", result.AfterRef)
+
+	// Each update is saved without losing the entry the other one wrote.
+	updatePluginCmd(cfg, deps, plugins, newOp(cfg, plugins[0]))()
+	updatePluginCmd(cfg, deps, plugins, newOp(cfg, plugins[1]))()
+
+	lf := loadLock(t, cfg)
+	for _, p := range plugins {
+		if _, ok := lf.Get(p.Name); !ok {
+			t.Errorf("expected %s to be locked, got %+v", p.Name, lf.Plugins)
+		}
 	}
 }
 
-func TestUpdatePluginCmd_NilRevParser(t *testing.T) {
-	puller := git.NewMockPuller()
-	puller.Output = "Already up to date."
+func TestUpdatePluginCmd_Build(t *testing.T) {
+	cfg := newOpConfig(t)
+	deps := Deps{Puller: git.NewMockPuller(), Validator: git.NewMockValidator()}
+	p := plug.Plugin{Name: "test-plugin", Spec: "user/test-plugin", Build: "echo built"}
+	op := newOp(cfg, p)
+	installed(t, deps, op)
 
-	op := pendingOp{
-		Name: "test-plugin",
-		Path: t.TempDir() + "/",
+	result, _ := updatePluginCmd(cfg, deps, []plug.Plugin{p}, op)().(pluginUpdateResultMsg)
+	if !result.Success || result.BuildOutput != "built" {
+		t.Errorf("result = %+v", result)
 	}
+}
 
-	cmd := updatePluginCmd(puller, nil, nil, op)
-	msg := cmd()
+func TestUpdatePluginCmd_BuildSkippedWhenUnchanged(t *testing.T) {
+	cfg := newOpConfig(t)
+	deps := Deps{Puller: git.NewMockPuller(), Validator: git.NewMockValidator(), RevParser: git.NewMockRevParser()}
+	p := plug.Plugin{Name: "test-plugin", Spec: "user/test-plugin", Build: "echo built"}
+	op := newOp(cfg, p)
+	installed(t, deps, op)
 
-	result, ok := msg.(pluginUpdateResultMsg)
-	if !ok {
-		t.Fatalf("expected pluginUpdateResultMsg, got %T", msg)
+	result, _ := updatePluginCmd(cfg, deps, []plug.Plugin{p}, op)().(pluginUpdateResultMsg)
+	if !result.Success || result.BuildOutput != "" {
+		t.Errorf("expected no build for an update that didn't move, got %+v", result)
 	}
-	if !result.Success {
-		t.Errorf("expected success, got failure: %s", result.Message)
+
+	// A failed build is retried even when the update doesn't move.
+	if err := state.SetBuildFailed(cfg.StatePath, op.Name, true); err != nil {
+		t.Fatal(err)
+	}
+	result, _ = updatePluginCmd(cfg, deps, []plug.Plugin{p}, op)().(pluginUpdateResultMsg)
+	if result.BuildOutput != "built" {
+		t.Errorf("expected the failed build to be retried, got %+v", result)
 	}
-	if len(result.Commits) != 0 {
-		t.Errorf("expected 0 commits with nil revParser, got %d", len(result.Commits))
+	if state.Load(cfg.StatePath).BuildFailed(op.Name) {
+		t.Error("expected the successful build to clear the failure")
 	}
 }
 
-func TestUpdatePluginCmd_Failure(t *testing.T) {
+func TestUpdatePluginCmd_RollsBackOnFailure(t *testing.T) {
+	cfg := newOpConfig(t)
 	puller := git.NewMockPuller()
 	puller.Err = errors.New("pull failed")
-	op := pendingOp{
-		Name: "test-plugin",
-		Path: t.TempDir() + "/",
+	checkouter := git.NewMockCheckouter()
+	deps := Deps{Puller: puller, Validator: git.NewMockValidator(), RevParser: git.NewMockRevParser(), Checkouter: checkouter}
+	p := plug.Plugin{Name: "test-plugin", Spec: "user/test-plugin"}
+	op := newOp(cfg, p)
+	installed(t, deps, op)
+
+	result, _ := updatePluginCmd(cfg, deps, []plug.Plugin{p}, op)().(pluginUpdateResultMsg)
+	if result.Success {
+		t.Error("expected failure, got success")
 	}
+	if len(checkouter.Calls) != 1 || checkouter.Calls[0].Ref != "abc123" {
+		t.Errorf("expected rollback to abc123, got %+v", checkouter.Calls)
+	}
+	if !strings.Contains(result.Output, "rolled back to abc123") {
+		t.Errorf("expected rollback in output, got %q", result.Output)
+	}
+}
 
-	cmd := updatePluginCmd(puller, nil, nil, op)
-	msg := cmd()
+func TestUpdatePluginCmd_NotInstalled(t *testing.T) {
+	cfg := newOpConfig(t)
+	deps := Deps{Puller: git.NewMockPuller(), Validator: git.NewMockValidator()}
+	p := plug.Plugin{Name: "test-plugin", Spec: "user/test-plugin"}
 
-	result, ok := msg.(pluginUpdateResultMsg)
-	if !ok {
-		t.Fatalf("expected pluginUpdateResultMsg, got %T", msg)
+	result, _ := updatePluginCmd(cfg, deps, []plug.Plugin{p}, newOp(cfg, p))().(pluginUpdateResultMsg)
+	if result.Success || result.Message != "not installed" {
+		t.Errorf("expected the update to fail, got %+v", result)
 	}
-	if result.Success {
-		t.Error("expected failure, got success")
+}
+
+func TestUpdatePluginCmd_Pinned(t *testing.T) {
+	cfg := newOpConfig(t)
+	tags := git.NewMockTagLister()
+	tags.Tags["https://git::@github.com/user/test-plugin"] = []string{"v1.0.0", "v1.2.0", "v2.0.0"}
+	checkouter := git.NewMockCheckouter()
+	puller := git.NewMockPuller()
+	deps := Deps{Puller: puller, Validator: git.NewMockValidator(), TagLister: tags, Checkouter: checkouter}
+	p := plug.Plugin{Name: "test-plugin", Spec: "user/test-plugin", Version: "^1"}
+	op := newOp(cfg, p)
+	installed(t, deps, op)
+
+	result, _ := updatePluginCmd(cfg, deps, []plug.Plugin{p}, op)().(pluginUpdateResultMsg)
+	if !result.Success {
+		t.Fatalf("expected success, got %s", result.Message)
+	}
+	if len(checkouter.Calls) != 1 || checkouter.Calls[0].Ref != "v1.2.0" {
+		t.Errorf("expected checkout of v1.2.0, got %+v", checkouter.Calls)
+	}
+	if len(puller.Calls) != 0 {
+		t.Error("pinned plugins should not be pulled")
 	}
 }
 
-// sequentialMockRevParser returns different hashes on sequential calls.
+func TestUpdatePluginCmd_CommitPinSkipped(t *testing.T) {
+	cfg := newOpConfig(t)
+	checkouter := git.NewMockCheckouter()
+	deps := Deps{Puller: git.NewMockPuller(), Validator: git.NewMockValidator(), TagLister: git.NewMockTagLister(), Checkouter: checkouter}
+	p := plug.Plugin{Name: "test-plugin", Spec: "user/test-plugin", Version: "3f2a9c1"}
+	op := newOp(cfg, p)
+	installed(t, deps, op)
+
+	result, _ := updatePluginCmd(cfg, deps, []plug.Plugin{p}, op)().(pluginUpdateResultMsg)
+	if !result.Success || result.Message != "skipped" || len(checkouter.Calls) != 0 {
+		t.Errorf("expected commit pin to be skipped, got %+v, checkouts %+v", result, checkouter.Calls)
+	}
+}
+
+// sequentialMockRevParser returns its hashes in turn, then the last one.
 type sequentialMockRevParser struct {
 	hashes []string
-	count  *int
+	count  int
 }
 
 func (s *sequentialMockRevParser) RevParse(_ context.Context, _ string) (string, error) {
-	idx := *s.count
-	*s.count++
-	if idx < len(s.hashes) {
-		return s.hashes[idx], nil
+	idx := min(s.count, len(s.hashes)-1)
+	s.count++
+	return s.hashes[idx], nil
+}
+
+// loadLock reads cfg's lockfile.
+func loadLock(t *testing.T, cfg *config.Config) *lock.File {
+	t.Helper()
+	lf, err := lock.Load(cfg.LockPath)
+	if err != nil {
+		t.Fatal(err)
 	}
-	return "unknown", nil
+	return lf
 }
 
 func TestCleanPluginCmd_Success(t *testing.T) {
@@ -404,6 +559,20 @@ func TestBuildUpdateOps_AllInstalled(t *testing.T) {
 	}
 }
 
+func TestBuildUpdateOps_SkipsLocal(t *testing.T) {
+	m := newTestModel(t, nil)
+	m.plugins = []PluginItem{
+		{Name: "a", Spec: "file:/src/a", LocalPath: "/src/a", Status: StatusLocal},
+		{Name: "b", Spec: "user/b", Status: StatusInstalled},
+	}
+	m.listScroll.cursor = 0
+
+	ops := m.buildUpdateOps()
+	if len(ops) != 1 || ops[0].Name != "b" {
+		t.Errorf("expected only the remote plugin to be updated, got %+v", ops)
+	}
+}
+
 func TestDispatchNext_EmptyQueue(t *testing.T) {
 	m := newTestModel(t, nil)
 	m.pendingItems = nil
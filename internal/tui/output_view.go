@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/tmuxpack/tpack/internal/sourcelog"
+)
+
+// outputReservedLines is the overhead for title, help, and padding in the
+// source output screen.
+const outputReservedLines = 9
+
+// enterOutput loads the source log of the plugin under the cursor and
+// switches to the source output screen.
+func (m Model) enterOutput() (tea.Model, tea.Cmd) {
+	m.outputLines = nil
+	m.outputErr = nil
+	if m.cfg.StatePath != "" {
+		log, err := sourcelog.Read(m.cfg.StatePath, m.plugins[m.listScroll.cursor].Name)
+		if log != "" {
+			m.outputLines = strings.Split(strings.TrimRight(log, "\n"), "\n")
+		}
+		m.outputErr = err
+	}
+	m.outputOffset = 0
+	m.screen = ScreenOutput
+	return m, nil
+}
+
+// handleKeyMsgOutput handles key events on the source output screen.
+func (m Model) handleKeyMsgOutput(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, SharedKeys.Quit):
+		return m, tea.Quit
+	case msg.String() == escKeyName:
+		m.screen = ScreenDetail
+	case key.Matches(msg, ListKeys.Up):
+		if m.outputOffset > 0 {
+			m.outputOffset--
+		}
+	case key.Matches(msg, ListKeys.Down):
+		if m.outputOffset < len(m.outputLines)-m.outputMaxVisible() {
+			m.outputOffset++
+		}
+	}
+	return m, nil
+}
+
+// outputMaxVisible returns the number of log lines that fit in the current height.
+func (m *Model) outputMaxVisible() int {
+	v := m.height - outputReservedLines
+	if v < MinViewHeight {
+		return MinViewHeight
+	}
+	return v
+}
+
+// viewOutput renders what the plugin under the cursor wrote to stderr the
+// last time it was sourced.
+func (m *Model) viewOutput() string {
+	var b strings.Builder
+
+	name := m.plugins[m.listScroll.cursor].Name
+	b.WriteString(m.centerText(m.theme.TitleStyle.Render("  " + name + " output  ")))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.outputErr != nil:
+		b.WriteString("  " + m.theme.ErrorStyle.Render("Failed to read source log: "+m.outputErr.Error()) + "\n")
+	case len(m.outputLines) == 0:
+		b.WriteString("  " + m.theme.MutedTextStyle.Render("No output: the plugin's scripts succeeded quietly the last time it was sourced.") + "\n")
+	default:
+		m.renderOutputLines(&b)
+	}
+
+	help := m.centerText(m.theme.renderHelp(m.width, SharedKeys.Back, SharedKeys.Quit))
+	return padToBottom(b.String(), help, m.height)
+}
+
+// renderOutputLines writes the visible log lines into b, highlighting the
+// header of each script.
+func (m *Model) renderOutputLines(b *strings.Builder) {
+	lines := m.outputLines
+	visible := min(len(lines), m.outputMaxVisible())
+	end := min(m.outputOffset+visible, len(lines))
+	top, bottom, dataStart, dataEnd := m.theme.renderScrollIndicators(m.outputOffset, end, len(lines))
+	b.WriteString(top)
+
+	width := max(m.width-4, MinViewHeight)
+	for _, line := range lines[dataStart:dataEnd] {
+		line = truncateLine(strings.ReplaceAll(line, "\t", "    "), width)
+		switch {
+		case strings.HasPrefix(line, "==> "):
+			line = m.theme.HelpKeyStyle.Render(line)
+		case strings.HasPrefix(line, "Sourced "):
+			line = m.theme.MutedTextStyle.Render(line)
+		}
+		b.WriteString("  " + line + "\n")
+	}
+
+	b.WriteString(bottom)
+}
+
+// truncateLine cuts s to at most width runes, marking the cut with "…".
+func truncateLine(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
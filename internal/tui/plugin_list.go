@@ -58,6 +58,9 @@ func (m *Model) viewList() string {
 			}
 
 			status := m.renderStatus(p.Status)
+			if p.Disabled {
+				status = m.theme.MutedTextStyle.Render("Disabled")
+			}
 
 			row := fmt.Sprintf("%s%s%-*s  %s", cursor, checkbox, m.nameColWidth(), p.Name, status)
 
@@ -65,6 +68,8 @@ func (m *Model) viewList() string {
 				row = m.theme.SelectedRowStyle.Render(row)
 			} else if m.selected[i] {
 				row = m.theme.CheckedStyle.Render(row)
+			} else if p.Disabled {
+				row = m.theme.MutedTextStyle.Render(row)
 			}
 
 			tb.WriteString(row)
@@ -89,6 +94,12 @@ func (m *Model) viewList() string {
 		b.WriteString("\n")
 	}
 
+	if m.listStatus != "" {
+		b.WriteString("\n")
+		b.WriteString(m.centerText(m.theme.ErrorStyle.Render(m.listStatus)))
+		b.WriteString("\n")
+	}
+
 	// Help bar — context-aware actions, pinned to bottom.
 	var bindings []key.Binding
 	hasNotInstalled, hasInstalled := m.targetHasStatus()
@@ -104,7 +115,13 @@ func (m *Model) viewList() string {
 	if len(m.orphans) > 0 {
 		bindings = append(bindings, ListKeys.Clean)
 	}
-	bindings = append(bindings, ListKeys.Browse)
+	if len(m.plugins) > 0 {
+		bindings = append(bindings, ListKeys.Details)
+	}
+	if toggle, ok := m.disableBinding(); ok {
+		bindings = append(bindings, toggle)
+	}
+	bindings = append(bindings, ListKeys.Browse, ListKeys.History, ListKeys.Startup)
 	bindings = append(bindings, SharedKeys.Quit)
 	help := m.centerText(m.theme.renderHelp(m.width, bindings...))
 
@@ -157,7 +174,7 @@ func (m *Model) statusSummary() string {
 	outdated := 0
 	for _, p := range m.plugins {
 		switch p.Status {
-		case StatusInstalled, StatusChecking, StatusCheckFailed:
+		case StatusInstalled, StatusChecking, StatusCheckFailed, StatusLocal:
 			installed++
 		case StatusNotInstalled:
 			notInstalled++
@@ -185,6 +202,8 @@ func (m *Model) renderStatus(s PluginStatus) string {
 		return m.theme.StatusOutdatedStyle.Render("Outdated")
 	case StatusCheckFailed:
 		return m.theme.StatusInstalledStyle.Render("Installed") + " " + m.theme.StatusCheckFailedStyle.Render("⚠")
+	case StatusLocal:
+		return m.theme.StatusInstalledStyle.Render("Local")
 	default:
 		return ""
 	}
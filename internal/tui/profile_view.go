@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/tmuxpack/tpack/internal/profile"
+)
+
+// profileReservedLines is the overhead for title, summary, help, and padding
+// in the startup profile screen.
+const profileReservedLines = 11
+
+// enterProfile loads the last startup profile and switches to the startup screen.
+func (m Model) enterProfile() (tea.Model, tea.Cmd) {
+	m.profileRun = nil
+	m.profileErr = nil
+	if m.cfg.StatePath != "" {
+		m.profileRun, m.profileErr = profile.Load(m.cfg.StatePath)
+	}
+	m.profileScroll.reset()
+	m.screen = ScreenProfile
+	return m, nil
+}
+
+// handleKeyMsgProfile handles key events on the startup profile screen.
+func (m Model) handleKeyMsgProfile(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, SharedKeys.Quit):
+		return m, tea.Quit
+	case msg.String() == escKeyName:
+		m.screen = ScreenList
+	case key.Matches(msg, ListKeys.Up):
+		m.profileScroll.moveUp()
+	case key.Matches(msg, ListKeys.Down):
+		if m.profileRun != nil {
+			m.profileScroll.moveDown(len(m.profileRun.Plugins()), m.profileMaxVisible())
+		}
+	}
+	return m, nil
+}
+
+// profileMaxVisible returns the number of plugin rows that fit in the current height.
+func (m *Model) profileMaxVisible() int {
+	v := m.height - profileReservedLines
+	if v < MinViewHeight {
+		return MinViewHeight
+	}
+	return v
+}
+
+// viewProfile renders the startup profile screen, slowest plugin first.
+func (m *Model) viewProfile() string {
+	var b strings.Builder
+
+	b.WriteString(m.centerText(m.theme.TitleStyle.Render("  Startup  ")))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.profileErr != nil:
+		b.WriteString("  " + m.theme.ErrorStyle.Render("Failed to read profile: "+m.profileErr.Error()) + "\n")
+	case m.profileRun == nil || len(m.profileRun.Scripts) == 0:
+		b.WriteString("  " + m.theme.MutedTextStyle.Render("No profile recorded yet. Run `tpack source --profile`.") + "\n")
+	default:
+		r := m.profileRun
+		summary := fmt.Sprintf("Profiled %s, %d scripts in %s",
+			r.Time.Local().Format("2006-01-02 15:04"), len(r.Scripts), profileDuration(r.Total))
+		if r.Parallel {
+			summary += " (parallel)"
+		}
+		b.WriteString("  " + m.theme.MutedTextStyle.Render(summary) + "\n\n")
+		m.renderProfileList(&b)
+	}
+
+	help := m.centerText(m.theme.renderHelp(m.width, SharedKeys.Back, SharedKeys.Quit))
+	return padToBottom(b.String(), help, m.height)
+}
+
+// renderProfileList writes the scrollable per-plugin rows into b.
+func (m *Model) renderProfileList(b *strings.Builder) {
+	plugins := m.profileRun.Plugins()
+	nameWidth := 0
+	for _, p := range plugins {
+		nameWidth = max(nameWidth, len(p.Name))
+	}
+
+	visible := min(len(plugins), m.profileMaxVisible())
+	end := min(m.profileScroll.scrollOffset+visible, len(plugins))
+	top, bottom, dataStart, dataEnd := m.theme.renderScrollIndicators(m.profileScroll.scrollOffset, end, len(plugins))
+	b.WriteString(top)
+
+	for i := dataStart; i < dataEnd; i++ {
+		p := plugins[i]
+		cursor := "  "
+		if i == m.profileScroll.cursor {
+			cursor = "> "
+		}
+		row := fmt.Sprintf("%8s  %-*s", profileDuration(p.Duration), nameWidth, p.Name)
+		if p.Scripts > 1 {
+			row += m.theme.MutedTextStyle.Render(fmt.Sprintf("  %d scripts", p.Scripts))
+		}
+		if p.Failed > 0 {
+			row = m.theme.ErrorStyle.Render("✗") + " " + row + m.theme.ErrorStyle.Render(fmt.Sprintf("  %d failed", p.Failed))
+		} else {
+			row = m.theme.SuccessStyle.Render("✓") + " " + row
+		}
+		b.WriteString(cursor + row + "\n")
+	}
+
+	b.WriteString(bottom)
+}
+
+// profileDuration formats d for the startup screen, to the millisecond.
+func profileDuration(d time.Duration) string {
+	if d >= time.Second {
+		return d.Round(10 * time.Millisecond).String()
+	}
+	return d.Round(100 * time.Microsecond).String()
+}
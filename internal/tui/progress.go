@@ -56,6 +56,10 @@ func (m *Model) viewProgress() string {
 		if len(visible) > 0 {
 			b.WriteString("\n\n")
 			b.WriteString(m.centerBlock(m.renderResults()))
+			if out := visible[m.resultScroll.cursor].BuildOutput; out != "" {
+				b.WriteString("\n\n")
+				b.WriteString(m.centerBlock(m.renderBuildOutput(out)))
+			}
 		}
 
 		var bindings []key.Binding
@@ -122,6 +126,31 @@ func (m *Model) renderResults() string {
 	return strings.TrimRight(rb.String(), "\n")
 }
 
+// renderBuildOutput renders the last lines of a plugin's build output.
+func (m *Model) renderBuildOutput(out string) string {
+	lines := strings.Split(out, "\n")
+	lines = lines[max(len(lines)-buildOutputLines, 0):]
+	width := max(m.width-BaseStylePadding-4, MinViewHeight)
+
+	var b strings.Builder
+	b.WriteString(m.theme.SubtitleStyle.Render("Build output:"))
+	for _, line := range lines {
+		line = truncateLine(strings.ReplaceAll(line, "\t", "    "), width)
+		b.WriteString("\n  " + m.theme.MutedTextStyle.Render(line))
+	}
+	return b.String()
+}
+
+// hasBuildOutput reports whether any of results has build output to show.
+func hasBuildOutput(results []ResultItem) bool {
+	for _, r := range results {
+		if r.BuildOutput != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // renderStats returns the summary stats string for the progress screen.
 func (m *Model) renderStats() string {
 	if m.operation == OpUpdate {
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// JSONOutput writes newline-delimited JSON events for scripting. Each
+// plugin result is one {"type":"result",...} object; Err messages become
+// {"type":"error","message":...} objects. Ok messages are narration for
+// humans and are dropped.
+type JSONOutput struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	failed atomic.Bool
+}
+
+// jsonEvent is a single line of JSON output.
+type jsonEvent struct {
+	Type    string `json:"type"`
+	Message string `json:"message,omitempty"`
+	*Result
+}
+
+// NewJSONOutput returns a JSONOutput writing to os.Stdout.
+func NewJSONOutput() *JSONOutput {
+	return NewJSONOutputWithWriter(os.Stdout)
+}
+
+// NewJSONOutputWithWriter creates a JSONOutput with a custom writer (for testing).
+func NewJSONOutputWithWriter(w io.Writer) *JSONOutput {
+	return &JSONOutput{enc: json.NewEncoder(w)}
+}
+
+func (j *JSONOutput) Ok(string) {}
+
+func (j *JSONOutput) Err(msg string) {
+	j.failed.Store(true)
+	j.write(jsonEvent{Type: "error", Message: msg})
+}
+
+// Result writes r as a result event. Failed results mark the output as failed.
+func (j *JSONOutput) Result(r Result) {
+	if r.Status == StatusFailed {
+		j.failed.Store(true)
+	}
+	j.write(jsonEvent{Type: "result", Result: &r})
+}
+
+func (j *JSONOutput) EndMessage() {
+	// JSON output has no interactive end message.
+}
+
+func (j *JSONOutput) HasFailed() bool {
+	return j.failed.Load()
+}
+
+func (j *JSONOutput) write(e jsonEvent) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_ = j.enc.Encode(e)
+}
@@ -7,6 +7,7 @@ type MockOutput struct {
 	mu       sync.Mutex
 	OkMsgs   []string
 	ErrMsgs  []string
+	Results  []Result
 	EndCalls int
 	failed   bool
 }
@@ -29,6 +30,12 @@ func (m *MockOutput) Err(msg string) {
 	m.ErrMsgs = append(m.ErrMsgs, msg)
 }
 
+func (m *MockOutput) Result(r Result) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Results = append(m.Results, r)
+}
+
 func (m *MockOutput) EndMessage() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -0,0 +1,38 @@
+package ui
+
+// Status is the outcome of an operation on a single plugin.
+type Status string
+
+const (
+	StatusOK        Status = "ok"
+	StatusUnchanged Status = "unchanged"
+	StatusSkipped   Status = "skipped"
+	StatusOutdated  Status = "outdated"
+	StatusFailed    Status = "failed"
+)
+
+// Result describes the outcome of one action on one plugin.
+type Result struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	Status Status `json:"status"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Output is what a build command printed, for build results.
+	Output string `json:"output,omitempty"`
+}
+
+// Reporter is implemented by outputs that accept structured per-plugin
+// results in addition to free-form messages.
+type Reporter interface {
+	Result(r Result)
+}
+
+// Report passes r to out if it accepts structured results. Text outputs
+// ignore results; their Ok/Err messages already describe the outcome.
+func Report(out Output, r Result) {
+	if rep, ok := out.(Reporter); ok {
+		rep.Result(r)
+	}
+}
@@ -150,3 +150,38 @@ func TestMockOutput(t *testing.T) {
 		t.Error("HasFailed should be true")
 	}
 }
+
+func TestJSONOutput(t *testing.T) {
+	var buf bytes.Buffer
+	out := ui.NewJSONOutputWithWriter(&buf)
+
+	out.Ok("Installing \"tmux-yank\"")
+	out.Result(ui.Result{Name: "tmux-yank", Action: "install", Status: ui.StatusOK, After: "abc123"})
+	if out.HasFailed() {
+		t.Fatal("HasFailed should be false after a successful result")
+	}
+	out.Result(ui.Result{Name: "tmux-sensible", Action: "update", Status: ui.StatusFailed, Error: "pull failed"})
+	out.Err("plugin directory is not writable")
+
+	want := `{"type":"result","name":"tmux-yank","action":"install","status":"ok","after":"abc123"}
+{"type":"result","name":"tmux-sensible","action":"update","status":"failed","error":"pull failed"}
+{"type":"error","message":"plugin directory is not writable"}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+	if !out.HasFailed() {
+		t.Error("HasFailed should be true")
+	}
+}
+
+func TestReportIgnoresTextOutputs(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	out := ui.NewShellOutputWithWriters(&stdout, &stderr)
+
+	ui.Report(out, ui.Result{Name: "x", Action: "install", Status: ui.StatusFailed})
+
+	if stdout.Len() != 0 || stderr.Len() != 0 || out.HasFailed() {
+		t.Errorf("text output should ignore results, got stdout=%q stderr=%q", stdout.String(), stderr.String())
+	}
+}
@@ -155,6 +155,10 @@ func (n *noopRunner) ShowOption(string) (string, error)       { return "", nil }
 func (n *noopRunner) ShowEnvironment(string) (string, error)  { return "", nil }
 func (n *noopRunner) SetEnvironment(string, string) error     { return nil }
 func (n *noopRunner) BindKey(string, string, string) error    { return nil }
+func (n *noopRunner) SetHook(string, string) error            { return nil }
+func (n *noopRunner) UnsetHook(string) error                  { return nil }
+func (n *noopRunner) UnbindKey(string) error                  { return nil }
+func (n *noopRunner) ShowHooks(string) (string, error)        { return "", nil }
 func (n *noopRunner) SourceFile(string) error                 { return nil }
 func (n *noopRunner) DisplayMessage(string) error             { return nil }
 func (n *noopRunner) RunShell(string) error                   { return nil }
@@ -163,3 +167,5 @@ func (n *noopRunner) Version() (string, error)                { return "tmux 3.4
 func (n *noopRunner) StartServer() error                      { return nil }
 func (n *noopRunner) ShowWindowOption(string) (string, error) { return "", nil }
 func (n *noopRunner) SetOption(string, string) error          { return nil }
+func (n *noopRunner) ListKeys(string) (string, error)         { return "", nil }
+func (n *noopRunner) DisplayFormat(string) (string, error)    { return "", nil }